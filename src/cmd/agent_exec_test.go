@@ -54,6 +54,7 @@ func TestAgentExec_ContextFileMissing(t *testing.T) {
 	}
 }
 
+// Verifies: SR-020
 func TestAgentExec_HappyPath(t *testing.T) {
 	dir := t.TempDir()
 	// Adapter that prints OK and exits 0
@@ -75,6 +76,7 @@ func TestAgentExec_HappyPath(t *testing.T) {
 	}
 }
 
+// Verifies: SR-020
 func TestAgentExec_AdapterFailureSurfaceStderr(t *testing.T) {
 	dir := t.TempDir()
 	// Adapter that writes to stderr and exits non-zero
@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// commandSpec is the machine-readable description of a command and its flags.
+type commandSpec struct {
+	Path     string        `json:"path"`
+	Short    string        `json:"short,omitempty"`
+	Flags    []flagSpec    `json:"flags"`
+	Commands []commandSpec `json:"commands,omitempty"`
+}
+
+type flagSpec struct {
+	Name      string `json:"name"`
+	Shorthand string `json:"shorthand,omitempty"`
+	Type      string `json:"type,omitempty"`
+	Default   string `json:"default,omitempty"`
+	Usage     string `json:"usage,omitempty"`
+}
+
+// cliSpec describes the command tree under c. Commands that parse their own
+// flags are asked for their usage; the others report their Cobra flags,
+// including persistent flags inherited from the root.
+func cliSpec(c *cobra.Command) commandSpec {
+	spec := commandSpec{Path: c.CommandPath(), Short: c.Short, Flags: []flagSpec{}}
+	if c.DisableFlagParsing && c.RunE != nil {
+		spec.Flags = append(spec.Flags, ownFlags(c)...)
+	} else {
+		add := func(f *pflag.Flag) {
+			spec.Flags = append(spec.Flags, flagSpec{Name: f.Name, Shorthand: f.Shorthand, Type: f.Value.Type(), Default: f.DefValue, Usage: f.Usage})
+		}
+		c.LocalFlags().VisitAll(add)
+		c.InheritedFlags().VisitAll(add)
+	}
+	sort.Slice(spec.Flags, func(i, j int) bool { return spec.Flags[i].Name < spec.Flags[j].Name })
+	subs := c.Commands()
+	sort.Slice(subs, func(i, j int) bool { return subs[i].Name() < subs[j].Name() })
+	for _, sub := range subs {
+		spec.Commands = append(spec.Commands, cliSpec(sub))
+	}
+	return spec
+}
+
+// commandTree flattens a spec to command paths ("tgs", "tgs verify ears") and
+// the flag names (and shorthands) each accepts.
+func commandTree(root *cobra.Command) map[string][]string {
+	tree := map[string][]string{}
+	var walk func(s commandSpec)
+	walk = func(s commandSpec) {
+		flags := []string{}
+		for _, f := range s.Flags {
+			flags = append(flags, f.Name)
+			if f.Shorthand != "" {
+				flags = append(flags, f.Shorthand)
+			}
+		}
+		tree[s.Path] = flags
+		for _, sub := range s.Commands {
+			walk(sub)
+		}
+	}
+	walk(cliSpec(root))
+	return tree
+}
+
+var (
+	// flag.PrintDefaults: "  -name type\n    \tusage (default x)"
+	usageFlagRe    = regexp.MustCompile(`(?m)^  -{1,2}([A-Za-z0-9][A-Za-z0-9-]*)(?: ([^\n\t]+))?(?:\n[ \t]*\t|\t)(.*)$`)
+	usageDefaultRe = regexp.MustCompile(` \(default (.*)\)$`)
+	stderrMu       sync.Mutex
+)
+
+// ownFlags runs a self-parsing command with -h and reads its flags from the
+// usage it prints to stderr.
+func ownFlags(c *cobra.Command) []flagSpec {
+	stderrMu.Lock()
+	defer stderrMu.Unlock()
+	r, w, err := os.Pipe()
+	if err != nil {
+		return nil
+	}
+	old := os.Stderr
+	os.Stderr = w
+	done := make(chan string)
+	go func() {
+		b, _ := io.ReadAll(r)
+		done <- string(b)
+	}()
+	_ = c.RunE(c, []string{"-h"})
+	w.Close()
+	os.Stderr = old
+	usage := <-done
+	var out []flagSpec
+	for _, m := range usageFlagRe.FindAllStringSubmatch(usage, -1) {
+		f := flagSpec{Name: m[1], Type: m[2], Usage: strings.TrimSpace(m[3])}
+		if d := usageDefaultRe.FindStringSubmatch(f.Usage); d != nil {
+			f.Default = strings.Trim(d[1], `"`)
+			f.Usage = strings.TrimSuffix(f.Usage, d[0])
+		}
+		switch {
+		case f.Type == "":
+			f.Type = "bool"
+		case strings.Contains(f.Type, " "):
+			f.Type = "value" // a `quoted` name from the usage, e.g. "go test -json"
+		}
+		out = append(out, f)
+	}
+	return out
+}
@@ -4,7 +4,9 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -13,6 +15,7 @@ import (
 	"github.com/kelvin/tgsflow/src/core/brain"
 	"github.com/kelvin/tgsflow/src/core/config"
 	"github.com/kelvin/tgsflow/src/core/thoughts"
+	"github.com/kelvin/tgsflow/src/core/toolpack"
 	"github.com/spf13/cobra"
 )
 
@@ -32,6 +35,7 @@ func newContextPackCommand() *cobra.Command {
 	var (
 		flagOut     string
 		flagVerbose bool
+		flagBase    string
 	)
 
 	cmd := &cobra.Command{
@@ -69,35 +73,15 @@ func newContextPackCommand() *cobra.Command {
 			}
 
 			// Gather candidate context files from design and active thought
-			designDir := cfg.Context.PackDir
-			if strings.TrimSpace(designDir) == "" {
-				designDir = filepath.Join("tgs", "design")
-			}
-			designDir = filepath.Clean(designDir)
-			if !filepath.IsAbs(designDir) {
-				designDir = filepath.Join(repoRoot, designDir)
-			}
-
-			candidateGlobs := []string{
-				filepath.Join(designDir, "*.md"),
-			}
-			thoughtFiles := []string{
-				"README.md", "research.md", "plan.md", "implementation.md",
-			}
-			for _, spec := range thoughts.SpecFileCandidates() {
-				thoughtFiles = append(thoughtFiles, spec)
-			}
-
-			var ctxFiles []string
-			for _, g := range candidateGlobs {
-				matches, _ := filepath.Glob(g)
-				ctxFiles = append(ctxFiles, matches...)
-			}
-			for _, f := range thoughtFiles {
-				p := filepath.Join(activeThought, f)
-				if st, err := os.Stat(p); err == nil && !st.IsDir() {
-					ctxFiles = append(ctxFiles, p)
+			designDir := toolpack.DesignDir(repoRoot, cfg)
+			ctxFiles := toolpack.ContextFiles(repoRoot, cfg)
+			// Pull in thoughts and tests linked to the requirements a diff affects
+			if strings.TrimSpace(flagBase) != "" {
+				rep, code := traceImpact(repoRoot, flagBase, nil)
+				if code != 0 {
+					return exitCodeError{code: code}
 				}
+				ctxFiles = append(ctxFiles, impactContextFiles(repoRoot, cfg, rep)...)
 			}
 			// De-duplicate preserving order
 			seen := make(map[string]struct{}, len(ctxFiles))
@@ -123,6 +107,12 @@ func newContextPackCommand() *cobra.Command {
 			prompt = strings.ReplaceAll(prompt, "{{TOKEN_BUDGET}}", fmt.Sprintf("%d", budget))
 			prompt = strings.ReplaceAll(prompt, "{{BRIEF_TEMPLATE}}", briefTemplate)
 
+			// Non-shell transports search the repo themselves through the toolpack;
+			// without tool access the adapter packs the listed files in one shot
+			if cfg.AI.Mode != "" && cfg.AI.Mode != "shell" && brain.ToolsAllowed(cfg, "context_pack") {
+				return packWithAgent(repoRoot, cfg, prompt, finalCtx, outPath, flagVerbose)
+			}
+
 			// Prepare adapter exec (reuse adapter contract)
 			adapterPath := cfg.AI.ShellAdapterPath
 			if strings.TrimSpace(adapterPath) == "" {
@@ -177,9 +167,65 @@ func newContextPackCommand() *cobra.Command {
 
 	cmd.Flags().StringVar(&flagOut, "out", "", "Output path for brief (default: <active-thought>/aibrief.md)")
 	cmd.Flags().BoolVar(&flagVerbose, "verbose", false, "Verbose logs")
+	cmd.Flags().StringVar(&flagBase, "base", "", "Also pack thoughts and tests linked to requirements affected by changes since this git ref")
 	return cmd
 }
 
+// packWithAgent runs the context_pack task as a tool loop over the toolpack
+// and writes the model's final brief to outPath.
+func packWithAgent(repoRoot string, cfg config.Config, prompt string, ctxFiles []string, outPath string, verbose bool) error {
+	env, err := toolpack.NewEnv(repoRoot, cfg)
+	if err != nil {
+		return err
+	}
+	tr, err := brain.NewTransport(cfg)
+	if err != nil {
+		return err
+	}
+	if c, ok := tr.(io.Closer); ok {
+		defer c.Close()
+	}
+	agent, err := brain.NewAgent(tr, env, "context_pack")
+	if err != nil {
+		return err
+	}
+	if verbose {
+		agent.Logf = func(format string, args ...any) {
+			fmt.Fprintf(os.Stderr, "tgs: "+format+"\n", args...)
+		}
+		fmt.Fprintf(os.Stderr, "tgs: context pack using ai.mode %s with %d tools\n", cfg.AI.Mode, len(agent.Tools()))
+	}
+
+	var files strings.Builder
+	files.WriteString("Start from these context files; use the tools to rank, read and quote sections:\n")
+	for _, p := range ctxFiles {
+		if rel, err := filepath.Rel(env.Root, p); err == nil {
+			p = filepath.ToSlash(rel)
+		}
+		files.WriteString("- " + p + "\n")
+	}
+
+	res, err := agent.Run(context.Background(), prompt, files.String())
+	if err != nil {
+		return fmt.Errorf("context pack: %w", err)
+	}
+	if verbose {
+		fmt.Fprintf(os.Stderr, "tgs: context pack stop=%s steps=%d tool_calls=%d tokens=%d\n", res.Stop, res.Steps, res.ToolCalls, res.Tokens)
+	}
+	if strings.TrimSpace(res.Text) == "" {
+		return fmt.Errorf("context pack: no brief returned (stop=%s)", res.Stop)
+	}
+	brief := env.Redactor.Apply(strings.TrimSpace(res.Text)) + "\n"
+	if err := os.WriteFile(outPath, []byte(brief), 0o644); err != nil {
+		return err
+	}
+	fmt.Print(brief)
+	if verbose {
+		fmt.Fprintf(os.Stderr, "wrote brief: %s\n", outPath)
+	}
+	return nil
+}
+
 func mustLoadPrompt(repoRoot, relPath, fallback string) string {
 	p := relPath
 	if !filepath.IsAbs(p) {
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+	"io"
 	"os"
 
 	"github.com/spf13/cobra"
@@ -9,7 +10,11 @@ import (
 
 // CmdHelp prints a concise usage with available commands.
 func CmdHelp(_ []string) int {
-	out := os.Stdout
+	writeHelp(os.Stdout)
+	return 0
+}
+
+func writeHelp(out io.Writer) {
 	fmt.Fprintln(out, "Usage: tgs [--json] <command> [args]")
 	fmt.Fprintln(out, "")
 	fmt.Fprintln(out, "Global Flags:")
@@ -20,14 +25,19 @@ func CmdHelp(_ []string) int {
 	fmt.Fprintln(out, "  help              Show this help")
 	fmt.Fprintln(out, "  init              Initialize TGS layout (idempotent)")
 	fmt.Fprintln(out, "  context           Context tools (e.g., pack)")
-	fmt.Fprintln(out, "  verify            Run hooks/policy checks (e.g., ears)")
+	fmt.Fprintln(out, "  verify            Run hooks/policy checks (e.g., ears, drift, links)")
 	fmt.Fprintln(out, "  agent             AI adapter runner (shell adapter)")
+	fmt.Fprintln(out, "  req               Requirement tools (e.g., model, diff, export)")
+	fmt.Fprintln(out, "  lsp               EARS language server for editors (stdio)")
+	fmt.Fprintln(out, "  trace             Traceability graph and test coverage (e.g., coverage)")
+	fmt.Fprintln(out, "  report            Static reports (e.g., html)")
 	fmt.Fprintln(out, "  version           Print version")
+	fmt.Fprintln(out, "  mcp               MCP server exposing TGS tools to AI agents (e.g., serve)")
 	fmt.Fprintln(out, "")
 	fmt.Fprintln(out, "Settings & Configuration:")
 	fmt.Fprintln(out, "  Config file       tgs/tgs.yml (auto-loaded); env prefix TGS_ via Viper")
 	fmt.Fprintln(out, "  Key settings      ai.provider, ai.model, ai.api_key_env, ai.shell_adapter_path")
-	fmt.Fprintln(out, "                   guardrails.ears.enable, guardrails.ears.paths")
+	fmt.Fprintln(out, "                   guardrails.ears.enable, guardrails.ears.paths, guardrails.ears.allow_negative")
 	fmt.Fprintln(out, "  Example (tgs/tgs.yml):")
 	fmt.Fprintln(out, "    guardrails:")
 	fmt.Fprintln(out, "      ears:")
@@ -56,13 +66,13 @@ func CmdHelp(_ []string) int {
 	fmt.Fprintln(out, "  5) Pack context into aibrief.md for the active thought")
 	fmt.Fprintln(out, "  	tgs context pack \"<your goal>\"")
 	fmt.Fprintln(out, "  6) Feed the brief to the AI agent of your choice to research, plan then get your approval before implementation.")
-	fmt.Fprintln(out, "  	tgs agent exec --task <taskID> --context aibrief.md")
+	fmt.Fprintln(out, "  	tgs agent exec --prompt-file <prompt.md> --context aibrief.md")
 	fmt.Fprintln(out, "")
 	fmt.Fprintln(out, "Examples:")
-	fmt.Fprintln(out, "  tgs verify ears")
+	fmt.Fprintln(out, "  tgs verify ears --analyze")
 	fmt.Fprintln(out, "  tgs context pack \"payment refund flow\" ")
+	fmt.Fprintln(out, "  tgs req model --format mermaid --out docs/diagrams")
 	fmt.Fprintln(out, "")
-	return 0
 }
 
 func newHelpCommand() *cobra.Command {
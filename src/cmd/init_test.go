@@ -6,6 +6,7 @@ import (
 	"testing"
 )
 
+// Verifies: SR-016, SR-017
 func TestInitSeedsFiles(t *testing.T) {
 	dir := t.TempDir()
 	if err := os.Chdir(dir); err != nil {
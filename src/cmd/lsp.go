@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/kelvin/tgsflow/src/core/lsp"
+	"github.com/spf13/cobra"
+)
+
+func newLSPCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:                "lsp",
+		Short:              "Run the EARS language server over stdio",
+		DisableFlagParsing: true, // CmdLSP parses its own flags
+		RunE: func(c *cobra.Command, args []string) error {
+			return codeToErr(CmdLSP(args))
+		},
+	}
+}
+
+// CmdLSP serves the Language Server Protocol on stdin/stdout for the EARS docs
+// configured under guardrails.ears.paths. The client's rootUri overrides --repo.
+func CmdLSP(args []string) int {
+	fs := flag.NewFlagSet("tgs lsp", flag.ContinueOnError)
+	repoRoot := fs.String("repo", ".", "Repository root path")
+	_ = fs.Bool("stdio", true, "Use stdio transport (the only transport; accepted for editor compatibility)")
+	fs.SetOutput(os.Stderr)
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if err := lsp.NewServer(*repoRoot).Serve(os.Stdin, os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "tgs lsp: %v\n", err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/kelvin/tgsflow/src/core/config"
+	"github.com/kelvin/tgsflow/src/core/jsonrpc"
+	"github.com/kelvin/tgsflow/src/core/mcp"
+	"github.com/kelvin/tgsflow/src/core/toolpack"
+	"github.com/spf13/cobra"
+)
+
+// mcpResourcePrefix prefixes repo-relative paths in resource URIs.
+const mcpResourcePrefix = "tgs://repo/"
+
+func newMCPCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "mcp",
+		Short: "Model Context Protocol integration",
+		RunE: func(c *cobra.Command, args []string) error {
+			return c.Help()
+		},
+	}
+	serveCmd := &cobra.Command{
+		Use:                "serve",
+		Short:              "Serve TGS tools and design/thought docs to AI agents over stdio",
+		DisableFlagParsing: true, // CmdMCPServe parses its own flags
+		RunE: func(c *cobra.Command, args []string) error {
+			return codeToErr(CmdMCPServe(args))
+		},
+	}
+	cmd.AddCommand(serveCmd)
+	return cmd
+}
+
+// CmdMCPServe runs an MCP server on stdin/stdout exposing the toolpack tools
+// and the design docs and thought files as resources. File access is limited
+// by guardrails.allow_paths and guardrails.deny_paths.
+func CmdMCPServe(args []string) int {
+	fs := flag.NewFlagSet("tgs mcp serve", flag.ContinueOnError)
+	repoRoot := fs.String("repo", ".", "Repository root path")
+	_ = fs.Bool("stdio", true, "Use stdio transport (the only transport; accepted for client compatibility)")
+	fs.SetOutput(os.Stderr)
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if err := serveMCP(*repoRoot, os.Stdin, os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "tgs mcp: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+func serveMCP(repoRoot string, r io.Reader, w io.Writer) error {
+	cfg, err := config.Load(repoRoot)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "tgs mcp: failed to load config: %v\n", err)
+	}
+	env, err := toolpack.NewEnv(repoRoot, cfg)
+	if err != nil {
+		return err
+	}
+	return newMCPServer(env).Serve(context.Background(), jsonrpc.NewLineConn(r, w))
+}
+
+func newMCPServer(env *toolpack.Env) *mcp.Server {
+	s := mcp.NewServer(mcp.Implementation{Name: "tgs"})
+	s.Instructions = "TGS (thoughts, guardrails, specs) tools for this repository. Read design docs and the active thought before planning; lint requirements with verify_ears."
+	for _, t := range env.Tools() {
+		s.AddTool(mcp.Tool{Name: t.Name, Description: t.Description, InputSchema: t.Schema}, t.Run)
+	}
+	s.ListResources = func(context.Context) ([]mcp.Resource, error) {
+		var out []mcp.Resource
+		for _, rel := range mcpDocuments(env) {
+			out = append(out, mcp.Resource{URI: mcpResourcePrefix + rel, Name: rel, MimeType: "text/markdown"})
+		}
+		return out, nil
+	}
+	s.ReadResource = func(_ context.Context, uri string) (mcp.ResourceContents, error) {
+		// only the listed documents are resources, not every file the sandbox allows
+		rel, ok := strings.CutPrefix(uri, mcpResourcePrefix)
+		docs := mcpDocuments(env)
+		if i := sort.SearchStrings(docs, rel); !ok || i == len(docs) || docs[i] != rel {
+			return mcp.ResourceContents{}, fmt.Errorf("unknown resource %s", uri)
+		}
+		data, _, err := env.Sandbox.ReadFile(rel)
+		if err != nil {
+			return mcp.ResourceContents{}, err
+		}
+		return mcp.ResourceContents{URI: uri, MimeType: "text/markdown", Text: env.Redactor.Apply(string(data))}, nil
+	}
+	return s
+}
+
+// mcpDocuments lists the design docs and every thought's Markdown files that
+// the guardrails allow, as repo-relative paths.
+func mcpDocuments(env *toolpack.Env) []string {
+	var out []string
+	add := func(pattern string) {
+		matches, _ := filepath.Glob(pattern)
+		for _, m := range matches {
+			if rel, err := env.Sandbox.Rel(m); err == nil && env.Sandbox.Allowed(rel) {
+				out = append(out, rel)
+			}
+		}
+	}
+	add(filepath.Join(toolpack.DesignDir(env.Root, env.Config), "*.md"))
+	add(filepath.Join(toolpack.ThoughtsDir(env.Root, env.Config), "*", "*.md"))
+	sort.Strings(out)
+	return out
+}
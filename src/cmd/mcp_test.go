@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"encoding/json"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kelvin/tgsflow/src/core/jsonrpc"
+	"github.com/kelvin/tgsflow/src/core/mcp"
+)
+
+// mcpSession drives serveMCP over in-memory pipes.
+type mcpSession struct {
+	t    *testing.T
+	conn *jsonrpc.LineConn
+	id   int
+}
+
+func startMCP(t *testing.T, repo string) *mcpSession {
+	t.Helper()
+	cr, sw := io.Pipe()
+	sr, cw := io.Pipe()
+	done := make(chan error, 1)
+	go func() { done <- serveMCP(repo, sr, sw); sw.Close() }()
+	t.Cleanup(func() {
+		cw.Close()
+		if err := <-done; err != nil {
+			t.Errorf("serveMCP: %v", err)
+		}
+	})
+	return &mcpSession{t: t, conn: jsonrpc.NewLineConn(cr, cw)}
+}
+
+func (s *mcpSession) call(method string, params, result any) *jsonrpc.Error {
+	s.t.Helper()
+	s.id++
+	req, _ := jsonrpc.NewRequest(s.id, method, params)
+	if err := s.conn.Write(req); err != nil {
+		s.t.Fatal(err)
+	}
+	reply, err := s.conn.Read()
+	if err != nil {
+		s.t.Fatal(err)
+	}
+	if reply.Error != nil {
+		return reply.Error
+	}
+	if err := json.Unmarshal(reply.Result, result); err != nil {
+		s.t.Fatal(err)
+	}
+	return nil
+}
+
+func TestMCPServe_ToolsResourcesAndGuardrails(t *testing.T) {
+	t.Setenv("TGS_THOUGHT_DIR", "")
+	repo := t.TempDir()
+	writeFile(t, filepath.Join(repo, "tgs", "design", "20_requirements.md"), "# Requirements\n- **SR-001**: When a refund is requested, the system shall notify the payer within 5 seconds.\n")
+	writeFile(t, filepath.Join(repo, "tgs", "thoughts", "abc1234-refunds", "plan.md"), "# Plan\n")
+	writeFile(t, filepath.Join(repo, "deploy", "notes.md"), "# Deploy\n")
+	writeFile(t, filepath.Join(repo, "src", "secrets.md"), "# Not a design doc\n")
+	s := startMCP(t, repo)
+
+	var init mcp.InitializeResult
+	if err := s.call("initialize", mcp.InitializeParams{ProtocolVersion: "2025-03-26", ClientInfo: mcp.Implementation{Name: "test"}}, &init); err != nil {
+		t.Fatal(err)
+	}
+	if init.ProtocolVersion != "2025-03-26" || init.ServerInfo.Name != "tgs" || init.Capabilities["resources"] == nil {
+		t.Fatalf("initialize = %+v", init)
+	}
+	note, _ := jsonrpc.NewNotification("notifications/initialized", nil)
+	_ = s.conn.Write(note)
+
+	var tools mcp.ListToolsResult
+	s.call("tools/list", nil, &tools)
+	var names []string
+	for _, tool := range tools.Tools {
+		names = append(names, tool.Name)
+	}
+	for _, want := range []string{"verify_ears", "parse_requirement", "list_thoughts", "active_thought", "context_pack", "fetch_repo_text", "list_candidates"} {
+		if !strings.Contains(strings.Join(names, ","), want) {
+			t.Errorf("tools/list missing %s: %v", want, names)
+		}
+	}
+
+	var res mcp.CallToolResult
+	s.call("tools/call", mcp.CallToolParams{Name: "verify_ears", Arguments: json.RawMessage(`{}`)}, &res)
+	if res.IsError || !strings.Contains(res.Text(), `"valid": 1`) {
+		t.Errorf("verify_ears = %+v", res)
+	}
+	res = mcp.CallToolResult{}
+	s.call("tools/call", mcp.CallToolParams{Name: "fetch_repo_text", Arguments: json.RawMessage(`{"path":"deploy/notes.md"}`)}, &res)
+	if !res.IsError || !strings.Contains(res.Text(), "not allowed by guardrails") {
+		t.Errorf("deny_paths not enforced: %+v", res)
+	}
+	if err := s.call("tools/call", mcp.CallToolParams{Name: "rm_rf"}, &res); err == nil || err.Code != jsonrpc.CodeInvalidParams {
+		t.Errorf("unknown tool error = %v", err)
+	}
+
+	var list mcp.ListResourcesResult
+	s.call("resources/list", nil, &list)
+	if len(list.Resources) != 2 || list.Resources[0].URI != "tgs://repo/tgs/design/20_requirements.md" || list.Resources[1].URI != "tgs://repo/tgs/thoughts/abc1234-refunds/plan.md" {
+		t.Fatalf("resources = %+v", list.Resources)
+	}
+	var read mcp.ReadResourceResult
+	s.call("resources/read", mcp.ReadResourceParams{URI: list.Resources[0].URI}, &read)
+	if len(read.Contents) != 1 || !strings.Contains(read.Contents[0].Text, "SR-001") {
+		t.Errorf("resources/read = %+v", read)
+	}
+	if err := s.call("resources/read", mcp.ReadResourceParams{URI: "tgs://repo/deploy/notes.md"}, &read); err == nil || err.Code != mcp.CodeResourceNotFound {
+		t.Errorf("denied resource read = %v", err)
+	}
+	if err := s.call("resources/read", mcp.ReadResourceParams{URI: "tgs://repo/src/secrets.md"}, &read); err == nil || err.Code != mcp.CodeResourceNotFound {
+		t.Errorf("unlisted resource read = %v", err)
+	}
+}
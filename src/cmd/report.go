@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/kelvin/tgsflow/src/core/config"
+	"github.com/kelvin/tgsflow/src/core/report"
+	"github.com/kelvin/tgsflow/src/core/trace"
+	"github.com/spf13/cobra"
+)
+
+func newReportCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "report",
+		Short: "Publish requirements, traceability and V&V reports",
+		RunE: func(c *cobra.Command, args []string) error {
+			return c.Help()
+		},
+	}
+	htmlCmd := &cobra.Command{
+		Use:                "html",
+		Short:              "Generate a self-contained static site (catalogue, trace, thoughts, lint, V&V)",
+		DisableFlagParsing: true, // CmdReportHTML parses its own flags
+		RunE: func(c *cobra.Command, args []string) error {
+			return codeToErr(CmdReportHTML(args))
+		},
+	}
+	cmd.AddCommand(htmlCmd)
+	return cmd
+}
+
+// CmdReportHTML writes the requirement catalogue, trace views, thought timeline,
+// EARS findings and V&V matrix as cross-linked static HTML under --out.
+func CmdReportHTML(args []string) int {
+	fs := flag.NewFlagSet("tgs report html", flag.ContinueOnError)
+	repoRoot := fs.String("repo", ".", "Repository root path")
+	outDir := fs.String("out", "site", "Output directory (relative to --repo unless absolute)")
+	title := fs.String("title", "Requirements report", "Site title")
+	noGit := fs.Bool("no-git", false, "Do not read commit trailers or thought dates from git history")
+	ci := fs.Bool("ci", false, "CI mode: exit non-zero when inputs cannot be read")
+	fs.SetOutput(os.Stderr)
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	cfg, err := config.Load(*repoRoot)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to load config: %v\n", err)
+		if *ci {
+			return 1
+		}
+	}
+	opts := trace.OptionsFromConfig(cfg)
+	opts.Git = !*noGit
+	site, errs := report.Collect(*repoRoot, *title, cfg, opts)
+	for _, e := range errs {
+		fmt.Fprintf(os.Stderr, "report html: %v\n", e)
+	}
+
+	dir := *outDir
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(*repoRoot, dir)
+	}
+	if err := site.Write(dir); err != nil {
+		fmt.Fprintf(os.Stderr, "report html: %v\n", err)
+		return 1
+	}
+	fmt.Fprintf(os.Stderr, "report html: requirements=%d thoughts=%d findings=%d out=%s\n",
+		len(site.Requirements), len(site.Thoughts), len(site.Findings), dir)
+	if *ci && len(errs) > 0 {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,21 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReportHTML_WritesSite(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "tgs", "design", "20_requirements.md"),
+		"- **SR-001**: When motion is detected, the alarm shall sound the siren. (Verification: Test)\n")
+	if code := CmdReportHTML([]string{"--repo", dir, "--no-git", "--out", "public"}); code != 0 {
+		t.Fatalf("expected code=0, got %d", code)
+	}
+	for _, name := range []string{"index.html", "requirements.html", "trace.html", "thoughts.html", "lint.html", "vnv.html", "style.css"} {
+		if _, err := os.Stat(filepath.Join(dir, "public", name)); err != nil {
+			t.Errorf("missing %s: %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,230 @@
+package cmd
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/kelvin/tgsflow/src/core/config"
+	"github.com/kelvin/tgsflow/src/core/reqs"
+	"github.com/spf13/cobra"
+)
+
+func newReqCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "req",
+		Short: "Requirement analysis and export tools",
+		RunE: func(c *cobra.Command, args []string) error {
+			return c.Help()
+		},
+	}
+	modelCmd := &cobra.Command{
+		Use:                "model",
+		Short:              "Catalogue states/events per system and export state diagrams",
+		DisableFlagParsing: true, // CmdReqModel parses its own flags
+		RunE: func(c *cobra.Command, args []string) error {
+			return codeToErr(CmdReqModel(args))
+		},
+	}
+	baselineCmd := &cobra.Command{
+		Use:                "baseline create <name>",
+		Short:              "Store a hashed snapshot of the parsed requirements under tgs/baselines/",
+		DisableFlagParsing: true, // CmdReqBaseline parses its own flags
+		RunE: func(c *cobra.Command, args []string) error {
+			return codeToErr(CmdReqBaseline(args))
+		},
+	}
+	baselineCmd.AddCommand(&cobra.Command{
+		Use:                "create <name>",
+		Short:              "Write tgs/baselines/<name>.json",
+		DisableFlagParsing: true, // cmdReqBaselineCreate parses its own flags
+		RunE: func(c *cobra.Command, args []string) error {
+			return codeToErr(cmdReqBaselineCreate(args))
+		},
+	})
+	diffCmd := &cobra.Command{
+		Use:                "diff <refA> [refB]",
+		Short:              "Report added, removed, reworded and shape-changed requirements by ID between baselines or git refs",
+		DisableFlagParsing: true, // CmdReqDiff parses its own flags
+		RunE: func(c *cobra.Command, args []string) error {
+			return codeToErr(CmdReqDiff(args))
+		},
+	}
+	exportCmd := &cobra.Command{
+		Use:                "export",
+		Short:              "Export the requirement set as ReqIF XML",
+		DisableFlagParsing: true, // CmdReqExport parses its own flags
+		RunE: func(c *cobra.Command, args []string) error {
+			return codeToErr(CmdReqExport(args))
+		},
+	}
+	importCmd := &cobra.Command{
+		Use:                "import <file.reqif>",
+		Short:              "Merge a ReqIF file into the requirements document, preserving local formatting",
+		DisableFlagParsing: true, // CmdReqImport parses its own flags
+		RunE: func(c *cobra.Command, args []string) error {
+			return codeToErr(CmdReqImport(args))
+		},
+	}
+	gherkinCmd := &cobra.Command{
+		Use:                "gherkin",
+		Short:              "Generate Gherkin .feature files from event-driven and unwanted requirements",
+		DisableFlagParsing: true, // CmdReqGherkin parses its own flags
+		RunE: func(c *cobra.Command, args []string) error {
+			return codeToErr(CmdReqGherkin(args))
+		},
+	}
+	scaffoldCmd := &cobra.Command{
+		Use:                "scaffold-tests",
+		Short:              "Write Go test skeletons for Test-verified requirements without an annotated test",
+		DisableFlagParsing: true, // CmdReqScaffoldTests parses its own flags
+		RunE: func(c *cobra.Command, args []string) error {
+			return codeToErr(CmdReqScaffoldTests(args))
+		},
+	}
+	cmd.AddCommand(modelCmd, baselineCmd, diffCmd, exportCmd, importCmd, gherkinCmd, scaffoldCmd)
+	return cmd
+}
+
+// loadRequirements resolves the requirement documents (flag override or config) and scans them.
+// Unreadable documents are reported to stderr; in CI mode they make the command fail.
+func loadRequirements(repoRoot, pathsFlag, cmdName string, ci bool) ([]reqs.Requirement, bool) {
+	cfg, err := config.Load(repoRoot)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to load config: %v\n", err)
+		if ci {
+			return nil, false
+		}
+	}
+	paths := reqs.DefaultPaths(cfg)
+	if strings.TrimSpace(pathsFlag) != "" {
+		paths = splitPaths(pathsFlag)
+	}
+	rules, err := reqs.LoadRules(repoRoot, cfg.Guardrails.EARS)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: glossary: %v\n", cmdName, err)
+	}
+	list, errs := reqs.Load(repoRoot, paths, reqs.DefaultLanguage(cfg), rules.Glossary.SystemNames())
+	for _, e := range errs {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", cmdName, e)
+	}
+	if len(errs) > 0 && ci {
+		return list, false
+	}
+	return list, true
+}
+
+// CmdReqModel aggregates preconditions and triggers into a state/event catalogue per system.
+func CmdReqModel(args []string) int {
+	fs := flag.NewFlagSet("tgs req model", flag.ContinueOnError)
+	repoRoot := fs.String("repo", ".", "Repository root path")
+	pathsFlag := fs.String("paths", "", "Comma-separated list of requirement docs (defaults from config)")
+	format := fs.String("format", "text", "Output format: text|json|mermaid|plantuml")
+	outDir := fs.String("out", "", "Directory to write one diagram per system (mermaid/plantuml only)")
+	ci := fs.Bool("ci", false, "CI mode: exit non-zero when findings are reported")
+	fs.SetOutput(os.Stderr)
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	list, ok := loadRequirements(*repoRoot, *pathsFlag, "req model", *ci)
+	if !ok {
+		return 1
+	}
+	model := reqs.BuildModel(list)
+
+	findings := 0
+	for _, sm := range model.Systems {
+		findings += len(sm.Findings)
+	}
+
+	switch strings.ToLower(*format) {
+	case "text":
+		printModelText(model)
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(model); err != nil {
+			fmt.Fprintf(os.Stderr, "req model: %v\n", err)
+			return 1
+		}
+	case "mermaid", "plantuml":
+		ext := ".mmd"
+		if strings.EqualFold(*format, "plantuml") {
+			ext = ".puml"
+		}
+		for _, sm := range model.Systems {
+			diagram := sm.Mermaid()
+			if ext == ".puml" {
+				diagram = sm.PlantUML()
+			}
+			if *outDir == "" {
+				fmt.Println(diagram)
+				continue
+			}
+			if err := os.MkdirAll(*outDir, 0o755); err != nil {
+				fmt.Fprintf(os.Stderr, "req model: %v\n", err)
+				return 1
+			}
+			path := filepath.Join(*outDir, slugify(sm.System)+ext)
+			if err := os.WriteFile(path, []byte(diagram), 0o644); err != nil {
+				fmt.Fprintf(os.Stderr, "req model: %v\n", err)
+				return 1
+			}
+			fmt.Fprintf(os.Stderr, "req model: wrote %s\n", path)
+		}
+	default:
+		fmt.Fprintf(os.Stderr, "unknown --format %q; expected text|json|mermaid|plantuml\n", *format)
+		return 2
+	}
+
+	fmt.Fprintf(os.Stderr, "req model: systems=%d findings=%d\n", len(model.Systems), findings)
+	if findings > 0 && *ci {
+		return 1
+	}
+	return 0
+}
+
+func printModelText(model reqs.Model) {
+	for _, sm := range model.Systems {
+		fmt.Printf("System: %s\n", sm.System)
+		fmt.Println("  States:")
+		for _, st := range sm.States {
+			fmt.Printf("    - %s %s\n", st.Name, refList(st.Refs))
+		}
+		fmt.Println("  Events:")
+		for _, ev := range sm.Events {
+			fmt.Printf("    - %s %s\n", ev.Name, refList(ev.Refs))
+		}
+		if len(sm.Findings) > 0 {
+			fmt.Println("  Findings:")
+			for _, f := range sm.Findings {
+				fmt.Printf("    - %s\n", f)
+			}
+		}
+		fmt.Println()
+	}
+}
+
+func refList(refs []string) string {
+	if len(refs) == 0 {
+		return "(no requirements)"
+	}
+	return "(" + strings.Join(refs, ", ") + ")"
+}
+
+var slugRe = regexp.MustCompile(`[^a-z0-9]+`)
+
+// slugify mirrors the make new-thought slug rules: lowercase, non-alphanumerics to '-'.
+func slugify(s string) string {
+	s = slugRe.ReplaceAllString(strings.ToLower(s), "-")
+	s = strings.Trim(s, "-")
+	if s == "" {
+		return "system"
+	}
+	return s
+}
@@ -0,0 +1,269 @@
+package cmd
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kelvin/tgsflow/src/core/config"
+	"github.com/kelvin/tgsflow/src/core/ears"
+	"github.com/kelvin/tgsflow/src/core/reqs"
+	"github.com/kelvin/tgsflow/src/core/trace"
+)
+
+// worktreeRef names the requirement documents as currently on disk in req diff.
+const worktreeRef = "WORKTREE"
+
+// CmdReqBaseline dispatches "tgs req baseline <subcommand>".
+func CmdReqBaseline(args []string) int {
+	if len(args) == 0 || args[0] != "create" {
+		fmt.Fprintln(os.Stderr, "usage: tgs req baseline create <name> [--repo PATH] [--paths a,b] [--force]")
+		return 2
+	}
+	return cmdReqBaselineCreate(args[1:])
+}
+
+// cmdReqBaselineCreate writes a hashed snapshot of the parsed requirements to
+// tgs/baselines/<name>.json.
+func cmdReqBaselineCreate(args []string) int {
+	fs := flag.NewFlagSet("tgs req baseline create", flag.ContinueOnError)
+	repoRoot := fs.String("repo", ".", "Repository root path")
+	pathsFlag := fs.String("paths", "", "Comma-separated list of requirement docs (defaults from config)")
+	force := fs.Bool("force", false, "Overwrite an existing baseline with the same name")
+	fs.SetOutput(os.Stderr)
+	rest, err := parseInterleaved(fs, args)
+	if err != nil {
+		return 2
+	}
+	if len(rest) != 1 || strings.ContainsAny(rest[0], `/\`) || strings.TrimSpace(rest[0]) == "" {
+		fmt.Fprintln(os.Stderr, "usage: tgs req baseline create <name> (name must not contain path separators)")
+		return 2
+	}
+	name := rest[0]
+	path := filepath.Join(*repoRoot, reqs.BaselineDir, name+".json")
+	if _, err := os.Stat(path); err == nil && !*force {
+		fmt.Fprintf(os.Stderr, "req baseline: %s already exists; pass --force to overwrite\n", path)
+		return 1
+	}
+
+	cfg, err := config.Load(*repoRoot)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to load config: %v\n", err)
+		return 1
+	}
+	paths := reqs.DefaultPaths(cfg)
+	if strings.TrimSpace(*pathsFlag) != "" {
+		paths = splitPaths(*pathsFlag)
+	}
+	rules, err := reqs.LoadRules(*repoRoot, cfg.Guardrails.EARS)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "req baseline: glossary: %v\n", err)
+	}
+	snap, errs := scanSnapshot(name, paths, reqs.DefaultLanguage(cfg), rules.Glossary.SystemNames(), readWorktree(*repoRoot))
+	if len(errs) > 0 {
+		for _, e := range errs {
+			fmt.Fprintf(os.Stderr, "req baseline: %v\n", e)
+		}
+		return 1
+	}
+	if out, err := trace.Git(*repoRoot, "rev-parse", "HEAD"); err == nil {
+		snap.Source = strings.TrimSpace(out)
+	}
+	if err := reqs.WriteSnapshot(path, snap); err != nil {
+		fmt.Fprintf(os.Stderr, "req baseline: %v\n", err)
+		return 1
+	}
+	fmt.Fprintf(os.Stderr, "req baseline: wrote %s (requirements=%d hash=%s)\n", path, len(snap.Requirements), snap.Hash[:12])
+	return 0
+}
+
+// CmdReqDiff reports requirements added, removed, reworded or shape-changed
+// between two refs. A ref is a baseline name, a git ref, or WORKTREE.
+func CmdReqDiff(args []string) int {
+	fs := flag.NewFlagSet("tgs req diff", flag.ContinueOnError)
+	repoRoot := fs.String("repo", ".", "Repository root path")
+	pathsFlag := fs.String("paths", "", "Comma-separated list of requirement docs for git refs (defaults from config)")
+	format := fs.String("format", "text", "Output format: text|json|markdown")
+	fs.SetOutput(os.Stderr)
+	refs, err := parseInterleaved(fs, args)
+	if err != nil {
+		return 2
+	}
+	if len(refs) == 1 {
+		refs = append(refs, worktreeRef)
+	}
+	if len(refs) != 2 {
+		fmt.Fprintln(os.Stderr, "usage: tgs req diff <refA> [refB] (baseline name, git ref or WORKTREE; refB defaults to WORKTREE)")
+		return 2
+	}
+	switch strings.ToLower(*format) {
+	case "text", "json", "markdown":
+	default:
+		fmt.Fprintf(os.Stderr, "unknown --format %q; expected text|json|markdown\n", *format)
+		return 2
+	}
+
+	cfg, err := config.Load(*repoRoot)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to load config: %v\n", err)
+	}
+	paths := reqs.DefaultPaths(cfg)
+	if strings.TrimSpace(*pathsFlag) != "" {
+		paths = splitPaths(*pathsFlag)
+	}
+	rules, err := reqs.LoadRules(*repoRoot, cfg.Guardrails.EARS)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "req diff: glossary: %v\n", err)
+	}
+	var snaps [2]reqs.Snapshot
+	for i, ref := range refs {
+		snaps[i], err = resolveSnapshot(*repoRoot, ref, paths, reqs.DefaultLanguage(cfg), rules.Glossary.SystemNames())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "req diff: %v\n", err)
+			return 1
+		}
+	}
+	changes := reqs.Diff(snaps[0], snaps[1])
+
+	switch strings.ToLower(*format) {
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(struct {
+			From    string        `json:"from"`
+			To      string        `json:"to"`
+			Changes []reqs.Change `json:"changes"`
+		}{refs[0], refs[1], changes}); err != nil {
+			fmt.Fprintf(os.Stderr, "req diff: %v\n", err)
+			return 1
+		}
+	case "markdown":
+		printReqDiffMarkdown(refs[0], refs[1], changes)
+	default:
+		for _, c := range changes {
+			switch c.Kind {
+			case reqs.ChangeAdded:
+				fmt.Printf("+ %s: %s\n", c.ID, c.After.Text)
+			case reqs.ChangeRemoved:
+				fmt.Printf("- %s: %s\n", c.ID, c.Before.Text)
+			case reqs.ChangeShapeChanged:
+				fmt.Printf("~ %s (%s → %s)\n    was: %s\n    now: %s\n", c.ID, c.Before.Shape, c.After.Shape, c.Before.Text, c.After.Text)
+			default:
+				fmt.Printf("~ %s (reworded)\n    was: %s\n    now: %s\n", c.ID, c.Before.Text, c.After.Text)
+			}
+		}
+	}
+	counts := map[reqs.ChangeKind]int{}
+	for _, c := range changes {
+		counts[c.Kind]++
+	}
+	fmt.Fprintf(os.Stderr, "req diff: %s..%s added=%d removed=%d reworded=%d shape-changed=%d\n", refs[0], refs[1],
+		counts[reqs.ChangeAdded], counts[reqs.ChangeRemoved], counts[reqs.ChangeReworded], counts[reqs.ChangeShapeChanged])
+	return 0
+}
+
+// resolveSnapshot loads ref as a stored baseline, the working tree, or the
+// requirement documents at a git ref.
+func resolveSnapshot(repoRoot, ref string, paths []string, lang string, names []string) (reqs.Snapshot, error) {
+	if ref == worktreeRef {
+		snap, errs := scanSnapshot(ref, paths, lang, names, readWorktree(repoRoot))
+		for _, e := range errs {
+			fmt.Fprintf(os.Stderr, "req diff: %v\n", e)
+		}
+		return snap, nil
+	}
+	if !strings.ContainsAny(ref, `/\`) {
+		path := filepath.Join(repoRoot, reqs.BaselineDir, ref+".json")
+		if _, err := os.Stat(path); err == nil {
+			return reqs.ReadSnapshot(path)
+		}
+	}
+	if _, err := trace.Git(repoRoot, "rev-parse", "--verify", "--quiet", ref+"^{commit}"); err != nil {
+		return reqs.Snapshot{}, fmt.Errorf("%q is neither a baseline in %s nor a git ref", ref, reqs.BaselineDir)
+	}
+	snap, errs := scanSnapshot(ref, paths, lang, names, func(rel string) (string, error) {
+		content, err := trace.Git(repoRoot, "show", ref+":"+filepath.ToSlash(rel))
+		if err != nil {
+			return "", fmt.Errorf("%s not found at %s", rel, ref)
+		}
+		return content, nil
+	})
+	for _, e := range errs {
+		fmt.Fprintf(os.Stderr, "req diff: %v\n", e)
+	}
+	return snap, nil
+}
+
+// readWorktree reads repo-relative documents from disk.
+func readWorktree(repoRoot string) func(rel string) (string, error) {
+	return func(rel string) (string, error) {
+		data, err := os.ReadFile(filepath.Join(repoRoot, rel))
+		if err != nil {
+			return "", fmt.Errorf("cannot read %s: %w", rel, err)
+		}
+		return string(data), nil
+	}
+}
+
+// scanSnapshot snapshots the ID'd items of the documents at paths, as returned
+// by read, parsing EARS lines with the known system names. Unreadable
+// documents are returned as errors and left out.
+func scanSnapshot(name string, paths []string, lang string, names []string, read func(rel string) (string, error)) (reqs.Snapshot, []error) {
+	var (
+		items  []reqs.Item
+		list   []reqs.Requirement
+		errs   []error
+		linter = &ears.Linter{Names: names}
+	)
+	for _, rel := range paths {
+		content, err := read(rel)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		items = append(items, reqs.ScanItems(rel, content)...)
+		list = append(list, reqs.ScanWith(linter, rel, content, reqs.IsRequirementsDoc(rel), lang)...)
+	}
+	return reqs.NewSnapshot(name, items, list), errs
+}
+
+func printReqDiffMarkdown(from, to string, changes []reqs.Change) {
+	fmt.Printf("## Requirement changes (%s → %s)\n", from, to)
+	sections := []struct {
+		kind  reqs.ChangeKind
+		title string
+	}{
+		{reqs.ChangeAdded, "Added"},
+		{reqs.ChangeRemoved, "Removed"},
+		{reqs.ChangeShapeChanged, "Shape changed"},
+		{reqs.ChangeReworded, "Reworded"},
+	}
+	for _, sec := range sections {
+		var lines []string
+		for _, c := range changes {
+			if c.Kind != sec.kind {
+				continue
+			}
+			switch c.Kind {
+			case reqs.ChangeAdded:
+				lines = append(lines, fmt.Sprintf("- **%s**: %s", c.ID, c.After.Text))
+			case reqs.ChangeRemoved:
+				lines = append(lines, fmt.Sprintf("- **%s**: ~~%s~~", c.ID, c.Before.Text))
+			case reqs.ChangeShapeChanged:
+				lines = append(lines, fmt.Sprintf("- **%s** (%s → %s): %s", c.ID, c.Before.Shape, c.After.Shape, c.After.Text))
+			default:
+				lines = append(lines, fmt.Sprintf("- **%s**: %s", c.ID, c.After.Text))
+			}
+		}
+		if len(lines) == 0 {
+			continue
+		}
+		fmt.Printf("\n### %s\n\n%s\n", sec.title, strings.Join(lines, "\n"))
+	}
+	if len(changes) == 0 {
+		fmt.Println("\nNo requirement changes.")
+	}
+}
@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/kelvin/tgsflow/src/core/reqs"
+)
+
+// CmdReqGherkin writes one .feature file per system with a scenario per
+// event-driven, unwanted or complex requirement, flagging scenarios whose
+// requirement changed since they were generated.
+func CmdReqGherkin(args []string) int {
+	fs := flag.NewFlagSet("tgs req gherkin", flag.ContinueOnError)
+	repoRoot := fs.String("repo", ".", "Repository root path")
+	pathsFlag := fs.String("paths", "", "Comma-separated list of requirement docs (defaults from config)")
+	outDir := fs.String("out", "features", "Directory for .feature files (relative paths are under --repo)")
+	update := fs.Bool("update", false, "Regenerate stale scenarios and drop scenarios of removed requirements")
+	ci := fs.Bool("ci", false, "CI mode: exit non-zero when stale scenarios are found")
+	fs.SetOutput(os.Stderr)
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	list, ok := loadRequirements(*repoRoot, *pathsFlag, "req gherkin", *ci)
+	if !ok {
+		return 1
+	}
+	bySystem := make(map[string][]reqs.Scenario)
+	total := 0
+	for _, r := range list {
+		if s, ok := reqs.ScenarioFor(r); ok {
+			bySystem[s.System] = append(bySystem[s.System], s)
+			total++
+		}
+	}
+	dir := *outDir
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(*repoRoot, dir)
+	}
+	// Existing feature files for systems no longer present still get their scenarios flagged
+	existing, _ := filepath.Glob(filepath.Join(dir, "*.feature"))
+	files := make(map[string]string)
+	for system := range bySystem {
+		files[filepath.Join(dir, slugify(system)+".feature")] = system
+	}
+	for _, path := range existing {
+		if _, ok := files[path]; !ok {
+			files[path] = ""
+		}
+	}
+	paths := make([]string, 0, len(files))
+	for p := range files {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	staleCount := 0
+	for _, path := range paths {
+		system := files[path]
+		data, err := os.ReadFile(path)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			fmt.Fprintf(os.Stderr, "req gherkin: %v\n", err)
+			return 1
+		}
+		out, stale := reqs.SyncFeature(string(data), system, bySystem[system], *update)
+		for _, s := range stale {
+			reason := "requirement changed"
+			if s.Removed {
+				reason = "requirement removed"
+			}
+			fmt.Fprintf(os.Stderr, "%s:%d: scenario for %s is stale (%s)\n", path, s.Line, s.ID, reason)
+		}
+		staleCount += len(stale)
+		if out == string(data) {
+			continue
+		}
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			fmt.Fprintf(os.Stderr, "req gherkin: %v\n", err)
+			return 1
+		}
+		if err := os.WriteFile(path, []byte(out), 0o644); err != nil {
+			fmt.Fprintf(os.Stderr, "req gherkin: %v\n", err)
+			return 1
+		}
+		fmt.Fprintf(os.Stderr, "req gherkin: wrote %s\n", path)
+	}
+	fmt.Fprintf(os.Stderr, "req gherkin: scenarios=%d stale=%d\n", total, staleCount)
+	if staleCount > 0 && *ci {
+		return 1
+	}
+	return 0
+}
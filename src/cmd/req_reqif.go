@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/kelvin/tgsflow/src/core/config"
+	"github.com/kelvin/tgsflow/src/core/ears"
+	"github.com/kelvin/tgsflow/src/core/reqif"
+	"github.com/kelvin/tgsflow/src/core/reqs"
+)
+
+// requirementsDoc returns the repo-relative requirements document: the flag
+// value or <context.pack_dir>/20_requirements.md.
+func requirementsDoc(cfg config.Config, docFlag string) string {
+	if strings.TrimSpace(docFlag) != "" {
+		return filepath.ToSlash(filepath.Clean(docFlag))
+	}
+	dir := strings.TrimSpace(cfg.Context.PackDir)
+	if dir == "" {
+		dir = "tgs/design"
+	}
+	return filepath.ToSlash(filepath.Join(dir, "20_requirements.md"))
+}
+
+// CmdReqExport writes the requirement set in an exchange format (currently ReqIF).
+func CmdReqExport(args []string) int {
+	fs := flag.NewFlagSet("tgs req export", flag.ContinueOnError)
+	repoRoot := fs.String("repo", ".", "Repository root path")
+	docFlag := fs.String("doc", "", "Requirements document (default: <context.pack_dir>/20_requirements.md)")
+	format := fs.String("format", "reqif", "Output format: reqif")
+	outPath := fs.String("out", "", "Write to this file instead of stdout")
+	fs.SetOutput(os.Stderr)
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if !strings.EqualFold(*format, "reqif") {
+		fmt.Fprintf(os.Stderr, "unknown --format %q; expected reqif\n", *format)
+		return 2
+	}
+	cfg, err := config.Load(*repoRoot)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to load config: %v\n", err)
+	}
+	rel := requirementsDoc(cfg, *docFlag)
+	data, err := os.ReadFile(filepath.Join(*repoRoot, rel))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "req export: %v\n", err)
+		return 1
+	}
+	lang := reqs.DefaultLanguage(cfg)
+	if l := ears.DetectLanguage(string(data)); l != "" {
+		lang = l
+	}
+	var list []reqif.Requirement
+	for _, it := range reqs.ScanItems(rel, string(data)) {
+		r := reqif.Requirement{ID: it.ID, Text: it.Text, Verification: it.Verification, Parents: it.Parents}
+		if res, err := ears.ParseRequirementIn(lang, it.Text); err == nil {
+			r.Shape = string(res.Shape)
+		}
+		list = append(list, r)
+	}
+	out, err := reqif.Export(filepath.Base(rel), list, time.Now())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "req export: %v\n", err)
+		return 1
+	}
+	if *outPath == "" {
+		_, err = os.Stdout.Write(out)
+	} else {
+		err = os.WriteFile(*outPath, out, 0o644)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "req export: %v\n", err)
+		return 1
+	}
+	fmt.Fprintf(os.Stderr, "req export: requirements=%d format=reqif\n", len(list))
+	return 0
+}
+
+// CmdReqImport merges a ReqIF file into the requirements document, editing
+// existing bullets in place and appending new ones.
+func CmdReqImport(args []string) int {
+	fs := flag.NewFlagSet("tgs req import", flag.ContinueOnError)
+	repoRoot := fs.String("repo", ".", "Repository root path")
+	docFlag := fs.String("doc", "", "Requirements document (default: <context.pack_dir>/20_requirements.md)")
+	dryRun := fs.Bool("dry-run", false, "Report changes without writing the document")
+	fs.SetOutput(os.Stderr)
+	files, err := parseInterleaved(fs, args)
+	if err != nil {
+		return 2
+	}
+	if len(files) != 1 {
+		fmt.Fprintln(os.Stderr, "usage: tgs req import <file.reqif> [--doc PATH] [--dry-run]")
+		return 2
+	}
+	data, err := os.ReadFile(files[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "req import: %v\n", err)
+		return 1
+	}
+	imported, err := reqif.Import(data)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "req import: %v\n", err)
+		return 1
+	}
+	cfg, err := config.Load(*repoRoot)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to load config: %v\n", err)
+	}
+	rel := requirementsDoc(cfg, *docFlag)
+	path := filepath.Join(*repoRoot, rel)
+	doc, err := os.ReadFile(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "req import: %v\n", err)
+		return 1
+	}
+	merged, rep := reqif.Merge(rel, string(doc), imported)
+	for _, id := range rep.Updated {
+		fmt.Printf("~ %s\n", id)
+	}
+	for _, id := range rep.Added {
+		fmt.Printf("+ %s\n", id)
+	}
+	for _, id := range rep.Missing {
+		fmt.Fprintf(os.Stderr, "req import: %s is not in the import; kept\n", id)
+	}
+	if !*dryRun && merged != string(doc) {
+		if err := os.WriteFile(path, []byte(merged), 0o644); err != nil {
+			fmt.Fprintf(os.Stderr, "req import: %v\n", err)
+			return 1
+		}
+	}
+	fmt.Fprintf(os.Stderr, "req import: %s updated=%d added=%d unchanged=%d missing=%d\n", rel, len(rep.Updated), len(rep.Added), len(rep.Unchanged), len(rep.Missing))
+	return 0
+}
@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kelvin/tgsflow/src/core/trace"
+)
+
+// CmdReqScaffoldTests writes a table-driven test skeleton for every
+// "(Verification: Test)" requirement that no "// Verifies:" test covers yet.
+func CmdReqScaffoldTests(args []string) int {
+	fs := flag.NewFlagSet("tgs req scaffold-tests", flag.ContinueOnError)
+	repoRoot := fs.String("repo", ".", "Repository root path")
+	pathsFlag := fs.String("paths", "", "Comma-separated list of requirement docs (defaults from config)")
+	lang := fs.String("lang", "go", "Test language: go")
+	pkg := fs.String("pkg", "", "Package directory for the skeletons, relative to --repo (required)")
+	dryRun := fs.Bool("dry-run", false, "List the skeletons that would be written")
+	fs.SetOutput(os.Stderr)
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if !strings.EqualFold(*lang, "go") {
+		fmt.Fprintf(os.Stderr, "unsupported --lang %q; expected go\n", *lang)
+		return 2
+	}
+	if strings.TrimSpace(*pkg) == "" {
+		fmt.Fprintln(os.Stderr, "usage: tgs req scaffold-tests --lang go --pkg <path>")
+		return 2
+	}
+
+	list, ok := loadRequirements(*repoRoot, *pathsFlag, "req scaffold-tests", false)
+	if !ok {
+		return 1
+	}
+	tests, testErrs := trace.ScanTests(*repoRoot)
+	for _, err := range testErrs {
+		fmt.Fprintf(os.Stderr, "req scaffold-tests: %v\n", err)
+	}
+	missing := trace.MissingTestRequirements(list, tests)
+	dir := filepath.Join(*repoRoot, *pkg)
+	pkgName := trace.PackageName(dir)
+	written := 0
+	for _, r := range missing {
+		if *dryRun {
+			fmt.Println(filepath.Join(dir, trace.TestFileName(r.ID)))
+			continue
+		}
+		path, ok, err := trace.WriteSkeleton(dir, pkgName, r)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "req scaffold-tests: %v\n", err)
+			return 1
+		}
+		if !ok {
+			fmt.Fprintf(os.Stderr, "req scaffold-tests: %s exists; skipped %s\n", path, r.ID)
+			continue
+		}
+		written++
+		fmt.Println(path)
+	}
+	fmt.Fprintf(os.Stderr, "req scaffold-tests: uncovered=%d written=%d package=%s\n", len(missing), written, pkgName)
+	return 0
+}
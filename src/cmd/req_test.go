@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReqModel_WritesDiagrams(t *testing.T) {
+	dir := t.TempDir()
+	reqs := "- **SR-001**: While idle, when the arm button is pressed, the alarm shall enter armed mode.\n" +
+		"- **SR-002**: While in armed mode, when motion is detected, the alarm shall sound the siren.\n"
+	writeFile(t, filepath.Join(dir, "tgs", "design", "20_requirements.md"), reqs)
+	writeFile(t, filepath.Join(dir, "tgs", "tgs.yml"), "guardrails:\n  ears:\n    paths: [tgs/design/20_requirements.md]\n")
+
+	out := filepath.Join(dir, "diagrams")
+	if code := CmdReqModel([]string{"--repo", dir, "--format", "mermaid", "--out", out}); code != 0 {
+		t.Fatalf("expected code=0, got %d", code)
+	}
+	b, err := os.ReadFile(filepath.Join(out, "alarm.mmd"))
+	if err != nil {
+		t.Fatalf("diagram not written: %v", err)
+	}
+	if !strings.Contains(string(b), "stateDiagram-v2") {
+		t.Fatalf("unexpected diagram: %s", b)
+	}
+
+	// Single-use events and never-entered states are findings; CI mode fails on them
+	if code := CmdReqModel([]string{"--repo", dir, "--ci"}); code != 1 {
+		t.Fatalf("expected code=1 in CI mode with findings, got %d", code)
+	}
+	if code := CmdReqModel([]string{"--repo", dir, "--format", "svg"}); code != 2 {
+		t.Fatalf("expected code=2 for unknown format, got %d", code)
+	}
+}
+
+func TestReqBaselineAndDiff(t *testing.T) {
+	dir := t.TempDir()
+	doc := filepath.Join(dir, "tgs", "design", "20_requirements.md")
+	writeFile(t, doc, "- **SR-001**: When motion is detected, the alarm shall sound the siren.\n- **SR-002**: The alarm shall log events.\n- **CON-001**: Builds use Go 1.23.\n")
+	writeFile(t, filepath.Join(dir, "tgs", "tgs.yml"), "guardrails:\n  ears:\n    paths: [tgs/design/20_requirements.md]\n")
+
+	if code := CmdReqBaseline([]string{"create", "v1", "--repo", dir}); code != 0 {
+		t.Fatalf("expected code=0, got %d", code)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "tgs", "baselines", "v1.json")); err != nil {
+		t.Fatalf("baseline not written: %v", err)
+	}
+	if code := CmdReqBaseline([]string{"create", "v1", "--repo", dir}); code != 1 {
+		t.Fatalf("expected code=1 for existing baseline without --force, got %d", code)
+	}
+
+	writeFile(t, doc, "- **SR-001**: While armed, when motion is detected, the alarm shall sound the siren.\n- **SR-003**: The alarm shall notify the owner.\n- **CON-001**: Builds use Go 1.24.\n")
+	old := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+	code := CmdReqDiff([]string{"v1", "--repo", dir})
+	w.Close()
+	os.Stdout = old
+	out, _ := io.ReadAll(r)
+	if code != 0 {
+		t.Fatalf("expected code=0, got %d", code)
+	}
+	for _, want := range []string{"~ SR-001 (event-driven → complex)", "+ SR-003", "- SR-002", "~ CON-001 (reworded)"} {
+		if !strings.Contains(string(out), want) {
+			t.Fatalf("diff missing %q:\n%s", want, out)
+		}
+	}
+	if code := CmdReqDiff([]string{"v9", "--repo", dir}); code != 1 {
+		t.Fatalf("expected code=1 for unknown ref, got %d", code)
+	}
+}
+
+func TestReqExportImport_RoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	doc := filepath.Join(dir, "tgs", "design", "20_requirements.md")
+	content := "# Requirements\n\n- **SR-001**: When motion is detected, the alarm shall sound the siren. (Traces: N-001) (Verification: Test)\n"
+	writeFile(t, doc, content)
+
+	out := filepath.Join(dir, "reqs.reqif")
+	if code := CmdReqExport([]string{"--repo", dir, "--out", out}); code != 0 {
+		t.Fatalf("expected code=0, got %d", code)
+	}
+	b, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), `THE-VALUE="event-driven"`) {
+		t.Fatalf("expected EARS shape in export: %s", b)
+	}
+	if code := CmdReqImport([]string{out, "--repo", dir}); code != 0 {
+		t.Fatalf("expected code=0, got %d", code)
+	}
+	if got, _ := os.ReadFile(doc); string(got) != content {
+		t.Fatalf("round trip changed the document:\n%s", got)
+	}
+	if code := CmdReqExport([]string{"--repo", dir, "--format", "csv"}); code != 2 {
+		t.Fatalf("expected code=2 for unknown format, got %d", code)
+	}
+}
+
+func TestReqGherkin_FlagsStaleScenarios(t *testing.T) {
+	dir := t.TempDir()
+	doc := filepath.Join(dir, "tgs", "design", "20_requirements.md")
+	writeFile(t, filepath.Join(dir, "tgs", "tgs.yml"), "guardrails:\n  ears:\n    paths: [tgs/design/20_requirements.md]\n")
+	writeFile(t, doc, "- **SR-001**: When motion is detected, the alarm shall sound the siren.\n")
+
+	if code := CmdReqGherkin([]string{"--repo", dir, "--ci"}); code != 0 {
+		t.Fatalf("expected code=0, got %d", code)
+	}
+	feature := filepath.Join(dir, "features", "alarm.feature")
+	b, err := os.ReadFile(feature)
+	if err != nil || !strings.Contains(string(b), "@SR-001 @ears-") || !strings.Contains(string(b), "When motion is detected") {
+		t.Fatalf("unexpected feature file (%v):\n%s", err, b)
+	}
+
+	writeFile(t, doc, "- **SR-001**: When motion is detected, the alarm shall sound the siren within 2 seconds.\n")
+	if code := CmdReqGherkin([]string{"--repo", dir, "--ci"}); code != 1 {
+		t.Fatalf("expected code=1 for stale scenario in CI mode, got %d", code)
+	}
+	if b, _ := os.ReadFile(feature); !strings.Contains(string(b), "@stale") {
+		t.Fatalf("expected @stale tag:\n%s", b)
+	}
+	if code := CmdReqGherkin([]string{"--repo", dir, "--update", "--ci"}); code != 0 {
+		t.Fatalf("expected code=0 after --update, got %d", code)
+	}
+}
+
+func TestReqScaffoldTests_SkipsCovered(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "tgs", "tgs.yml"), "guardrails:\n  ears:\n    paths: [tgs/design/20_requirements.md]\n")
+	writeFile(t, filepath.Join(dir, "tgs", "design", "20_requirements.md"),
+		"- **SR-001**: When motion is detected, the alarm shall sound the siren. (Verification: Test)\n"+
+			"- **SR-002**: The alarm shall log events. (Verification: Test)\n")
+	writeFile(t, filepath.Join(dir, "alarm", "log_test.go"), "package alarm\n\nimport \"testing\"\n\n// Verifies: SR-002\nfunc TestLog(t *testing.T) {}\n")
+
+	if code := CmdReqScaffoldTests([]string{"--repo", dir, "--lang", "go", "--pkg", "alarm"}); code != 0 {
+		t.Fatalf("expected code=0, got %d", code)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "alarm", "sr_001_test.go")); err != nil {
+		t.Fatalf("skeleton for SR-001 not written: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "alarm", "sr_002_test.go")); err == nil {
+		t.Fatal("SR-002 is already covered and must be skipped")
+	}
+	if code := CmdReqScaffoldTests([]string{"--repo", dir, "--lang", "python", "--pkg", "alarm"}); code != 2 {
+		t.Fatalf("expected code=2 for unsupported language, got %d", code)
+	}
+}
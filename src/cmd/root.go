@@ -44,7 +44,7 @@ func NewRootCommand(version, commit, date string) *cobra.Command {
 		RunE: func(cmd *cobra.Command, args []string) error {
 			// If no subcommand: show help or version
 			if flagVersion {
-				fmt.Printf("tgs %s (commit %s, built %s)\n", version, commit, date)
+				printVersion(version, commit, date)
 				return exitCodeError{code: 0}
 			}
 			_ = cmd.Help()
@@ -76,6 +76,12 @@ func NewRootCommand(version, commit, date string) *cobra.Command {
 		newContextCommand(),
 		newVerifyCommand(),
 		newAgentCommand(),
+		newReqCommand(),
+		newLSPCommand(),
+		newTraceCommand(),
+		newReportCommand(),
+		newMCPCommand(),
+		newVersionCommand(version, commit, date),
 	)
 
 	// Use our custom help command
@@ -84,6 +90,21 @@ func NewRootCommand(version, commit, date string) *cobra.Command {
 	return root
 }
 
+func newVersionCommand(version, commit, date string) *cobra.Command {
+	return &cobra.Command{
+		Use:   "version",
+		Short: "Print version",
+		Args:  cobra.NoArgs,
+		Run: func(c *cobra.Command, args []string) {
+			printVersion(version, commit, date)
+		},
+	}
+}
+
+func printVersion(version, commit, date string) {
+	fmt.Printf("tgs %s (commit %s, built %s)\n", version, commit, date)
+}
+
 // Execute runs the CLI and maps errors to exit codes.
 func Execute(version, commit, date string) int {
 	root := NewRootCommand(version, commit, date)
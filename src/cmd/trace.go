@@ -0,0 +1,472 @@
+package cmd
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kelvin/tgsflow/src/core/config"
+	"github.com/kelvin/tgsflow/src/core/reqs"
+	"github.com/kelvin/tgsflow/src/core/trace"
+	"github.com/spf13/cobra"
+)
+
+func newTraceCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                "trace",
+		Short:              "Build the traceability graph (needs → requirements → V&V → thoughts → commits → files)",
+		DisableFlagParsing: true, // CmdTrace parses its own flags
+		RunE: func(c *cobra.Command, args []string) error {
+			return codeToErr(CmdTrace(args))
+		},
+	}
+	coverageCmd := &cobra.Command{
+		Use:                "coverage",
+		Short:              "Report passing/failing/missing tests per requirement from // Verifies: annotations",
+		DisableFlagParsing: true, // CmdTraceCoverage parses its own flags
+		RunE: func(c *cobra.Command, args []string) error {
+			return codeToErr(CmdTraceCoverage(args))
+		},
+	}
+	impactCmd := &cobra.Command{
+		Use:                "impact [paths...]",
+		Short:              "List requirements, thoughts and tests affected by changed paths (or --base <ref>)",
+		DisableFlagParsing: true, // CmdTraceImpact parses its own flags
+		RunE: func(c *cobra.Command, args []string) error {
+			return codeToErr(CmdTraceImpact(args))
+		},
+	}
+	allocationCmd := &cobra.Command{
+		Use:                "allocation",
+		Short:              "Report requirement-to-component allocation from Allocated-To annotations",
+		DisableFlagParsing: true, // CmdTraceAllocation parses its own flags
+		RunE: func(c *cobra.Command, args []string) error {
+			return codeToErr(CmdTraceAllocation(args))
+		},
+	}
+	cmd.AddCommand(coverageCmd, impactCmd, allocationCmd)
+	return cmd
+}
+
+// CmdTrace builds the traceability graph and prints it with an orphans report.
+func CmdTrace(args []string) int {
+	fs := flag.NewFlagSet("tgs trace", flag.ContinueOnError)
+	repoRoot := fs.String("repo", ".", "Repository root path")
+	format := fs.String("format", "text", "Output format: text|json|dot|mermaid")
+	outPath := fs.String("out", "", "Write the graph to this file instead of stdout")
+	noGit := fs.Bool("no-git", false, "Do not read commit trailers from git history")
+	ci := fs.Bool("ci", false, "CI mode: exit non-zero when orphans are reported")
+	fs.SetOutput(os.Stderr)
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	fmtName := strings.ToLower(*format)
+	switch fmtName {
+	case "text", "json", "dot", "mermaid":
+	default:
+		fmt.Fprintf(os.Stderr, "unknown --format %q; expected text|json|dot|mermaid\n", *format)
+		return 2
+	}
+
+	cfg, err := config.Load(*repoRoot)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to load config: %v\n", err)
+		if *ci {
+			return 1
+		}
+	}
+	opts := trace.OptionsFromConfig(cfg)
+	opts.Git = !*noGit
+	g, errs := trace.Build(*repoRoot, opts)
+	for _, e := range errs {
+		fmt.Fprintf(os.Stderr, "trace: %v\n", e)
+	}
+	orphans := g.Orphans()
+
+	var out io.Writer = os.Stdout
+	if *outPath != "" {
+		f, err := os.Create(*outPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "trace: %v\n", err)
+			return 1
+		}
+		defer f.Close()
+		out = f
+	}
+	switch fmtName {
+	case "text":
+		printTraceText(out, g, orphans)
+	case "json":
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		enc.SetEscapeHTML(false)
+		err = enc.Encode(struct {
+			*trace.Graph
+			Orphans trace.Orphans `json:"orphans"`
+		}{g, orphans})
+	case "dot":
+		err = g.WriteDOT(out)
+	case "mermaid":
+		err = g.WriteMermaid(out)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "trace: %v\n", err)
+		return 1
+	}
+
+	fmt.Fprintf(os.Stderr, "trace: nodes=%d edges=%d orphans=%d\n", len(g.Nodes), len(g.Edges), orphans.Count())
+	if *ci && (orphans.Count() > 0 || len(errs) > 0) {
+		return 1
+	}
+	return 0
+}
+
+func printTraceText(w io.Writer, g *trace.Graph, o trace.Orphans) {
+	for _, k := range []trace.Kind{trace.KindNeed, trace.KindRequirement, trace.KindVnV, trace.KindThought, trace.KindCommit, trace.KindFile, trace.KindTest, trace.KindComponent} {
+		fmt.Fprintf(w, "%-12s %d\n", k+":", len(g.NodesOf(k)))
+	}
+	section := func(title string, items []string) {
+		if len(items) == 0 {
+			return
+		}
+		fmt.Fprintf(w, "\n%s (%d):\n", title, len(items))
+		for _, it := range items {
+			fmt.Fprintf(w, "  - %s\n", it)
+		}
+	}
+	section("Requirements without a parent need", o.RequirementsWithoutNeed)
+	section("Needs without requirements", o.NeedsWithoutRequirement)
+	section("Requirements without a V&V row", o.RequirementsWithoutVerification)
+	section("Dangling references", o.Dangling)
+}
+
+// CmdTraceCoverage matches "// Verifies: <IDs>" test annotations, and optionally
+// `go test -json` results, against the requirements document. With --ci, a
+// requirement marked "(Verification: Test)" without a passing or declared test
+// (or with a failing one) fails the command.
+func CmdTraceCoverage(args []string) int {
+	fs := flag.NewFlagSet("tgs trace coverage", flag.ContinueOnError)
+	repoRoot := fs.String("repo", ".", "Repository root path")
+	resultsPath := fs.String("results", "", "File with `go test -json` output (\"-\" for stdin)")
+	format := fs.String("format", "text", "Output format: text|json")
+	ci := fs.Bool("ci", false, "CI mode: exit non-zero when Test-verified requirements are missing or failing")
+	fs.SetOutput(os.Stderr)
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if f := strings.ToLower(*format); f != "text" && f != "json" {
+		fmt.Fprintf(os.Stderr, "unknown --format %q; expected text|json\n", *format)
+		return 2
+	}
+
+	cfg, err := config.Load(*repoRoot)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to load config: %v\n", err)
+		if *ci {
+			return 1
+		}
+	}
+	items, err := trace.Requirements(*repoRoot, trace.OptionsFromConfig(cfg))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "trace coverage: %v\n", err)
+		return 1
+	}
+	tests, testErrs := trace.ScanTests(*repoRoot)
+	for _, err := range testErrs {
+		fmt.Fprintf(os.Stderr, "trace coverage: %v\n", err)
+	}
+	var results trace.TestResults
+	if *resultsPath != "" {
+		var r io.Reader = os.Stdin
+		if *resultsPath != "-" {
+			f, err := os.Open(*resultsPath)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "trace coverage: %v\n", err)
+				return 1
+			}
+			defer f.Close()
+			r = f
+		}
+		if results, err = trace.ReadTestJSON(r); err != nil {
+			fmt.Fprintf(os.Stderr, "trace coverage: reading results: %v\n", err)
+			return 1
+		}
+	}
+
+	rep := trace.Coverage(items, tests, results)
+	counts := map[trace.CoverageStatus]int{}
+	findings := 0
+	for _, rc := range rep.Requirements {
+		counts[rc.Status]++
+		if rc.Status == trace.CoverageFailing || (rc.Enforced() && rc.Status == trace.CoverageMissing) {
+			findings++
+		}
+	}
+	findings += len(rep.Unknown)
+
+	if strings.EqualFold(*format, "json") {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(rep); err != nil {
+			fmt.Fprintf(os.Stderr, "trace coverage: %v\n", err)
+			return 1
+		}
+	} else {
+		for _, rc := range rep.Requirements {
+			verification := rc.Verification
+			if verification == "" {
+				verification = "-"
+			}
+			line := fmt.Sprintf("%-8s %-9s %-13s", rc.ID, rc.Status, verification)
+			var names []string
+			for _, t := range rc.Tests {
+				name := t.Name
+				if t.Status != "" {
+					name += " (" + string(t.Status) + ")"
+				}
+				names = append(names, name)
+			}
+			fmt.Println(strings.TrimRight(line+" "+strings.Join(names, ", "), " "))
+		}
+		for _, t := range rep.Unknown {
+			fmt.Fprintf(os.Stderr, "%s:%d: %s verifies unknown requirement %s\n", t.Path, t.Line, t.Name, strings.Join(t.Verifies, ", "))
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "trace coverage: requirements=%d passing=%d failing=%d declared=%d missing=%d\n",
+		len(rep.Requirements), counts[trace.CoveragePassing], counts[trace.CoverageFailing], counts[trace.CoverageDeclared], counts[trace.CoverageMissing])
+	if findings > 0 && *ci {
+		return 1
+	}
+	return 0
+}
+
+// CmdTraceImpact reports the requirements a change may affect, with the thoughts
+// that introduced them and the tests that verify them. Paths are taken from the
+// arguments and/or `git diff --name-only <base>`.
+func CmdTraceImpact(args []string) int {
+	fs := flag.NewFlagSet("tgs trace impact", flag.ContinueOnError)
+	repoRoot := fs.String("repo", ".", "Repository root path")
+	base := fs.String("base", "", "Git ref to diff the working tree against (e.g. origin/main)")
+	format := fs.String("format", "text", "Output format: text|json")
+	fs.SetOutput(os.Stderr)
+	paths, err := parseInterleaved(fs, args)
+	if err != nil {
+		return 2
+	}
+	if f := strings.ToLower(*format); f != "text" && f != "json" {
+		fmt.Fprintf(os.Stderr, "unknown --format %q; expected text|json\n", *format)
+		return 2
+	}
+	if *base == "" && len(paths) == 0 {
+		fmt.Fprintln(os.Stderr, "usage: tgs trace impact <paths...> | --base <ref>")
+		return 2
+	}
+
+	rep, code := traceImpact(*repoRoot, *base, paths)
+	if code != 0 {
+		return code
+	}
+	if strings.EqualFold(*format, "json") {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(rep); err != nil {
+			fmt.Fprintf(os.Stderr, "trace impact: %v\n", err)
+			return 1
+		}
+	} else {
+		for _, r := range rep.Requirements {
+			fmt.Printf("%s (%s:%d)\n", r.ID, r.Path, r.Line)
+			for _, why := range r.Reasons {
+				fmt.Printf("  via:     %s\n", why)
+			}
+			for _, th := range r.Thoughts {
+				fmt.Printf("  thought: %s\n", th)
+			}
+			for _, t := range r.Tests {
+				fmt.Printf("  test:    %s\n", t)
+			}
+		}
+		for _, p := range rep.Unmapped {
+			fmt.Fprintf(os.Stderr, "trace impact: %s has no traceability links\n", p)
+		}
+	}
+	fmt.Fprintf(os.Stderr, "trace impact: paths=%d requirements=%d unmapped=%d\n", len(rep.Paths), len(rep.Requirements), len(rep.Unmapped))
+	return 0
+}
+
+// traceImpact builds the graph and runs the impact analysis for paths plus the
+// files changed since base (when set). Errors are reported to stderr.
+func traceImpact(repoRoot, base string, paths []string) (trace.ImpactReport, int) {
+	if base != "" {
+		changed, err := trace.ChangedFiles(repoRoot, base)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "trace impact: %v\n", err)
+			return trace.ImpactReport{}, 1
+		}
+		paths = append(paths, changed...)
+	}
+	cfg, err := config.Load(repoRoot)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to load config: %v\n", err)
+	}
+	g, errs := trace.Build(repoRoot, trace.OptionsFromConfig(cfg))
+	for _, e := range errs {
+		fmt.Fprintf(os.Stderr, "trace impact: %v\n", e)
+	}
+	return g.Impact(paths), 0
+}
+
+// parseInterleaved parses flags that may follow positional arguments, e.g.
+// "impact src/a.go --format json", and returns the positional arguments.
+func parseInterleaved(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// impactContextFiles returns the thought documents and test files behind an
+// impact report, for inclusion in a context pack.
+func impactContextFiles(repoRoot string, cfg config.Config, rep trace.ImpactReport) []string {
+	thoughtsDir := trace.OptionsFromConfig(cfg).ThoughtsDir
+	var files []string
+	for _, r := range rep.Requirements {
+		for _, th := range r.Thoughts {
+			matches, _ := filepath.Glob(filepath.Join(repoRoot, thoughtsDir, th, "*.md"))
+			files = append(files, matches...)
+		}
+		for _, t := range r.Tests {
+			path, _, _ := strings.Cut(t, ":")
+			files = append(files, filepath.Join(repoRoot, path))
+		}
+	}
+	return files
+}
+
+// CmdTraceAllocation reports the allocation of requirements to the components
+// declared in 30_architecture.md: the allocation matrix, unallocated requirements,
+// components without requirements and requirements whose EARS system does not
+// name their component. Allocated-To targets that name no declared component
+// always fail the command; with --ci, any finding does.
+func CmdTraceAllocation(args []string) int {
+	fs := flag.NewFlagSet("tgs trace allocation", flag.ContinueOnError)
+	repoRoot := fs.String("repo", ".", "Repository root path")
+	format := fs.String("format", "text", "Output format: text|json")
+	ci := fs.Bool("ci", false, "CI mode: exit non-zero when allocation findings are reported")
+	fs.SetOutput(os.Stderr)
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if f := strings.ToLower(*format); f != "text" && f != "json" {
+		fmt.Fprintf(os.Stderr, "unknown --format %q; expected text|json\n", *format)
+		return 2
+	}
+
+	cfg, err := config.Load(*repoRoot)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to load config: %v\n", err)
+		if *ci {
+			return 1
+		}
+	}
+	opts := trace.OptionsFromConfig(cfg)
+	opts.Git = false
+	if _, err := trace.Requirements(*repoRoot, opts); err != nil {
+		fmt.Fprintf(os.Stderr, "trace allocation: %v\n", err)
+		return 1
+	}
+	g, errs := trace.Build(*repoRoot, opts)
+	for _, e := range errs {
+		fmt.Fprintf(os.Stderr, "trace allocation: %v\n", e)
+	}
+	reqPath := filepath.ToSlash(filepath.Join(opts.DesignDir, "20_requirements.md"))
+	rules, err := reqs.LoadRules(*repoRoot, cfg.Guardrails.EARS)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "trace allocation: glossary: %v\n", err)
+	}
+	list, _ := reqs.Load(*repoRoot, []string{reqPath}, reqs.DefaultLanguage(cfg), rules.Glossary.SystemNames())
+	systems := make(map[string]string)
+	for _, r := range list {
+		if r.ID != "" && r.Valid() {
+			systems[r.ID] = r.Result.System
+		}
+	}
+	rep := g.Allocation(systems)
+
+	if strings.EqualFold(*format, "json") {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(rep); err != nil {
+			fmt.Fprintf(os.Stderr, "trace allocation: %v\n", err)
+			return 1
+		}
+	} else {
+		printAllocationText(os.Stdout, rep)
+	}
+	for _, u := range rep.Unknown {
+		fmt.Fprintf(os.Stderr, "trace allocation: %s:%d: %s is allocated to unknown component %s\n", u.Path, u.Line, u.Requirement, u.Component)
+	}
+	fmt.Fprintf(os.Stderr, "trace allocation: components=%d allocated=%d unallocated=%d unused=%d mismatches=%d unknown=%d\n",
+		len(rep.Components), len(rep.Matrix), len(rep.Unallocated), len(rep.Unused), len(rep.Mismatches), len(rep.Unknown))
+	if len(rep.Unknown) > 0 || (*ci && rep.Count() > 0) {
+		return 1
+	}
+	return 0
+}
+
+func printAllocationText(w io.Writer, rep trace.AllocationReport) {
+	if len(rep.Components) > 0 && len(rep.Matrix) > 0 {
+		fmt.Fprintf(w, "%-8s", "")
+		for _, c := range rep.Components {
+			fmt.Fprintf(w, " %-6s", c.ID)
+		}
+		fmt.Fprintln(w)
+		for _, row := range rep.Matrix {
+			fmt.Fprintf(w, "%-8s", row.Requirement)
+			for _, c := range rep.Components {
+				mark := "."
+				for _, id := range row.Components {
+					if id == c.ID {
+						mark = "x"
+					}
+				}
+				fmt.Fprintf(w, " %-6s", mark)
+			}
+			fmt.Fprintln(w)
+		}
+	}
+	section := func(title string, items []string) {
+		if len(items) == 0 {
+			return
+		}
+		fmt.Fprintf(w, "\n%s (%d):\n", title, len(items))
+		for _, it := range items {
+			fmt.Fprintf(w, "  - %s\n", it)
+		}
+	}
+	section("Requirements without a component", rep.Unallocated)
+	section("Components without requirements", rep.Unused)
+	var mismatches []string
+	for _, m := range rep.Mismatches {
+		mismatches = append(mismatches, fmt.Sprintf("%s:%d: %s names system %q but is allocated to %s", m.Path, m.Line, m.Requirement, m.System, strings.Join(m.Components, ", ")))
+	}
+	section("System/component mismatches", mismatches)
+	var unknown []string
+	for _, u := range rep.Unknown {
+		unknown = append(unknown, fmt.Sprintf("%s:%d: %s is allocated to unknown component %s", u.Path, u.Line, u.Requirement, u.Component))
+	}
+	section("Unknown components", unknown)
+}
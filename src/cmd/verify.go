@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"encoding/json"
 	"flag"
 	"fmt"
 	"os"
@@ -10,6 +11,7 @@ import (
 
 	"github.com/kelvin/tgsflow/src/core/config"
 	"github.com/kelvin/tgsflow/src/core/ears"
+	"github.com/kelvin/tgsflow/src/core/reqs"
 	"github.com/spf13/cobra"
 )
 
@@ -34,7 +36,22 @@ func CmdVerify(args []string) int {
 
 	// Optional: EARS linter gate (default false)
 	if cfg.Guardrails.EARS.Enable {
-		issues := verifyEARS(*repoRoot)
+		rules, err := reqs.LoadRules(*repoRoot, cfg.Guardrails.EARS)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "verify: glossary: %v\n", err)
+			if *ci {
+				return 1
+			}
+		}
+		lang := reqs.DefaultLanguage(cfg)
+		if _, ok := ears.LookupLanguage(lang); !ok {
+			fmt.Fprintf(os.Stderr, "verify: unsupported guardrails.ears.language %q (supported: %s)\n", lang, strings.Join(ears.Languages(), ", "))
+			if *ci {
+				return 1
+			}
+			lang = "en"
+		}
+		issues := verifyEARS(*repoRoot, rules, lang)
 		for _, is := range issues {
 			fmt.Fprintln(os.Stderr, is)
 		}
@@ -70,10 +87,22 @@ func runHook(path string) error {
 
 // in future we may add scoped args like --since
 
+// splitPaths parses a comma-separated --paths flag value.
+func splitPaths(v string) []string {
+	var out []string
+	for _, p := range strings.Split(v, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
 func newVerifyCommand() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "verify",
-		Short: "Run hooks/policy/drift checks",
+		Use:                "verify",
+		Short:              "Run hooks/policy/drift checks",
+		DisableFlagParsing: true, // CmdVerify parses its own flags
 		RunE: func(c *cobra.Command, args []string) error {
 			return codeToErr(CmdVerify(args))
 		},
@@ -81,13 +110,38 @@ func newVerifyCommand() *cobra.Command {
 
 	// Add subcommand: verify ears
 	earsCmd := &cobra.Command{
-		Use:   "ears",
-		Short: "Lint EARS requirements (design docs by default)",
+		Use:                "ears",
+		Short:              "Lint EARS requirements (design docs by default)",
+		DisableFlagParsing: true, // CmdVerifyEARS parses its own flags
 		RunE: func(c *cobra.Command, args []string) error {
 			return codeToErr(CmdVerifyEARS(args))
 		},
 	}
-	cmd.AddCommand(earsCmd)
+	driftCmd := &cobra.Command{
+		Use:                "drift",
+		Short:              "Check that paths, commands, config keys and Go symbols named in docs still exist",
+		DisableFlagParsing: true, // CmdVerifyDrift parses its own flags
+		RunE: func(c *cobra.Command, args []string) error {
+			return codeToErr(CmdVerifyDrift(args))
+		},
+	}
+	cliCmd := &cobra.Command{
+		Use:                "cli",
+		Short:              "Check IF-xxx requirements and help text against the command tree (--dump prints it)",
+		DisableFlagParsing: true, // CmdVerifyCLI parses its own flags
+		RunE: func(c *cobra.Command, args []string) error {
+			return codeToErr(CmdVerifyCLI(args))
+		},
+	}
+	linksCmd := &cobra.Command{
+		Use:                "links",
+		Short:              "Check relative Markdown links and #anchors under tgs/",
+		DisableFlagParsing: true, // CmdVerifyLinks parses its own flags
+		RunE: func(c *cobra.Command, args []string) error {
+			return codeToErr(CmdVerifyLinks(args))
+		},
+	}
+	cmd.AddCommand(earsCmd, driftCmd, cliCmd, linksCmd)
 	return cmd
 }
 
@@ -98,10 +152,18 @@ func CmdVerifyEARS(args []string) int {
 	ci := fs.Bool("ci", false, "CI mode")
 	// optional override: --paths comma,separated
 	pathsFlag := fs.String("paths", "", "Comma-separated list of paths to lint (defaults from config)")
+	analyze := fs.Bool("analyze", false, "Also report likely duplicates and conflicting requirements")
+	format := fs.String("format", "text", "Output format: text|json (json writes parsed requirements to stdout)")
+	workers := fs.Int("workers", 0, "Parser workers (0 = number of CPUs)")
+	noCache := fs.Bool("no-cache", false, "Do not read or write the parse cache")
 	fs.SetOutput(os.Stderr)
 	if err := fs.Parse(args); err != nil {
 		return 2
 	}
+	if *format != "text" && *format != "json" {
+		fmt.Fprintf(os.Stderr, "unknown --format %q; expected text|json\n", *format)
+		return 2
+	}
 
 	cfg, err := config.Load(*repoRoot)
 	if err != nil {
@@ -110,23 +172,39 @@ func CmdVerifyEARS(args []string) int {
 			return 1
 		}
 	}
+	rules, err := reqs.LoadRules(*repoRoot, cfg.Guardrails.EARS)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "verify ears: glossary: %v\n", err)
+		if *ci {
+			return 1
+		}
+	}
+	lang := reqs.DefaultLanguage(cfg)
+	if _, ok := ears.LookupLanguage(lang); !ok {
+		fmt.Fprintf(os.Stderr, "verify ears: unsupported guardrails.ears.language %q (supported: %s)\n", lang, strings.Join(ears.Languages(), ", "))
+		if *ci {
+			return 1
+		}
+		lang = "en"
+	}
+	linter := &ears.Linter{Workers: *workers, Names: rules.Glossary.SystemNames()}
+	if !*noCache {
+		cache, err := ears.OpenCache(filepath.Join(*repoRoot, reqs.CacheDir(cfg)))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "verify ears: cache: %v\n", err)
+		}
+		linter.Cache = cache
+	}
 	// Resolve paths
-	var paths []string
+	paths := reqs.DefaultPaths(cfg)
 	if strings.TrimSpace(*pathsFlag) != "" {
-		for _, p := range strings.Split(*pathsFlag, ",") {
-			p = strings.TrimSpace(p)
-			if p != "" {
-				paths = append(paths, p)
-			}
-		}
-	} else if len(cfg.Guardrails.EARS.Paths) > 0 {
-		paths = append(paths, cfg.Guardrails.EARS.Paths...)
-	} else {
-		paths = []string{"tgs/design/10_needs.md", "tgs/design/20_requirements.md"}
+		paths = splitPaths(*pathsFlag)
 	}
 
 	var (
 		issues        []string
+		scanned       []reqs.Requirement
+		records       []earsRecord
 		totalCaptured int
 		totalValid    int
 		totalInvalid  int
@@ -144,119 +222,37 @@ func CmdVerifyEARS(args []string) int {
 			}
 			continue
 		}
-		lines := strings.Split(string(data), "\n")
-		isReqDoc := strings.HasSuffix(rel, "20_requirements.md")
 		if _, ok := perFile[rel]; !ok {
 			perFile[rel] = &fileCounts{}
 		}
 		fc := perFile[rel]
-		// helper to strip leading bold ID markers like **SR-001**: or **N-001**:
-		sanitize := func(s string) string {
-			if strings.HasPrefix(s, "**") {
-				// find closing '**'
-				if idx := strings.Index(s[2:], "**:"); idx >= 0 {
-					return strings.TrimSpace(s[2+idx+3:])
-				}
-				if idx := strings.Index(s[2:], "**:"); idx == -1 {
-					if end := strings.Index(s[2:], "**"); end >= 0 {
-						rest := s[2+end+2:]
-						rest = strings.TrimPrefix(rest, ":")
-						return strings.TrimSpace(rest)
-					}
-				}
-			}
-			return s
-		}
-		// Reuse core linter behavior: scan respecting code fences and bullets via minimal adapter
-		inFence := false
-		bulletResponseMode := false
-		for i, ln := range lines {
-			raw := ln
-			trimmed := strings.TrimSpace(raw)
-			if strings.HasPrefix(trimmed, "```") {
-				inFence = !inFence
-				continue
-			}
-			if inFence || trimmed == "" || strings.HasPrefix(trimmed, "#") {
-				if trimmed == "" {
-					bulletResponseMode = false
-				}
-				continue
-			}
-			isBullet := strings.HasPrefix(trimmed, "- ") || strings.HasPrefix(trimmed, "* ") || strings.HasPrefix(trimmed, "1. ")
-			if isBullet && bulletResponseMode {
-				continue
-			}
-			if !isBullet {
-				upper := strings.ToUpper(trimmed)
-				// Attempt parse for EARS-shaped lines. For requirements doc, enforce even without 'shall' to surface missing shall.
-				if strings.HasPrefix(upper, "WHEN ") || strings.HasPrefix(upper, "WHILE ") || strings.HasPrefix(upper, "IF ") || strings.HasPrefix(upper, "THE ") {
-					if !isReqDoc && !strings.Contains(strings.ToLower(trimmed), " shall") {
-						// In needs doc, skip lines without 'shall'.
-						continue
-					}
-					totalCaptured++
-					fc.captured++
-					if _, err := ears.ParseRequirement(trimmed); err != nil {
-						issues = append(issues, fmt.Sprintf("%s:%d: %s", rel, i+1, err.Error()))
-						totalInvalid++
-						fc.invalid++
-					} else {
-						totalValid++
-						fc.valid++
-					}
-					if strings.HasSuffix(trimmed, ":") && strings.Contains(strings.ToLower(trimmed), " shall") {
-						bulletResponseMode = true
-					} else {
-						bulletResponseMode = false
-					}
-					continue
-				}
-			}
-			if isBullet {
-				candidate := strings.TrimSpace(trimmed[2:])
-				candidate = sanitize(candidate)
-				upper := strings.ToUpper(candidate)
-				hasStarter := strings.HasPrefix(upper, "WHEN ") || strings.HasPrefix(upper, "WHILE ") || strings.HasPrefix(upper, "IF ") || strings.HasPrefix(upper, "THE ")
-				if isReqDoc {
-					// In requirements doc, lint EARS-shaped bullets regardless of 'shall' to catch missing shall
-					if hasStarter {
-						totalCaptured++
-						fc.captured++
-						if _, err := ears.ParseRequirement(candidate); err != nil {
-							issues = append(issues, fmt.Sprintf("%s:%d: %s", rel, i+1, err.Error()))
-							totalInvalid++
-							fc.invalid++
-						} else {
-							totalValid++
-							fc.valid++
-						}
-						continue
-					}
-				} else {
-					// In needs doc, only lint if explicit 'shall' is present to avoid over-flagging
-					if !strings.Contains(strings.ToLower(candidate), " shall") {
-						continue
-					}
-					if hasStarter {
-						totalCaptured++
-						fc.captured++
-						if _, err := ears.ParseRequirement(candidate); err != nil {
-							issues = append(issues, fmt.Sprintf("%s:%d: %s", rel, i+1, err.Error()))
-							totalInvalid++
-							fc.invalid++
-						} else {
-							totalValid++
-							fc.valid++
-						}
-						continue
-					}
+		// Scan respects code fences and bullet response sections; requirements docs are linted strictly
+		for _, r := range reqs.ScanWith(linter, rel, string(data), reqs.IsRequirementsDoc(rel), lang) {
+			scanned = append(scanned, r)
+			totalCaptured++
+			fc.captured++
+			msgs := rules.CheckRequirement(r)
+			records = append(records, newEARSRecord(r, msgs))
+			if len(msgs) > 0 {
+				for _, m := range msgs {
+					issues = append(issues, fmt.Sprintf("%s:%d: %s", rel, r.Line, m))
 				}
+				totalInvalid++
+				fc.invalid++
 				continue
 			}
+			totalValid++
+			fc.valid++
 		}
 	}
 
+	if *analyze {
+		issues = append(issues, analyzeEARS(scanned)...)
+	}
+	if err := linter.Cache.Save(); err != nil {
+		fmt.Fprintf(os.Stderr, "verify ears: cache: %v\n", err)
+	}
+
 	for _, is := range issues {
 		fmt.Fprintln(os.Stderr, is)
 	}
@@ -267,15 +263,60 @@ func CmdVerifyEARS(args []string) int {
 		}
 	}
 	fmt.Fprintf(os.Stderr, "verify ears: captured=%d valid=%d invalid=%d\n", totalCaptured, totalValid, totalInvalid)
+	if *format == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		enc.SetEscapeHTML(false) // keep comparators like "<=" readable
+		if err := enc.Encode(records); err != nil {
+			fmt.Fprintf(os.Stderr, "verify ears: %v\n", err)
+			return 1
+		}
+	}
 	if len(issues) > 0 && *ci {
 		return 1
 	}
 	return 0
 }
 
+// earsRecord is the JSON view of one captured requirement, including its extracted bound.
+type earsRecord struct {
+	Path         string       `json:"path"`
+	Line         int          `json:"line"`
+	ID           string       `json:"id,omitempty"`
+	Text         string       `json:"text"`
+	Verification string       `json:"verification,omitempty"`
+	Valid        bool         `json:"valid"`
+	Result       *ears.Result `json:"result,omitempty"`
+	Issues       []string     `json:"issues,omitempty"`
+}
+
+func newEARSRecord(r reqs.Requirement, msgs []string) earsRecord {
+	rec := earsRecord{Path: r.Path, Line: r.Line, ID: r.ID, Text: r.Text, Verification: r.Verification, Valid: len(msgs) == 0, Issues: msgs}
+	if r.Valid() {
+		res := r.Result
+		rec.Result = &res
+	}
+	return rec
+}
+
+// analyzeEARS reports duplicate/conflict findings located at the later requirement of each pair.
+func analyzeEARS(list []reqs.Requirement) []string {
+	byRef := make(map[string]reqs.Requirement, len(list))
+	for _, r := range list {
+		byRef[r.Ref()] = r
+	}
+	var out []string
+	for _, f := range reqs.Analyze(list) {
+		loc := byRef[f.B]
+		out = append(out, fmt.Sprintf("%s:%d: %s: %s", loc.Path, loc.Line, f.Kind, f.Message))
+	}
+	return out
+}
+
 // verifyEARS is a temporary placeholder that will be replaced by the real linter integration.
-// It scans markdown files for bullet lines and returns issue strings.
-func verifyEARS(repoRoot string) []string {
+// It scans markdown files for bullet lines and returns issue strings. Lines are
+// checked in lang unless a file selects another profile with an ears-language marker.
+func verifyEARS(repoRoot string, rules reqs.Rules, lang string) []string {
 	var issues []string
 	filepath.WalkDir(repoRoot, func(path string, d os.DirEntry, err error) error {
 		if err != nil {
@@ -294,6 +335,11 @@ func verifyEARS(repoRoot string) []string {
 			if err != nil {
 				return nil
 			}
+			fileLang := lang
+			if l := ears.DetectLanguage(string(data)); l != "" {
+				fileLang = l
+			}
+			profile, _ := ears.LookupLanguage(fileLang)
 			lines := strings.Split(string(data), "\n")
 			inFence := false
 			bulletResponseMode := false
@@ -321,12 +367,17 @@ func verifyEARS(repoRoot string) []string {
 				// Non-bullet candidate lines that start with EARS keywords
 				if !isBullet {
 					upper := strings.ToUpper(trimmed)
-					if strings.HasPrefix(upper, "WHEN ") || strings.HasPrefix(upper, "WHILE ") || strings.HasPrefix(upper, "IF ") || strings.HasPrefix(upper, "THE ") {
-						if _, err := ears.ParseRequirement(trimmed); err != nil {
-							issues = append(issues, fmt.Sprintf("%s:%d: %s", path, i+1, err.Error()))
+					starter := strings.HasPrefix(upper, "WHEN ") || strings.HasPrefix(upper, "WHILE ") || strings.HasPrefix(upper, "IF ") || strings.HasPrefix(upper, "THE ")
+					modal := strings.Contains(strings.ToLower(trimmed), " shall")
+					if profile != nil {
+						starter, modal = profile.HasStarter(trimmed), profile.HasModal(trimmed)
+					}
+					if starter {
+						for _, m := range rules.Check(fileLang, trimmed) {
+							issues = append(issues, fmt.Sprintf("%s:%d: %s", path, i+1, m))
 						}
 						// If this line ends with ":" and contains " shall" before it, enable bullet response mode
-						if strings.HasSuffix(trimmed, ":") && strings.Contains(strings.ToLower(trimmed), " shall") {
+						if strings.HasSuffix(trimmed, ":") && modal {
 							bulletResponseMode = true
 						} else {
 							bulletResponseMode = false
@@ -338,8 +389,8 @@ func verifyEARS(repoRoot string) []string {
 				// Bullet candidate lines (top-level bullets only)
 				if isBullet {
 					candidate := strings.TrimSpace(trimmed[2:])
-					if _, err := ears.ParseRequirement(candidate); err != nil {
-						issues = append(issues, fmt.Sprintf("%s:%d: %s", path, i+1, err.Error()))
+					for _, m := range rules.Check(fileLang, candidate) {
+						issues = append(issues, fmt.Sprintf("%s:%d: %s", path, i+1, m))
 					}
 					continue
 				}
@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/kelvin/tgsflow/src/core/config"
+	"github.com/kelvin/tgsflow/src/core/drift"
+	"github.com/kelvin/tgsflow/src/core/trace"
+)
+
+// CmdVerifyCLI checks that the commands and flags named by IF-xxx requirements
+// and by CmdHelp exist in the command tree of NewRootCommand. With --dump it
+// writes the tree as a JSON spec instead.
+func CmdVerifyCLI(args []string) int {
+	fs := flag.NewFlagSet("tgs verify cli", flag.ContinueOnError)
+	repoRoot := fs.String("repo", ".", "Repository root path")
+	dump := fs.Bool("dump", false, "Print the command/flag tree as JSON and exit")
+	outPath := fs.String("out", "", "With --dump, write the spec to this file instead of stdout")
+	ci := fs.Bool("ci", false, "CI mode: exit non-zero when a command or flag does not resolve")
+	fs.SetOutput(os.Stderr)
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	root := NewRootCommand("dev", "", "")
+	if *dump {
+		var buf bytes.Buffer
+		enc := json.NewEncoder(&buf)
+		enc.SetIndent("", "  ")
+		enc.SetEscapeHTML(false)
+		if err := enc.Encode(cliSpec(root)); err != nil {
+			fmt.Fprintf(os.Stderr, "verify cli: %v\n", err)
+			return 1
+		}
+		if *outPath == "" {
+			_, _ = os.Stdout.Write(buf.Bytes())
+			return 0
+		}
+		if err := os.WriteFile(*outPath, buf.Bytes(), 0o644); err != nil {
+			fmt.Fprintf(os.Stderr, "verify cli: %v\n", err)
+			return 1
+		}
+		fmt.Fprintf(os.Stderr, "verify cli: wrote %s\n", *outPath)
+		return 0
+	}
+
+	cfg, err := config.Load(*repoRoot)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to load config: %v\n", err)
+		if *ci {
+			return 1
+		}
+	}
+	checker := &drift.Checker{RepoRoot: *repoRoot, Commands: commandTree(root)}
+
+	var refs []drift.Ref
+	items, err := trace.Requirements(*repoRoot, trace.OptionsFromConfig(cfg))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "verify cli: %v\n", err)
+	}
+	interfaces := 0
+	for _, it := range items {
+		if !strings.HasPrefix(it.ID, "IF-") {
+			continue
+		}
+		interfaces++
+		for _, r := range drift.Extract(it.Path, it.Text) {
+			if r.Kind == drift.KindCommand {
+				r.Line = it.Line
+				refs = append(refs, r)
+			}
+		}
+	}
+	refs = append(refs, helpCommands()...)
+
+	findings := checker.Check(refs)
+	for _, f := range findings {
+		fmt.Fprintln(os.Stderr, f)
+	}
+	fmt.Fprintf(os.Stderr, "verify cli: interfaces=%d commands=%d findings=%d\n", interfaces, len(refs), len(findings))
+	if len(findings) > 0 && *ci {
+		return 1
+	}
+	return 0
+}
+
+var (
+	helpCommandRe = regexp.MustCompile(`^  ([a-z][a-z-]*)\s{2,}\S`)
+	helpUsageRe   = regexp.MustCompile(`(?:^|\s)(tgs\s.*)$`)
+)
+
+// helpCommands returns the commands advertised by CmdHelp: the entries of its
+// "Commands:" section and every "tgs ..." example line.
+func helpCommands() []drift.Ref {
+	var buf bytes.Buffer
+	writeHelp(&buf)
+	var refs []drift.Ref
+	section := ""
+	for i, ln := range strings.Split(buf.String(), "\n") {
+		if strings.HasSuffix(ln, ":") && !strings.HasPrefix(ln, " ") {
+			section = ln
+			continue
+		}
+		if section == "Commands:" {
+			if m := helpCommandRe.FindStringSubmatch(ln); m != nil {
+				refs = append(refs, drift.Ref{Kind: drift.KindCommand, Value: "tgs " + m[1], Path: "tgs help", Line: i + 1})
+			}
+			continue
+		}
+		if m := helpUsageRe.FindStringSubmatch(strings.TrimSpace(ln)); m != nil {
+			refs = append(refs, drift.Ref{Kind: drift.KindCommand, Value: m[1], Path: "tgs help", Line: i + 1})
+		}
+	}
+	return refs
+}
@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Verifies: IF-003
+func TestVerifyCLI_InterfacesAndHelp(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "tgs", "design", "20_requirements.md"),
+		"- **IF-001**: The system shall provide a `tgs verify --repo <PATH>` command. (Verification: Test)\n")
+	if code := CmdVerifyCLI([]string{"--repo", dir, "--ci"}); code != 0 {
+		t.Fatalf("expected code=0 when interfaces and help resolve, got %d", code)
+	}
+	if _, ok := commandTree(NewRootCommand("dev", "", ""))["tgs version"]; !ok {
+		t.Error("expected the version command advertised by help")
+	}
+	writeFile(t, filepath.Join(dir, "tgs", "design", "20_requirements.md"),
+		"- **IF-001**: The system shall provide a `tgs verify --strict` command. (Verification: Test)\n")
+	if code := CmdVerifyCLI([]string{"--repo", dir, "--ci"}); code != 1 {
+		t.Fatalf("expected code=1 for an unknown interface flag, got %d", code)
+	}
+
+	out := filepath.Join(dir, "cli.json")
+	if code := CmdVerifyCLI([]string{"--dump", "--out", out}); code != 0 {
+		t.Fatalf("expected code=0 for --dump, got %d", code)
+	}
+	b, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var spec commandSpec
+	if err := json.Unmarshal(b, &spec); err != nil {
+		t.Fatalf("invalid spec: %v\n%s", err, b)
+	}
+	if spec.Path != "tgs" || len(spec.Commands) == 0 {
+		t.Fatalf("unexpected spec root: %+v", spec)
+	}
+	if !strings.Contains(string(b), `"path": "tgs req baseline create"`) || !strings.Contains(string(b), `"name": "analyze"`) {
+		t.Errorf("spec missing self-parsed commands or flags:\n%s", b)
+	}
+}
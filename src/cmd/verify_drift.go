@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kelvin/tgsflow/src/core/config"
+	"github.com/kelvin/tgsflow/src/core/drift"
+	"github.com/kelvin/tgsflow/src/core/trace"
+)
+
+// CmdVerifyDrift checks that the paths, commands, config keys and Go symbols
+// referenced by the design docs and thoughts still exist.
+func CmdVerifyDrift(args []string) int {
+	fs := flag.NewFlagSet("tgs verify drift", flag.ContinueOnError)
+	repoRoot := fs.String("repo", ".", "Repository root path")
+	pathsFlag := fs.String("paths", "", "Comma-separated docs or directories to scan (default: design and thoughts dirs)")
+	format := fs.String("format", "text", "Output format: text|json")
+	ci := fs.Bool("ci", false, "CI mode: exit non-zero when references do not resolve")
+	fs.SetOutput(os.Stderr)
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if f := strings.ToLower(*format); f != "text" && f != "json" {
+		fmt.Fprintf(os.Stderr, "unknown --format %q; expected text|json\n", *format)
+		return 2
+	}
+
+	cfg, err := config.Load(*repoRoot)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to load config: %v\n", err)
+		if *ci {
+			return 1
+		}
+	}
+	dirs := splitPaths(*pathsFlag)
+	if len(dirs) == 0 {
+		opts := trace.OptionsFromConfig(cfg)
+		dirs = []string{opts.DesignDir, opts.ThoughtsDir}
+	}
+	var docs []string
+	for _, d := range dirs {
+		if strings.HasSuffix(d, ".md") {
+			docs = append(docs, filepath.ToSlash(d))
+			continue
+		}
+		found, err := drift.Documents(*repoRoot, []string{d})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "verify drift: %v\n", err)
+			return 1
+		}
+		docs = append(docs, found...)
+	}
+
+	symbols, err := drift.GoSymbols(*repoRoot)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "verify drift: %v\n", err)
+		return 1
+	}
+	checker := &drift.Checker{
+		RepoRoot: *repoRoot,
+		Commands: commandTree(NewRootCommand("dev", "", "")),
+		Config:   drift.ConfigKeys(config.Config{}),
+		Symbols:  symbols,
+	}
+	var refs []drift.Ref
+	for _, rel := range docs {
+		data, err := os.ReadFile(filepath.Join(*repoRoot, rel))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "verify drift: cannot read %s: %v\n", rel, err)
+			continue
+		}
+		refs = append(refs, drift.Extract(rel, string(data))...)
+	}
+	findings := checker.Check(refs)
+
+	if strings.EqualFold(*format, "json") {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		enc.SetEscapeHTML(false)
+		if findings == nil {
+			findings = []drift.Finding{}
+		}
+		if err := enc.Encode(findings); err != nil {
+			fmt.Fprintf(os.Stderr, "verify drift: %v\n", err)
+			return 1
+		}
+	} else {
+		for _, f := range findings {
+			fmt.Fprintln(os.Stderr, f)
+		}
+	}
+	fmt.Fprintf(os.Stderr, "verify drift: docs=%d refs=%d findings=%d\n", len(docs), len(refs), len(findings))
+	if len(findings) > 0 && *ci {
+		return 1
+	}
+	return 0
+}
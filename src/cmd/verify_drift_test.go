@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestVerifyDrift_CommandsAndPaths(t *testing.T) {
+	tree := commandTree(NewRootCommand("dev", "", ""))
+	for path, flag := range map[string]string{"tgs": "json", "tgs verify ears": "repo", "tgs agent exec": "prompt-text", "tgs trace impact": "base"} {
+		found := false
+		for _, f := range tree[path] {
+			found = found || f == flag
+		}
+		if !found {
+			t.Errorf("expected %q to accept --%s, got %v", path, flag, tree[path])
+		}
+	}
+
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "tgs", "design", "30_architecture.md"),
+		"- `tgs verify ears --ci` in `tgs/design/30_architecture.md`\n- `guardrails.ears.enable` and `config.Config`\n")
+	if code := CmdVerifyDrift([]string{"--repo", dir, "--ci"}); code != 0 {
+		t.Fatalf("expected code=0 when references resolve, got %d", code)
+	}
+	writeFile(t, filepath.Join(dir, "tgs", "design", "30_architecture.md"), "- `tgs verify ears --strict` reads `tgs/design/99_gone.md`\n")
+	if code := CmdVerifyDrift([]string{"--repo", dir, "--ci"}); code != 1 {
+		t.Fatalf("expected code=1 for drifted references, got %d", code)
+	}
+}
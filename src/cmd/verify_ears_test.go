@@ -19,6 +19,7 @@ func writeFile(t *testing.T, path string, content string) {
 	}
 }
 
+// Verifies: SR-009, NFR-003
 func TestVerify_EARS_AllValid(t *testing.T) {
 	dir := t.TempDir()
 	// Enable EARS
@@ -49,6 +50,7 @@ func TestVerify_EARS_AllValid(t *testing.T) {
 	}
 }
 
+// Verifies: SR-009, NFR-003
 func TestVerify_EARS_WithInvalid(t *testing.T) {
 	dir := t.TempDir()
 	writeFile(t, filepath.Join(dir, "tgs", "tgs.yml"), "guardrails:\n  ears:\n    enable: true\n")
@@ -73,6 +75,7 @@ func TestVerify_EARS_WithInvalid(t *testing.T) {
 	}
 }
 
+// Verifies: SR-026, IF-003
 func TestVerify_EARS_DesignDocs_Valid(t *testing.T) {
 	dir := t.TempDir()
 	// Enable EARS
@@ -92,6 +95,7 @@ func TestVerify_EARS_DesignDocs_Valid(t *testing.T) {
 	}
 }
 
+// Verifies: SR-026
 func TestVerify_EARS_DesignDocs_InvalidReportsPathLine(t *testing.T) {
 	dir := t.TempDir()
 	writeFile(t, filepath.Join(dir, "tgs", "tgs.yml"), "guardrails:\n  ears:\n    enable: true\n")
@@ -127,3 +131,174 @@ func TestVerify_EARS_DesignDocs_InvalidReportsPathLine(t *testing.T) {
 		t.Fatalf("expected stderr to contain path with line prefix, got: %q", stderr)
 	}
 }
+
+// Verifies: SR-008
+func TestVerify_EARS_NegativePolicy(t *testing.T) {
+	dir := t.TempDir()
+	reqs := "# System Requirements\n\n- **SR-001**: If the token is expired, then the gateway shall not forward the request.\n"
+	writeFile(t, filepath.Join(dir, "tgs", "design", "20_requirements.md"), reqs)
+	writeFile(t, filepath.Join(dir, "tgs", "design", "10_needs.md"), "# Needs\n")
+
+	// Allowed by default
+	writeFile(t, filepath.Join(dir, "tgs", "tgs.yml"), "guardrails:\n  ears:\n    enable: true\n")
+	if code := CmdVerifyEARS([]string{"--repo", dir, "--ci"}); code != 0 {
+		t.Fatalf("expected code=0 with negative requirements allowed, got %d", code)
+	}
+
+	// Rejected when policy disallows negatives
+	writeFile(t, filepath.Join(dir, "tgs", "tgs.yml"), "guardrails:\n  ears:\n    enable: true\n    allow_negative: false\n")
+	if code := CmdVerifyEARS([]string{"--repo", dir, "--ci"}); code != 1 {
+		t.Fatalf("expected code=1 with negative requirements disallowed, got %d", code)
+	}
+}
+
+// Verifies: SR-008
+func TestVerify_EARS_GlossaryFlagsUnknownSystem(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "tgs", "tgs.yml"), "guardrails:\n  ears:\n    enable: true\n")
+	writeFile(t, filepath.Join(dir, "tgs", "design", "glossary.yml"), "systems:\n  - name: tgs CLI\n    synonyms: [the CLI]\n")
+	writeFile(t, filepath.Join(dir, "tgs", "design", "10_needs.md"), "# Needs\n")
+	reqPath := filepath.Join(dir, "tgs", "design", "20_requirements.md")
+
+	writeFile(t, reqPath, "- **SR-001**: The tgs CLI shall report verification results.\n")
+	if code := CmdVerifyEARS([]string{"--repo", dir, "--ci"}); code != 0 {
+		t.Fatalf("expected code=0 for canonical system name, got %d", code)
+	}
+
+	writeFile(t, reqPath, "- **SR-001**: The CLI shall report verification results.\n")
+	if code := CmdVerifyEARS([]string{"--repo", dir, "--ci"}); code != 1 {
+		t.Fatalf("expected code=1 for synonym system name, got %d", code)
+	}
+}
+
+func TestVerify_EARS_GlossaryNamesInLocalizedSubject(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "tgs", "tgs.yml"), "guardrails:\n  ears:\n    enable: true\n    language: de\n")
+	writeFile(t, filepath.Join(dir, "tgs", "design", "glossary.yml"), "systems:\n  - name: Payment Service\n")
+	writeFile(t, filepath.Join(dir, "tgs", "design", "10_needs.md"), "# Needs\n")
+	writeFile(t, filepath.Join(dir, "tgs", "design", "20_requirements.md"),
+		"- **SR-001**: Wenn eine Zahlung eingeht, soll der Payment Service Belege erzeugen.\n")
+	if code := CmdVerifyEARS([]string{"--repo", dir, "--ci"}); code != 0 {
+		t.Fatalf("expected verify ears to keep the glossary name whole, got %d", code)
+	}
+}
+
+func TestVerify_EARS_AnalyzeReportsConflicts(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "tgs", "tgs.yml"), "guardrails:\n  ears:\n    enable: true\n")
+	writeFile(t, filepath.Join(dir, "tgs", "design", "10_needs.md"), "# Needs\n")
+	reqs := "- **SR-001**: If the token is expired, then the gateway shall forward the request.\n" +
+		"- **SR-002**: If the token is expired, then the gateway shall not forward the request.\n"
+	writeFile(t, filepath.Join(dir, "tgs", "design", "20_requirements.md"), reqs)
+
+	// Without --analyze the statements are individually valid
+	if code := CmdVerifyEARS([]string{"--repo", dir, "--ci"}); code != 0 {
+		t.Fatalf("expected code=0 without --analyze, got %d", code)
+	}
+
+	old := os.Stderr
+	r, w, _ := os.Pipe()
+	os.Stderr = w
+	code := CmdVerifyEARS([]string{"--repo", dir, "--ci", "--analyze"})
+	w.Close()
+	os.Stderr = old
+	out, _ := io.ReadAll(r)
+	if code != 1 {
+		t.Fatalf("expected code=1 with --analyze, got %d", code)
+	}
+	if !strings.Contains(string(out), "tgs/design/20_requirements.md:2: conflict: SR-001 and SR-002") {
+		t.Fatalf("expected conflict at path:line, got: %q", out)
+	}
+}
+
+func TestVerify_EARS_MeasurabilityAndJSONBound(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "tgs", "tgs.yml"), "guardrails:\n  ears:\n    enable: true\n")
+	writeFile(t, filepath.Join(dir, "tgs", "design", "10_needs.md"), "# Needs\n")
+	reqPath := filepath.Join(dir, "tgs", "design", "20_requirements.md")
+
+	writeFile(t, reqPath, "- **NFR-001**: When a brief is requested, the system shall respond quickly.\n")
+	if code := CmdVerifyEARS([]string{"--repo", dir, "--ci"}); code != 0 {
+		t.Fatalf("expected measurability to be off by default, got %d", code)
+	}
+	writeFile(t, filepath.Join(dir, "tgs", "tgs.yml"), "guardrails:\n  ears:\n    enable: true\n    require_measurable: true\n")
+	if code := CmdVerifyEARS([]string{"--repo", dir, "--ci"}); code != 1 {
+		t.Fatalf("expected code=1 for unbounded performance requirement, got %d", code)
+	}
+
+	writeFile(t, reqPath, "- **NFR-001**: When a brief is requested, the system shall respond within 30 seconds. (Verification: Test)\n")
+	old := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+	code := CmdVerifyEARS([]string{"--repo", dir, "--ci", "--format", "json"})
+	w.Close()
+	os.Stdout = old
+	out, _ := io.ReadAll(r)
+	if code != 0 {
+		t.Fatalf("expected code=0 for bounded requirement, got %d", code)
+	}
+	for _, want := range []string{`"id": "NFR-001"`, `"comparator": "<="`, `"value": 30`, `"unit": "s"`} {
+		if !strings.Contains(string(out), want) {
+			t.Fatalf("expected JSON to contain %s, got: %s", want, out)
+		}
+	}
+}
+
+func TestVerify_EARS_ConfiguredLanguage(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "tgs", "design", "10_needs.md"), "# Needs\n")
+	writeFile(t, filepath.Join(dir, "tgs", "design", "20_requirements.md"), "- **SR-001**: Lorsque le bouton est pressé, l'application doit démarrer.\n")
+
+	writeFile(t, filepath.Join(dir, "tgs", "tgs.yml"), "guardrails:\n  ears:\n    enable: true\n    language: fr\n")
+	if code := CmdVerifyEARS([]string{"--repo", dir, "--ci"}); code != 0 {
+		t.Fatalf("expected code=0 with language fr, got %d", code)
+	}
+	// Unknown profiles are a configuration error in CI mode
+	writeFile(t, filepath.Join(dir, "tgs", "tgs.yml"), "guardrails:\n  ears:\n    enable: true\n    language: xx\n")
+	if code := CmdVerifyEARS([]string{"--repo", dir, "--ci"}); code != 1 {
+		t.Fatalf("expected code=1 for unsupported language, got %d", code)
+	}
+}
+
+func TestVerify_GateUsesConfiguredLanguage(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "anforderungen.md"), "# Anforderungen\n\n- Wenn der Knopf gedrückt wird, soll das System Alarme auslösen\n- Das Gerät soll Ereignisse protokollieren\n")
+
+	writeFile(t, filepath.Join(dir, "tgs", "tgs.yml"), "guardrails:\n  ears:\n    enable: true\n    language: de\n")
+	if code := CmdVerify([]string{"--repo", dir, "--ci"}); code != 0 {
+		t.Fatalf("expected code=0 with language de, got %d", code)
+	}
+	// Without the profile the German lines fail the English grammar
+	writeFile(t, filepath.Join(dir, "tgs", "tgs.yml"), "guardrails:\n  ears:\n    enable: true\n")
+	if code := CmdVerify([]string{"--repo", dir, "--ci"}); code != 1 {
+		t.Fatalf("expected code=1 with language en, got %d", code)
+	}
+	// A per-file marker overrides the configured profile
+	writeFile(t, filepath.Join(dir, "anforderungen.md"), "# Anforderungen\n<!-- ears-language: de -->\n\n- Das Gerät soll Ereignisse protokollieren\n")
+	if code := CmdVerify([]string{"--repo", dir, "--ci"}); code != 0 {
+		t.Fatalf("expected code=0 with ears-language marker, got %d", code)
+	}
+}
+
+func TestVerify_EARS_WritesParseCache(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "tgs", "tgs.yml"), "guardrails:\n  ears:\n    enable: true\ntelemetry:\n  log_dir: logs\n")
+	writeFile(t, filepath.Join(dir, "tgs", "design", "10_needs.md"), "# Needs\n")
+	writeFile(t, filepath.Join(dir, "tgs", "design", "20_requirements.md"), "- **SR-001**: The system shall log.\n")
+	cachePath := filepath.Join(dir, "logs", "cache", "ears-cache.json")
+
+	if code := CmdVerifyEARS([]string{"--repo", dir, "--ci", "--no-cache"}); code != 0 {
+		t.Fatalf("expected code=0, got %d", code)
+	}
+	if _, err := os.Stat(cachePath); err == nil {
+		t.Fatalf("expected no cache with --no-cache")
+	}
+	for i := 0; i < 2; i++ {
+		if code := CmdVerifyEARS([]string{"--repo", dir, "--ci", "--workers", "2"}); code != 0 {
+			t.Fatalf("run %d: expected code=0, got %d", i, code)
+		}
+	}
+	if _, err := os.Stat(cachePath); err != nil {
+		t.Fatalf("expected cache file: %v", err)
+	}
+}
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kelvin/tgsflow/src/core/drift"
+)
+
+// CmdVerifyLinks checks the relative links and #anchors of the Markdown files
+// under tgs/ (or --paths), reporting broken ones as path:line.
+func CmdVerifyLinks(args []string) int {
+	fs := flag.NewFlagSet("tgs verify links", flag.ContinueOnError)
+	repoRoot := fs.String("repo", ".", "Repository root path")
+	pathsFlag := fs.String("paths", "tgs", "Comma-separated docs or directories to scan")
+	sources := fs.Bool("sources", false, "Also check brief source pointers of the form (Source: path#anchor)")
+	format := fs.String("format", "text", "Output format: text|json")
+	ci := fs.Bool("ci", false, "CI mode: exit non-zero when links are broken")
+	fs.SetOutput(os.Stderr)
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if f := strings.ToLower(*format); f != "text" && f != "json" {
+		fmt.Fprintf(os.Stderr, "unknown --format %q; expected text|json\n", *format)
+		return 2
+	}
+
+	var docs []string
+	for _, p := range splitPaths(*pathsFlag) {
+		if strings.HasSuffix(p, ".md") {
+			docs = append(docs, filepath.ToSlash(p))
+			continue
+		}
+		found, err := drift.Documents(*repoRoot, []string{p})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "verify links: %v\n", err)
+			return 1
+		}
+		docs = append(docs, found...)
+	}
+	var links []drift.Link
+	for _, rel := range docs {
+		data, err := os.ReadFile(filepath.Join(*repoRoot, rel))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "verify links: cannot read %s: %v\n", rel, err)
+			continue
+		}
+		links = append(links, drift.ExtractLinks(rel, string(data), *sources)...)
+	}
+	findings := drift.CheckLinks(*repoRoot, links)
+
+	if strings.EqualFold(*format, "json") {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		enc.SetEscapeHTML(false)
+		if findings == nil {
+			findings = []drift.Finding{}
+		}
+		if err := enc.Encode(findings); err != nil {
+			fmt.Fprintf(os.Stderr, "verify links: %v\n", err)
+			return 1
+		}
+	} else {
+		for _, f := range findings {
+			fmt.Fprintln(os.Stderr, f)
+		}
+	}
+	fmt.Fprintf(os.Stderr, "verify links: docs=%d links=%d broken=%d\n", len(docs), len(links), len(findings))
+	if len(findings) > 0 && *ci {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,19 @@
+package cmd
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestVerifyLinks_CI(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "tgs", "design", "20_requirements.md"), "# Requirements\n## Interfaces\n")
+	writeFile(t, filepath.Join(dir, "tgs", "README.md"), "See [interfaces](design/20_requirements.md#interfaces).\n")
+	if code := CmdVerifyLinks([]string{"--repo", dir, "--ci"}); code != 0 {
+		t.Fatalf("expected code=0 when links resolve, got %d", code)
+	}
+	writeFile(t, filepath.Join(dir, "tgs", "agentops", "prompts", "review.md"), "- [Review Checklist](../agentops/review_checklist.md)\n")
+	if code := CmdVerifyLinks([]string{"--repo", dir, "--ci", "--format", "json"}); code != 1 {
+		t.Fatalf("expected code=1 for a broken link, got %d", code)
+	}
+}
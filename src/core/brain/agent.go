@@ -0,0 +1,214 @@
+package brain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/kelvin/tgsflow/src/core/config"
+	"github.com/kelvin/tgsflow/src/core/toolpack"
+)
+
+// Agent stop reasons.
+const (
+	StopDone   = "done"   // the model answered without requesting tools
+	StopSteps  = "steps"  // the step budget ran out; the last reply was requested without tools
+	StopTokens = "tokens" // the token budget ran out; the last reply was requested without tools
+)
+
+// maxToolOutput caps the bytes of one tool result fed back to the model.
+const maxToolOutput = 16000
+
+// ErrTaskNotAllowed is returned when the toolpack is disabled or the task is
+// not listed in ai.toolpack.allow_for.
+var ErrTaskNotAllowed = errors.New("task not allowed by ai.toolpack")
+
+// Agent runs a tool-calling loop: it offers the toolpack tools declared in
+// ai.toolpack.tools, executes the calls the model returns locally inside the
+// toolpack sandbox, feeds the results back and stops when the model answers
+// or a budget runs out.
+type Agent struct {
+	Transport Transport
+	Env       *toolpack.Env
+	Task      string
+	MaxSteps  int // model round trips
+	MaxTokens int // total tokens across the run
+	ReplyMax  int // max_tokens of each request
+	Logf      func(format string, args ...any)
+}
+
+// AgentResult is the outcome of Agent.Run.
+type AgentResult struct {
+	Text      string
+	Steps     int
+	ToolCalls int
+	Tokens    int
+	Stop      string
+}
+
+// NewAgent prepares an agent for task. Budgets come from ai.toolpack.budgets:
+// agent_steps (default 8), agent_tokens (default 20000) and <task>_tokens for
+// each reply (default 1200).
+func NewAgent(tr Transport, env *toolpack.Env, task string) (*Agent, error) {
+	cfg := env.Config
+	if !ToolsAllowed(cfg, task) {
+		return nil, fmt.Errorf("%w: %s", ErrTaskNotAllowed, task)
+	}
+	return &Agent{
+		Transport: tr,
+		Env:       env,
+		Task:      task,
+		MaxSteps:  Budget(cfg, "agent_steps", 8),
+		MaxTokens: Budget(cfg, "agent_tokens", 20000),
+		ReplyMax:  Budget(cfg, task+"_tokens", 1200),
+	}, nil
+}
+
+// Tools returns the toolpack tools offered to the model: those declared in
+// ai.toolpack.tools that have a local implementation.
+func (a *Agent) Tools() []toolpack.Tool {
+	declared := map[string]bool{}
+	for _, t := range a.Env.Config.AI.Toolpack.Tools {
+		declared[t.Name] = true
+	}
+	var out []toolpack.Tool
+	for _, t := range a.Env.Tools() {
+		if declared[t.Name] {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// ToolsAllowed reports whether the toolpack is enabled and task is listed in
+// ai.toolpack.allow_for.
+func ToolsAllowed(cfg config.Config, task string) bool {
+	if !cfg.AI.Toolpack.Enabled {
+		return false
+	}
+	for _, t := range cfg.AI.Toolpack.AllowFor {
+		if t == task {
+			return true
+		}
+	}
+	return false
+}
+
+// Run converses with the model starting from system and prompt. Once the step
+// or token budget is used up, one last request is sent without tools so the
+// model has to write its answer from what it has gathered.
+func (a *Agent) Run(ctx context.Context, system, prompt string) (AgentResult, error) {
+	tools := a.Tools()
+	byName := make(map[string]toolpack.Tool, len(tools))
+	schemas := make([]Tool, 0, len(tools))
+	for _, t := range tools {
+		byName[t.Name] = t
+		schemas = append(schemas, Tool{Name: t.Name, Description: t.Description, JSONSchema: string(t.Schema)})
+	}
+	maxSteps := a.MaxSteps
+	if maxSteps <= 0 {
+		maxSteps = 1
+	}
+
+	var res AgentResult
+	msgs := []Msg{{Role: "user", Content: prompt}}
+	for {
+		final := ""
+		switch {
+		case a.MaxTokens > 0 && res.Tokens >= a.MaxTokens:
+			final = StopTokens
+		case res.Steps >= maxSteps-1:
+			final = StopSteps
+		}
+		req := ChatReq{System: system, Messages: msgs, MaxTokens: a.ReplyMax}
+		if final == "" {
+			req.Tools = schemas
+		}
+		resp, err := a.Transport.Chat(ctx, req)
+		if err != nil {
+			return res, err
+		}
+		res.Steps++
+		res.Tokens += usedTokens(req, resp)
+		if strings.TrimSpace(resp.Text) != "" {
+			res.Text = resp.Text
+		}
+		if final != "" {
+			res.Stop = final
+			return res, nil
+		}
+		if len(resp.ToolCalls) == 0 {
+			res.Stop = StopDone
+			return res, nil
+		}
+
+		msgs = append(msgs, Msg{Role: "assistant", Content: resp.Text, ToolCalls: resp.ToolCalls})
+		for _, call := range resp.ToolCalls {
+			res.ToolCalls++
+			out := a.runTool(ctx, byName, call)
+			a.logf("agent: %s -> %d bytes", call.Name, len(out))
+			msgs = append(msgs, Msg{Role: "tool", ToolCallID: call.ID, Content: out})
+		}
+	}
+}
+
+// runTool executes call and returns the text fed back to the model; failures
+// are reported to the model rather than aborting the run. Tools the toolpack
+// does not know are delegated to the transport when it can run tools (MCP).
+func (a *Agent) runTool(ctx context.Context, byName map[string]toolpack.Tool, call ToolCall) string {
+	var out string
+	var err error
+	if t, ok := byName[call.Name]; ok {
+		out, err = t.Run(ctx, toolArgs(call))
+	} else if r, ok := a.Transport.(ToolRunner); ok {
+		out, err = r.RunTool(ctx, call)
+		out = a.Env.Redactor.Apply(out)
+	} else {
+		err = fmt.Errorf("unknown tool %q", call.Name)
+	}
+	if err != nil {
+		return "error: " + a.Env.Redactor.Apply(err.Error())
+	}
+	return truncate(out, maxToolOutput)
+}
+
+// truncate cuts s to at most n bytes on a rune boundary and marks the cut.
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n] + "\n[truncated]"
+}
+
+func (a *Agent) logf(format string, args ...any) {
+	if a.Logf != nil {
+		a.Logf(format, args...)
+	}
+}
+
+// usedTokens prefers the provider's usage report and otherwise estimates
+// from the request and reply text.
+func usedTokens(req ChatReq, resp ChatResp) int {
+	if n := resp.Usage.Total(); n > 0 {
+		return n
+	}
+	n := toolpack.EstimateTokens(req.System) + toolpack.EstimateTokens(resp.Text)
+	for _, m := range req.Messages {
+		n += toolpack.EstimateTokens(m.Content)
+		for _, c := range m.ToolCalls {
+			n += toolpack.EstimateTokens(c.ArgsJSON)
+		}
+	}
+	for _, t := range req.Tools {
+		n += toolpack.EstimateTokens(t.Description + t.JSONSchema)
+	}
+	for _, c := range resp.ToolCalls {
+		n += toolpack.EstimateTokens(c.ArgsJSON)
+	}
+	return n
+}
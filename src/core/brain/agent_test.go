@@ -0,0 +1,184 @@
+package brain
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kelvin/tgsflow/src/core/config"
+	"github.com/kelvin/tgsflow/src/core/toolpack"
+)
+
+// scriptedTransport replays canned replies and records every request.
+type scriptedTransport struct {
+	replies []ChatResp
+	reqs    []ChatReq
+}
+
+func (s *scriptedTransport) Chat(_ context.Context, req ChatReq) (ChatResp, error) {
+	s.reqs = append(s.reqs, req)
+	if len(s.reqs) > len(s.replies) {
+		return ChatResp{}, errors.New("script exhausted")
+	}
+	return s.replies[len(s.reqs)-1], nil
+}
+
+func agentEnv(t *testing.T, cfg config.Config) *toolpack.Env {
+	t.Helper()
+	t.Setenv("TGS_THOUGHT_DIR", "")
+	root := t.TempDir()
+	for path, content := range map[string]string{
+		"tgs/design/20_requirements.md": "# Requirements\n\n## Refunds\n- **SR-001**: When a refund is requested, the system shall notify the payer.\n",
+		"deploy/prod.md":                "# Prod\n",
+	} {
+		p := filepath.Join(root, filepath.FromSlash(path))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	env, err := toolpack.NewEnv(root, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return env
+}
+
+func TestAgent_ExecutesToolCallsAndFeedsResults(t *testing.T) {
+	cfg := config.Default()
+	cfg.AI.Toolpack.Tools = cfg.AI.Toolpack.Tools[:3]
+	env := agentEnv(t, cfg)
+	tr := &scriptedTransport{replies: []ChatResp{
+		{ToolCalls: []ToolCall{
+			{ID: "c1", Name: "fetch_repo_text", ArgsJSON: `{"path":"tgs/design/20_requirements.md","start_line":3,"end_line":4}`},
+			{ID: "c2", Name: "fetch_repo_text", ArgsJSON: `{"path":"deploy/prod.md"}`},
+			{ID: "c3", Name: "drop_tables", ArgsJSON: `{}`},
+		}},
+		{Text: "# AI Brief\nSR-001 (Source: tgs/design/20_requirements.md#refunds)"},
+	}}
+	agent, err := NewAgent(tr, env, "context_pack")
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := agent.Run(context.Background(), "search", "refunds")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Stop != StopDone || res.Steps != 2 || res.ToolCalls != 3 || !strings.HasPrefix(res.Text, "# AI Brief") {
+		t.Fatalf("result = %+v", res)
+	}
+
+	// Only tools declared in ai.toolpack.tools are offered.
+	var offered []string
+	for _, tool := range tr.reqs[0].Tools {
+		offered = append(offered, tool.Name)
+	}
+	if got := strings.Join(offered, ","); got != "fetch_repo_text,list_candidates,propose_brief" {
+		t.Errorf("offered tools = %s", got)
+	}
+	if tr.reqs[0].MaxTokens != 1200 {
+		t.Errorf("max_tokens = %d", tr.reqs[0].MaxTokens)
+	}
+
+	msgs := tr.reqs[1].Messages
+	if len(msgs) != 5 || msgs[1].Role != "assistant" || len(msgs[1].ToolCalls) != 3 {
+		t.Fatalf("second request messages = %+v", msgs)
+	}
+	if m := msgs[2]; m.Role != "tool" || m.ToolCallID != "c1" || !strings.Contains(m.Content, "SR-001") {
+		t.Errorf("fetch result = %+v", m)
+	}
+	if m := msgs[3]; m.ToolCallID != "c2" || !strings.HasPrefix(m.Content, "error: ") || !strings.Contains(m.Content, "not allowed by guardrails") {
+		t.Errorf("deny_paths not enforced: %+v", m)
+	}
+	if m := msgs[4]; m.ToolCallID != "c3" || !strings.Contains(m.Content, `unknown tool "drop_tables"`) {
+		t.Errorf("unknown tool result = %+v", m)
+	}
+}
+
+func TestAgent_StepBudget(t *testing.T) {
+	cfg := config.Default()
+	cfg.AI.Toolpack.Budgets["agent_steps"] = 2
+	env := agentEnv(t, cfg)
+	call := ChatResp{Text: "looking", ToolCalls: []ToolCall{{ID: "c", Name: "list_candidates", ArgsJSON: `{"query":"refunds"}`}}}
+	tr := &scriptedTransport{replies: []ChatResp{call, call, call}}
+	agent, err := NewAgent(tr, env, "context_pack")
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := agent.Run(context.Background(), "search", "refunds")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Stop != StopSteps || res.Steps != 2 || res.Text != "looking" {
+		t.Fatalf("result = %+v", res)
+	}
+	if len(tr.reqs[1].Tools) != 0 {
+		t.Errorf("final step still offers tools: %d", len(tr.reqs[1].Tools))
+	}
+}
+
+// toolHungryTransport requests a tool whenever tools are offered and only
+// writes an answer when it is given none.
+type toolHungryTransport struct {
+	reqs []ChatReq
+}
+
+func (h *toolHungryTransport) Chat(_ context.Context, req ChatReq) (ChatResp, error) {
+	h.reqs = append(h.reqs, req)
+	if len(req.Tools) > 0 {
+		return ChatResp{ToolCalls: []ToolCall{{ID: "c", Name: "list_candidates", ArgsJSON: `{"query":"refunds"}`}}, Usage: Usage{InputTokens: 90, OutputTokens: 20}}, nil
+	}
+	return ChatResp{Text: "# AI Brief\nfinal", Usage: Usage{InputTokens: 120, OutputTokens: 10}}, nil
+}
+
+func TestAgent_TokenBudget(t *testing.T) {
+	cfg := config.Default()
+	cfg.AI.Toolpack.Budgets["agent_tokens"] = 100
+	env := agentEnv(t, cfg)
+	tr := &toolHungryTransport{}
+	agent, err := NewAgent(tr, env, "context_pack")
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := agent.Run(context.Background(), "search", "refunds")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Stop != StopTokens || res.Steps != 2 || res.ToolCalls != 1 || res.Tokens != 240 || res.Text != "# AI Brief\nfinal" {
+		t.Fatalf("result = %+v", res)
+	}
+	if len(tr.reqs) != 2 || len(tr.reqs[1].Tools) != 0 {
+		t.Fatalf("expected a final request without tools, got %d requests", len(tr.reqs))
+	}
+	if last := tr.reqs[1].Messages[len(tr.reqs[1].Messages)-1]; last.Role != "tool" {
+		t.Errorf("final request lacks the tool result: %+v", last)
+	}
+}
+
+func TestTruncate_RuneBoundary(t *testing.T) {
+	s := strings.Repeat("ä", 5) // 10 bytes
+	if got := truncate(s, 5); got != "ää\n[truncated]" {
+		t.Errorf("truncate = %q", got)
+	}
+	if got := truncate(s, 10); got != s {
+		t.Errorf("truncate within limit = %q", got)
+	}
+}
+
+func TestNewAgent_AllowFor(t *testing.T) {
+	cfg := config.Default()
+	env := agentEnv(t, cfg)
+	if _, err := NewAgent(&scriptedTransport{}, env, "write_code"); !errors.Is(err, ErrTaskNotAllowed) {
+		t.Errorf("task outside allow_for: %v", err)
+	}
+	cfg.AI.Toolpack.Enabled = false
+	env = agentEnv(t, cfg)
+	if _, err := NewAgent(&scriptedTransport{}, env, "context_pack"); !errors.Is(err, ErrTaskNotAllowed) {
+		t.Errorf("disabled toolpack: %v", err)
+	}
+}
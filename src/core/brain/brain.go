@@ -13,47 +13,67 @@ type ChatReq struct {
 	Tools     []Tool `json:"tools,omitempty"`
 	MaxTokens int    `json:"max_tokens"`
 }
+
+// Msg is a conversation turn. An assistant turn that requested tools carries
+// ToolCalls; the following "tool" turns answer them by ToolCallID.
 type Msg struct {
-	Role, Content string `json:"role","content"`
+	Role       string     `json:"role"`
+	Content    string     `json:"content"`
+	ToolCalls  []ToolCall `json:"tool_calls,omitempty"`
+	ToolCallID string     `json:"tool_call_id,omitempty"`
 }
 type Tool struct {
-	Name, Description, JSONSchema string `json:"name","description","json_schema"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	JSONSchema  string `json:"json_schema"` // JSON Schema of the arguments object
 }
 type ToolCall struct {
-	Name, ArgsJSON string `json:"name","args_json"`
+	ID       string `json:"id,omitempty"` // provider-assigned; echoed back in Msg.ToolCallID
+	Name     string `json:"name"`
+	ArgsJSON string `json:"args_json"`
 }
 type ChatResp struct {
 	Text      string     `json:"text"`
 	ToolCalls []ToolCall `json:"tool_calls"`
+	Usage     Usage      `json:"usage"`
+}
+
+// Usage is the token accounting reported by the provider; zero when the
+// backend does not report it.
+type Usage struct {
+	InputTokens  int `json:"input_tokens"`
+	OutputTokens int `json:"output_tokens"`
 }
 
+// Total is InputTokens plus OutputTokens.
+func (u Usage) Total() int { return u.InputTokens + u.OutputTokens }
+
 type Transport interface {
 	Chat(ctx context.Context, req ChatReq) (ChatResp, error)
 }
 
+// ToolRunner is implemented by transports whose backend can execute tools
+// itself (MCP servers); the result is the tool's text output.
+type ToolRunner interface {
+	RunTool(ctx context.Context, call ToolCall) (string, error)
+}
+
 func NewTransport(cfg config.Config) (Transport, error) {
 	switch cfg.AI.Mode {
 	case "shell":
 		return NewShellTransport(cfg), nil
 	case "mcp":
-		return NewMCPTransport(cfg) // small client; build-tag or optional dep
+		return NewMCPTransport(cfg)
 	case "proxy":
 		return NewProxyTransport(cfg), nil
 	case "sdk":
-		return NewSDKTransport(cfg), nil
+		return NewSDKTransport(cfg)
 	default:
 		return nil, fmt.Errorf("ai.mode %q unsupported", cfg.AI.Mode)
 	}
 }
 
-// helper budget
+// Budget reads ai.toolpack.budgets[key], falling back to def.
 func Budget(cfg config.Config, key string, def int) int {
-	if v, ok := cfg.AI.Toolpack.Budgets[key]; ok && v > 0 {
-		return v
-	}
-	return def
+	return cfg.AI.Toolpack.Budget(key, def)
 }
-
-// Usage in tgs context pack:
-// tr := NewTransport(cfg)
-// resp, _ := tr.Chat(ctx, ChatReq{System:..., Messages:..., Tools:..., MaxTokens:Budget(cfg,"context_pack_tokens",1200)})
@@ -20,9 +20,9 @@ func TestNewTransport_SupportedModes(t *testing.T) {
 			if tr == nil {
 				t.Fatalf("expected transport instance for mode %q", mode)
 			}
-			// Even stub transports should implement Chat and return an error.
+			// Every transport rejects an empty request without contacting a backend.
 			if _, chatErr := tr.Chat(context.Background(), ChatReq{}); chatErr == nil {
-				t.Fatalf("expected Chat to return error for stub transport in mode %q", mode)
+				t.Fatalf("expected Chat to return error for empty request in mode %q", mode)
 			}
 		})
 	}
@@ -0,0 +1,160 @@
+package brain
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/kelvin/tgsflow/src/core/config"
+)
+
+const (
+	// DefaultAnthropicEndpoint is used when ai.endpoint is empty.
+	DefaultAnthropicEndpoint = "https://api.anthropic.com"
+	// AnthropicVersion is sent as the anthropic-version header.
+	AnthropicVersion = "2023-06-01"
+)
+
+// anthropicTransport speaks the Anthropic Messages API (/v1/messages).
+type anthropicTransport struct {
+	url       string
+	model     string
+	maxTokens int // used when ChatReq.MaxTokens is unset; the API requires one
+	headers   map[string]string
+	http      httpDoer
+}
+
+func newAnthropicTransport(cfg config.Config) *anthropicTransport {
+	base := strings.TrimRight(strings.TrimSpace(cfg.AI.Endpoint), "/")
+	if base == "" {
+		base = DefaultAnthropicEndpoint
+	}
+	url := base
+	if !strings.HasSuffix(url, "/messages") {
+		url = strings.TrimSuffix(url, "/v1") + "/v1/messages"
+	}
+	t := &anthropicTransport{
+		url:       url,
+		model:     cfg.AI.Model,
+		maxTokens: Budget(cfg, "max_tokens", 1024),
+		headers:   map[string]string{"anthropic-version": AnthropicVersion},
+		http:      newHTTPDoer(cfg),
+	}
+	if key := apiKey(cfg); key != "" {
+		t.headers["x-api-key"] = key
+	}
+	return t
+}
+
+type anthropicBlock struct {
+	Type      string          `json:"type"`
+	Text      string          `json:"text,omitempty"`
+	ID        string          `json:"id,omitempty"`
+	Name      string          `json:"name,omitempty"`
+	Input     json.RawMessage `json:"input,omitempty"`
+	ToolUseID string          `json:"tool_use_id,omitempty"`
+	Content   string          `json:"content,omitempty"`
+}
+
+type anthropicMessage struct {
+	Role    string           `json:"role"`
+	Content []anthropicBlock `json:"content"`
+}
+
+type anthropicTool struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	InputSchema json.RawMessage `json:"input_schema"`
+}
+
+type anthropicRequest struct {
+	Model     string             `json:"model"`
+	MaxTokens int                `json:"max_tokens"`
+	System    string             `json:"system,omitempty"`
+	Messages  []anthropicMessage `json:"messages"`
+	Tools     []anthropicTool    `json:"tools,omitempty"`
+}
+
+type anthropicResponse struct {
+	Content    []anthropicBlock `json:"content"`
+	StopReason string           `json:"stop_reason"`
+	Usage      Usage            `json:"usage"`
+}
+
+func (t *anthropicTransport) Chat(ctx context.Context, req ChatReq) (ChatResp, error) {
+	if emptyRequest(req) {
+		return ChatResp{}, errors.New("empty prompt")
+	}
+	body := anthropicRequest{Model: t.model, MaxTokens: req.MaxTokens, System: req.System}
+	if body.MaxTokens <= 0 {
+		body.MaxTokens = t.maxTokens
+	}
+	for _, m := range req.Messages {
+		role, blocks := anthropicBlocks(m)
+		if role == "system" {
+			body.System = strings.TrimSpace(body.System + "\n\n" + m.Content)
+			continue
+		}
+		if len(blocks) == 0 {
+			continue
+		}
+		// The API expects alternating turns; fold consecutive tool results
+		// (and any other same-role messages) into one turn.
+		if n := len(body.Messages); n > 0 && body.Messages[n-1].Role == role {
+			body.Messages[n-1].Content = append(body.Messages[n-1].Content, blocks...)
+			continue
+		}
+		body.Messages = append(body.Messages, anthropicMessage{Role: role, Content: blocks})
+	}
+	for _, tool := range req.Tools {
+		body.Tools = append(body.Tools, anthropicTool{Name: tool.Name, Description: tool.Description, InputSchema: toolSchema(tool)})
+	}
+
+	var out anthropicResponse
+	if err := t.http.postJSON(ctx, t.url, t.headers, body, &out); err != nil {
+		return ChatResp{}, fmt.Errorf("anthropic transport: %w", err)
+	}
+	resp := ChatResp{Usage: out.Usage}
+	var text []string
+	for _, b := range out.Content {
+		switch b.Type {
+		case "text":
+			text = append(text, b.Text)
+		case "tool_use":
+			args := string(b.Input)
+			if args == "" {
+				args = "{}"
+			}
+			resp.ToolCalls = append(resp.ToolCalls, ToolCall{ID: b.ID, Name: b.Name, ArgsJSON: args})
+		}
+	}
+	resp.Text = strings.Join(text, "")
+	return resp, nil
+}
+
+// anthropicBlocks maps a Msg onto a Messages API role and content blocks:
+// tool results become user tool_result blocks, assistant tool calls tool_use blocks.
+func anthropicBlocks(m Msg) (string, []anthropicBlock) {
+	switch m.Role {
+	case "system":
+		return "system", nil
+	case "tool":
+		return "user", []anthropicBlock{{Type: "tool_result", ToolUseID: m.ToolCallID, Content: m.Content}}
+	case "assistant":
+		var blocks []anthropicBlock
+		if m.Content != "" {
+			blocks = append(blocks, anthropicBlock{Type: "text", Text: m.Content})
+		}
+		for _, c := range m.ToolCalls {
+			blocks = append(blocks, anthropicBlock{Type: "tool_use", ID: c.ID, Name: c.Name, Input: toolArgs(c)})
+		}
+		return "assistant", blocks
+	default:
+		if m.Content == "" {
+			return "user", nil
+		}
+		return "user", []anthropicBlock{{Type: "text", Text: m.Content}}
+	}
+}
@@ -0,0 +1,169 @@
+package brain
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kelvin/tgsflow/src/core/config"
+)
+
+// httpDoer posts JSON to an LLM API with the timeout and retry policy of
+// config.AI. It is shared by the HTTP transports.
+type httpDoer struct {
+	client      *http.Client
+	maxAttempts int
+	backoff     time.Duration
+}
+
+func newHTTPDoer(cfg config.Config) httpDoer {
+	d := httpDoer{client: &http.Client{}, maxAttempts: cfg.AI.Retry.MaxAttempts, backoff: time.Duration(cfg.AI.Retry.BackoffMS) * time.Millisecond}
+	if cfg.AI.TimeoutMS > 0 {
+		d.client.Timeout = time.Duration(cfg.AI.TimeoutMS) * time.Millisecond
+	}
+	if d.maxAttempts < 1 {
+		d.maxAttempts = 1
+	}
+	return d
+}
+
+// apiError is a non-2xx response.
+type apiError struct {
+	Status int
+	Body   string
+}
+
+func (e *apiError) Error() string {
+	msg := strings.TrimSpace(e.Body)
+	var parsed struct {
+		Error struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if json.Unmarshal([]byte(e.Body), &parsed) == nil && parsed.Error.Message != "" {
+		msg = parsed.Error.Message
+	}
+	return fmt.Sprintf("HTTP %d: %s", e.Status, msg)
+}
+
+// maxRetryAfter caps the wait requested by a Retry-After header.
+const maxRetryAfter = time.Minute
+
+// postJSON sends body to url and decodes the response into out. Rate limits
+// (429) and server errors (5xx) are retried with linear backoff, or after the
+// delay named by the response's Retry-After header.
+func (d httpDoer) postJSON(ctx context.Context, url string, headers map[string]string, body, out any) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	var (
+		lastErr    error
+		retryAfter time.Duration
+	)
+	for attempt := 1; attempt <= d.maxAttempts; attempt++ {
+		if attempt > 1 {
+			wait := time.Duration(attempt-1) * d.backoff
+			if retryAfter > 0 {
+				wait = retryAfter
+			}
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(wait):
+			}
+		}
+		retryAfter = 0
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		resp, err := d.client.Do(req)
+		if err != nil {
+			lastErr = err
+			if ctx.Err() != nil {
+				return err
+			}
+			continue
+		}
+		data, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if resp.StatusCode/100 != 2 {
+			lastErr = &apiError{Status: resp.StatusCode, Body: string(data)}
+			if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+				retryAfter = parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+				continue
+			}
+			return lastErr
+		}
+		if err := json.Unmarshal(data, out); err != nil {
+			return fmt.Errorf("decode response: %w", err)
+		}
+		return nil
+	}
+	return lastErr
+}
+
+// parseRetryAfter returns the delay of a Retry-After header given in seconds or
+// as an HTTP date, capped at maxRetryAfter; 0 when absent or invalid.
+func parseRetryAfter(v string, now time.Time) time.Duration {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return 0
+	}
+	var d time.Duration
+	if secs, err := strconv.Atoi(v); err == nil {
+		d = time.Duration(secs) * time.Second
+	} else if t, err := http.ParseTime(v); err == nil {
+		d = t.Sub(now)
+	}
+	if d < 0 {
+		return 0
+	}
+	return min(d, maxRetryAfter)
+}
+
+// apiKey reads the key named by ai.api_key_env, if any.
+func apiKey(cfg config.Config) string {
+	if env := strings.TrimSpace(cfg.AI.APIKeyEnv); env != "" {
+		return os.Getenv(env)
+	}
+	return ""
+}
+
+// toolSchema returns the tool's argument schema, defaulting to an open object.
+func toolSchema(t Tool) json.RawMessage {
+	if s := strings.TrimSpace(t.JSONSchema); s != "" && json.Valid([]byte(s)) {
+		return json.RawMessage(s)
+	}
+	return json.RawMessage(`{"type":"object"}`)
+}
+
+// emptyRequest reports whether req has nothing to send; HTTP transports reject
+// it before making a call.
+func emptyRequest(req ChatReq) bool {
+	return strings.TrimSpace(req.System) == "" && len(req.Messages) == 0
+}
+
+// toolArgs returns a tool call's arguments as a JSON object.
+func toolArgs(c ToolCall) json.RawMessage {
+	if s := strings.TrimSpace(c.ArgsJSON); s != "" && json.Valid([]byte(s)) {
+		return json.RawMessage(s)
+	}
+	return json.RawMessage(`{}`)
+}
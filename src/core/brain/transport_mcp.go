@@ -0,0 +1,263 @@
+package brain
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kelvin/tgsflow/src/core/config"
+	"github.com/kelvin/tgsflow/src/core/jsonrpc"
+	"github.com/kelvin/tgsflow/src/core/mcp"
+)
+
+// mcpTransport talks to an MCP server: completions go through
+// sampling/createMessage (for gateways that front a model) and the server's
+// tools are offered to the model alongside the caller's. The connection is
+// opened on first use and reused until a transport error, after which the next
+// call reconnects.
+type mcpTransport struct {
+	command   []string
+	env       []string
+	url       string
+	headers   map[string]string
+	timeout   time.Duration
+	maxTokens int
+
+	mu     sync.Mutex
+	client *mcp.Client
+	tools  []Tool // server tools, listed once per connection
+}
+
+// NewMCPTransport returns a transport for ai.mode "mcp" configured by ai.mcp.
+func NewMCPTransport(cfg config.Config) (Transport, error) {
+	t := &mcpTransport{
+		command:   cfg.AI.MCP.Command,
+		url:       strings.TrimSpace(cfg.AI.MCP.URL),
+		headers:   map[string]string{},
+		timeout:   time.Duration(cfg.AI.TimeoutMS) * time.Millisecond,
+		maxTokens: Budget(cfg, "max_tokens", 1024),
+	}
+	if len(t.command) > 0 && t.url != "" {
+		return nil, errors.New("ai.mcp: set either command or url, not both")
+	}
+	if len(t.command) == 0 && t.url == "" {
+		t.url = strings.TrimSpace(cfg.AI.Endpoint)
+	}
+	for k, v := range cfg.AI.MCP.Env {
+		t.env = append(t.env, k+"="+v)
+	}
+	sort.Strings(t.env)
+	if key := apiKey(cfg); key != "" && t.url != "" {
+		t.headers["Authorization"] = "Bearer " + key
+	}
+	return t, nil
+}
+
+// connect dials the server on first use; the caller holds t.mu.
+func (t *mcpTransport) connect(ctx context.Context) (*mcp.Client, error) {
+	if t.client != nil {
+		return t.client, nil
+	}
+	var c *mcp.Client
+	var err error
+	switch {
+	case len(t.command) > 0:
+		c, err = mcp.DialStdio(ctx, t.command, t.env)
+	case t.url != "":
+		c, err = mcp.DialHTTP(ctx, t.url, t.headers)
+	default:
+		return nil, errors.New("ai.mode mcp requires ai.mcp.command or ai.mcp.url")
+	}
+	if err != nil {
+		return nil, err
+	}
+	var tools []Tool
+	if c.HasCapability("tools") {
+		list, err := c.ListTools(ctx)
+		if err != nil {
+			c.Close()
+			return nil, fmt.Errorf("mcp: tools/list: %w", err)
+		}
+		tools = fromMCPTools(list)
+	}
+	t.client, t.tools = c, tools
+	return c, nil
+}
+
+// release drops c after a transport failure so the next call reconnects.
+// JSON-RPC errors returned by the server leave the session in place.
+func (t *mcpTransport) release(c *mcp.Client, err error) {
+	var rpcErr *jsonrpc.Error
+	if err == nil || errors.As(err, &rpcErr) {
+		return
+	}
+	t.mu.Lock()
+	if t.client == c {
+		t.client, t.tools = nil, nil
+	}
+	t.mu.Unlock()
+	_ = c.Close()
+}
+
+func (t *mcpTransport) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if t.timeout > 0 {
+		return context.WithTimeout(ctx, t.timeout)
+	}
+	return context.WithCancel(ctx)
+}
+
+// Tools returns the server's tools mapped for ChatReq.Tools.
+func (t *mcpTransport) Tools(ctx context.Context) ([]Tool, error) {
+	ctx, cancel := t.withTimeout(ctx)
+	defer cancel()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, err := t.connect(ctx); err != nil {
+		return nil, fmt.Errorf("mcp transport: %w", err)
+	}
+	return append([]Tool(nil), t.tools...), nil
+}
+
+// RunTool executes one of the server's tools.
+func (t *mcpTransport) RunTool(ctx context.Context, call ToolCall) (string, error) {
+	ctx, cancel := t.withTimeout(ctx)
+	defer cancel()
+	t.mu.Lock()
+	c, err := t.connect(ctx)
+	t.mu.Unlock()
+	if err != nil {
+		return "", fmt.Errorf("mcp transport: %w", err)
+	}
+	res, err := c.CallTool(ctx, call.Name, toolArgs(call))
+	t.release(c, err)
+	if err != nil {
+		return "", fmt.Errorf("mcp transport: %s: %w", call.Name, err)
+	}
+	if res.IsError {
+		return "", fmt.Errorf("mcp transport: %s: %s", call.Name, res.Text())
+	}
+	return res.Text(), nil
+}
+
+// Close ends the MCP session.
+func (t *mcpTransport) Close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.client == nil {
+		return nil
+	}
+	err := t.client.Close()
+	t.client = nil
+	return err
+}
+
+func (t *mcpTransport) Chat(ctx context.Context, req ChatReq) (ChatResp, error) {
+	if emptyRequest(req) {
+		return ChatResp{}, errors.New("empty prompt")
+	}
+	ctx, cancel := t.withTimeout(ctx)
+	defer cancel()
+	t.mu.Lock()
+	c, err := t.connect(ctx)
+	serverTools := t.tools
+	t.mu.Unlock()
+	if err != nil {
+		return ChatResp{}, fmt.Errorf("mcp transport: %w", err)
+	}
+
+	params := mcp.CreateMessageParams{SystemPrompt: req.System, MaxTokens: req.MaxTokens}
+	if params.MaxTokens <= 0 {
+		params.MaxTokens = t.maxTokens
+	}
+	for _, m := range req.Messages {
+		if m.Role == "system" {
+			params.SystemPrompt = strings.TrimSpace(params.SystemPrompt + "\n\n" + m.Content)
+			continue
+		}
+		sm, err := samplingMessage(m)
+		if err != nil {
+			return ChatResp{}, err
+		}
+		params.Messages = append(params.Messages, sm)
+	}
+	seen := map[string]bool{}
+	for _, tool := range append(append([]Tool(nil), req.Tools...), serverTools...) {
+		if seen[tool.Name] {
+			continue
+		}
+		seen[tool.Name] = true
+		params.Tools = append(params.Tools, mcp.Tool{Name: tool.Name, Description: tool.Description, InputSchema: toolSchema(tool)})
+	}
+
+	res, err := c.CreateMessage(ctx, params)
+	t.release(c, err)
+	if err != nil {
+		return ChatResp{}, fmt.Errorf("mcp transport: sampling/createMessage: %w", err)
+	}
+	blocks, err := res.Blocks()
+	if err != nil {
+		return ChatResp{}, fmt.Errorf("mcp transport: decode sampling result: %w", err)
+	}
+	var resp ChatResp
+	var text []string
+	for _, b := range blocks {
+		switch b.Type {
+		case "text":
+			text = append(text, b.Text)
+		case "tool_use":
+			args := string(b.Input)
+			if args == "" {
+				args = "{}"
+			}
+			resp.ToolCalls = append(resp.ToolCalls, ToolCall{ID: b.ID, Name: b.Name, ArgsJSON: args})
+		}
+	}
+	resp.Text = strings.Join(text, "")
+	return resp, nil
+}
+
+// samplingMessage maps a Msg onto MCP sampling content: plain turns carry one
+// text block, tool calls and results become tool_use/tool_result blocks.
+func samplingMessage(m Msg) (mcp.SamplingMessage, error) {
+	var content any
+	role := "user"
+	switch m.Role {
+	case "assistant":
+		role = "assistant"
+		if len(m.ToolCalls) == 0 {
+			content = mcp.Content{Type: "text", Text: m.Content}
+			break
+		}
+		var blocks []mcp.Content
+		if m.Content != "" {
+			blocks = append(blocks, mcp.Content{Type: "text", Text: m.Content})
+		}
+		for _, c := range m.ToolCalls {
+			blocks = append(blocks, mcp.Content{Type: "tool_use", ID: c.ID, Name: c.Name, Input: toolArgs(c)})
+		}
+		content = blocks
+	case "tool":
+		content = []mcp.Content{{Type: "tool_result", ToolUseID: m.ToolCallID, Content: []mcp.Content{{Type: "text", Text: m.Content}}}}
+	default:
+		content = mcp.Content{Type: "text", Text: m.Content}
+	}
+	data, err := json.Marshal(content)
+	return mcp.SamplingMessage{Role: role, Content: data}, err
+}
+
+func fromMCPTools(list []mcp.Tool) []Tool {
+	out := make([]Tool, 0, len(list))
+	for _, t := range list {
+		desc := t.Description
+		if desc == "" {
+			desc = t.Title
+		}
+		out = append(out, Tool{Name: t.Name, Description: desc, JSONSchema: string(t.InputSchema)})
+	}
+	return out
+}
@@ -0,0 +1,162 @@
+package brain
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/kelvin/tgsflow/src/core/config"
+	"github.com/kelvin/tgsflow/src/core/jsonrpc"
+	"github.com/kelvin/tgsflow/src/core/mcp"
+	"github.com/kelvin/tgsflow/src/core/mcp/mcptest"
+)
+
+// TestMain doubles as the stdio fake MCP server for TestMCPTransport_Stdio.
+func TestMain(m *testing.M) {
+	if os.Getenv("TGS_MCP_FAKE_SERVER") == "1" {
+		if err := mcptest.Default().ServeConn(jsonrpc.NewLineConn(os.Stdin, os.Stdout)); err != nil {
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+func TestMCPTransport_Stdio(t *testing.T) {
+	cfg := config.Default()
+	cfg.AI.Mode = "mcp"
+	cfg.AI.MCP.Command = []string{os.Args[0], "-test.run=^$"}
+	cfg.AI.MCP.Env = map[string]string{"TGS_MCP_FAKE_SERVER": "1"}
+	tr, err := NewTransport(cfg)
+	if err != nil {
+		t.Fatalf("NewTransport: %v", err)
+	}
+	defer tr.(*mcpTransport).Close()
+
+	resp, err := tr.Chat(context.Background(), ChatReq{System: "be brief", Messages: []Msg{{Role: "user", Content: "hello"}}})
+	if err != nil {
+		t.Fatalf("Chat: %v", err)
+	}
+	if resp.Text != "echo: hello" {
+		t.Fatalf("Text = %q", resp.Text)
+	}
+	out, err := tr.(ToolRunner).RunTool(context.Background(), ToolCall{Name: "lookup", ArgsJSON: `{"term":"EARS"}`})
+	if err != nil || out != `lookup {"term":"EARS"}` {
+		t.Fatalf("RunTool = %q, %v", out, err)
+	}
+}
+
+func TestMCPTransport_HTTPToolsAndToolUse(t *testing.T) {
+	fake := mcptest.Default()
+	var got mcp.CreateMessageParams
+	fake.Sample = func(p mcp.CreateMessageParams) mcp.CreateMessageResult {
+		got = p
+		content, _ := json.Marshal([]mcp.Content{{Type: "tool_use", ID: "t1", Name: "lookup", Input: json.RawMessage(`{"term":"SR"}`)}})
+		return mcp.CreateMessageResult{Role: "assistant", Content: content, StopReason: "toolUse"}
+	}
+	fake.CallTool = func(name string, args json.RawMessage) mcp.CallToolResult {
+		return mcp.CallToolResult{IsError: true, Content: []mcp.Content{{Type: "text", Text: "unknown term"}}}
+	}
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+
+	cfg := config.Default()
+	cfg.AI.Mode = "mcp"
+	cfg.AI.Endpoint = srv.URL
+	tr, err := NewMCPTransport(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mt := tr.(*mcpTransport)
+	defer mt.Close()
+
+	tools, err := mt.Tools(context.Background())
+	if err != nil || len(tools) != 1 || tools[0].Name != "lookup" || !strings.Contains(tools[0].JSONSchema, `"term"`) {
+		t.Fatalf("Tools = %+v, %v", tools, err)
+	}
+	resp, err := tr.Chat(context.Background(), ChatReq{
+		Messages: []Msg{
+			{Role: "user", Content: "define SR"},
+			{Role: "assistant", ToolCalls: []ToolCall{{ID: "t0", Name: "fetch_repo_text", ArgsJSON: `{"path":"a.md"}`}}},
+			{Role: "tool", ToolCallID: "t0", Content: "SR means system requirement"},
+		},
+		Tools: []Tool{{Name: "fetch_repo_text"}},
+	})
+	if err != nil {
+		t.Fatalf("Chat: %v", err)
+	}
+	if len(resp.ToolCalls) != 1 || resp.ToolCalls[0].ID != "t1" || resp.ToolCalls[0].ArgsJSON != `{"term":"SR"}` {
+		t.Fatalf("ToolCalls = %+v", resp.ToolCalls)
+	}
+	if len(got.Tools) != 2 || got.Tools[0].Name != "fetch_repo_text" || got.Tools[1].Name != "lookup" || got.MaxTokens != 1024 {
+		t.Errorf("sampling params tools=%+v max=%d", got.Tools, got.MaxTokens)
+	}
+	if len(got.Messages) != 3 || !strings.Contains(string(got.Messages[1].Content), `"tool_use"`) || !strings.Contains(string(got.Messages[2].Content), `"toolUseId":"t0"`) {
+		t.Errorf("sampling messages = %+v", got.Messages)
+	}
+	if _, err := mt.RunTool(context.Background(), resp.ToolCalls[0]); err == nil || !strings.Contains(err.Error(), "unknown term") {
+		t.Errorf("RunTool error = %v", err)
+	}
+}
+
+func TestMCPTransport_ReconnectsAfterTransportError(t *testing.T) {
+	fake := mcptest.Default()
+	var failing atomic.Bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failing.Load() {
+			http.Error(w, "bad gateway", http.StatusBadGateway)
+			return
+		}
+		fake.ServeHTTP(w, r)
+	}))
+	defer srv.Close()
+
+	cfg := config.Default()
+	cfg.AI.Mode = "mcp"
+	cfg.AI.Endpoint = srv.URL
+	tr, err := NewMCPTransport(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mt := tr.(*mcpTransport)
+	defer mt.Close()
+	req := ChatReq{Messages: []Msg{{Role: "user", Content: "hello"}}}
+	if _, err := tr.Chat(context.Background(), req); err != nil {
+		t.Fatalf("Chat: %v", err)
+	}
+
+	failing.Store(true)
+	if _, err := tr.Chat(context.Background(), req); err == nil {
+		t.Fatal("expected a transport error")
+	}
+	if mt.client != nil {
+		t.Fatal("expected the failed session to be dropped")
+	}
+	failing.Store(false)
+	resp, err := tr.Chat(context.Background(), req)
+	if err != nil || resp.Text != "echo: hello" {
+		t.Fatalf("expected a reconnect, got %+v, %v", resp, err)
+	}
+}
+
+func TestMCPTransport_Config(t *testing.T) {
+	cfg := config.Default()
+	cfg.AI.MCP.Command = []string{"server"}
+	cfg.AI.MCP.URL = "http://localhost:1"
+	if _, err := NewMCPTransport(cfg); err == nil {
+		t.Fatal("expected error when both command and url are set")
+	}
+	tr, err := NewMCPTransport(config.Default())
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = tr.Chat(context.Background(), ChatReq{Messages: []Msg{{Role: "user", Content: "hi"}}})
+	if err == nil || !strings.Contains(err.Error(), "ai.mcp.command or ai.mcp.url") {
+		t.Fatalf("expected configuration error, got %v", err)
+	}
+}
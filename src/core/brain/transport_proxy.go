@@ -0,0 +1,137 @@
+package brain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/kelvin/tgsflow/src/core/config"
+)
+
+// DefaultOpenAIEndpoint is used when ai.endpoint is empty.
+const DefaultOpenAIEndpoint = "https://api.openai.com/v1"
+
+// proxyTransport speaks the OpenAI-compatible /v1/chat/completions API, as
+// served by OpenAI itself and by most internal LLM gateways.
+type proxyTransport struct {
+	label   string // error prefix: "proxy transport", "azure transport", ...
+	url     string
+	model   string
+	headers map[string]string
+	http    httpDoer
+}
+
+// NewProxyTransport returns a transport for ai.mode "proxy". ai.endpoint may be
+// a base URL ("https://llm.internal" or ".../v1") or the full completions URL;
+// the key named by ai.api_key_env is sent as a bearer token.
+func NewProxyTransport(cfg config.Config) Transport {
+	t := &proxyTransport{label: "proxy transport", url: chatCompletionsURL(cfg.AI.Endpoint), model: cfg.AI.Model, headers: map[string]string{}, http: newHTTPDoer(cfg)}
+	if key := apiKey(cfg); key != "" {
+		t.headers["Authorization"] = "Bearer " + key
+	}
+	return t
+}
+
+func chatCompletionsURL(endpoint string) string {
+	u := strings.TrimRight(strings.TrimSpace(endpoint), "/")
+	switch {
+	case u == "":
+		u = DefaultOpenAIEndpoint
+	case strings.HasSuffix(u, "/chat/completions"):
+		return u
+	case !strings.HasSuffix(u, "/v1"):
+		u += "/v1"
+	}
+	return u + "/chat/completions"
+}
+
+type openAIMessage struct {
+	Role       string           `json:"role"`
+	Content    *string          `json:"content"`
+	ToolCalls  []openAIToolCall `json:"tool_calls,omitempty"`
+	ToolCallID string           `json:"tool_call_id,omitempty"`
+}
+
+type openAIToolCall struct {
+	ID       string `json:"id"`
+	Type     string `json:"type"`
+	Function struct {
+		Name      string `json:"name"`
+		Arguments string `json:"arguments"`
+	} `json:"function"`
+}
+
+type openAITool struct {
+	Type     string `json:"type"`
+	Function struct {
+		Name        string `json:"name"`
+		Description string `json:"description,omitempty"`
+		Parameters  any    `json:"parameters"`
+	} `json:"function"`
+}
+
+type openAIRequest struct {
+	Model     string          `json:"model"`
+	Messages  []openAIMessage `json:"messages"`
+	Tools     []openAITool    `json:"tools,omitempty"`
+	MaxTokens int             `json:"max_tokens,omitempty"`
+}
+
+type openAIResponse struct {
+	Choices []struct {
+		Message      openAIMessage `json:"message"`
+		FinishReason string        `json:"finish_reason"`
+	} `json:"choices"`
+	Usage struct {
+		PromptTokens     int `json:"prompt_tokens"`
+		CompletionTokens int `json:"completion_tokens"`
+	} `json:"usage"`
+}
+
+func (t *proxyTransport) Chat(ctx context.Context, req ChatReq) (ChatResp, error) {
+	if emptyRequest(req) {
+		return ChatResp{}, errors.New("empty prompt")
+	}
+	body := openAIRequest{Model: t.model, MaxTokens: req.MaxTokens}
+	if strings.TrimSpace(req.System) != "" {
+		body.Messages = append(body.Messages, openAIMessage{Role: "system", Content: &req.System})
+	}
+	for _, m := range req.Messages {
+		om := openAIMessage{Role: m.Role, ToolCallID: m.ToolCallID}
+		if om.Role == "" {
+			om.Role = "user"
+		}
+		if content := m.Content; content != "" || len(m.ToolCalls) == 0 {
+			om.Content = &content
+		}
+		for _, c := range m.ToolCalls {
+			oc := openAIToolCall{ID: c.ID, Type: "function"}
+			oc.Function.Name, oc.Function.Arguments = c.Name, c.ArgsJSON
+			om.ToolCalls = append(om.ToolCalls, oc)
+		}
+		body.Messages = append(body.Messages, om)
+	}
+	for _, tool := range req.Tools {
+		ot := openAITool{Type: "function"}
+		ot.Function.Name, ot.Function.Description, ot.Function.Parameters = tool.Name, tool.Description, toolSchema(tool)
+		body.Tools = append(body.Tools, ot)
+	}
+
+	var out openAIResponse
+	if err := t.http.postJSON(ctx, t.url, t.headers, body, &out); err != nil {
+		return ChatResp{}, fmt.Errorf("%s: %w", t.label, err)
+	}
+	if len(out.Choices) == 0 {
+		return ChatResp{}, fmt.Errorf("%s: response has no choices", t.label)
+	}
+	msg := out.Choices[0].Message
+	resp := ChatResp{Usage: Usage{InputTokens: out.Usage.PromptTokens, OutputTokens: out.Usage.CompletionTokens}}
+	if msg.Content != nil {
+		resp.Text = *msg.Content
+	}
+	for _, c := range msg.ToolCalls {
+		resp.ToolCalls = append(resp.ToolCalls, ToolCall{ID: c.ID, Name: c.Function.Name, ArgsJSON: c.Function.Arguments})
+	}
+	return resp, nil
+}
@@ -0,0 +1,178 @@
+package brain
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kelvin/tgsflow/src/core/config"
+)
+
+func proxyConfig(t *testing.T, endpoint string) config.Config {
+	t.Helper()
+	t.Setenv("TGS_TEST_PROXY_KEY", "sk-test")
+	cfg := config.Default()
+	cfg.AI.Mode = "proxy"
+	cfg.AI.Endpoint = endpoint
+	cfg.AI.APIKeyEnv = "TGS_TEST_PROXY_KEY"
+	cfg.AI.Model = "test-model"
+	cfg.AI.Retry.BackoffMS = 1
+	return cfg
+}
+
+func TestProxyTransport_ToolCallRoundTrip(t *testing.T) {
+	var got openAIRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/chat/completions" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		if auth := r.Header.Get("Authorization"); auth != "Bearer sk-test" {
+			t.Errorf("Authorization = %q", auth)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"choices":[{"finish_reason":"tool_calls","message":{"role":"assistant","content":null,
+			"tool_calls":[{"id":"call_1","type":"function","function":{"name":"fetch_repo_text","arguments":"{\"path\":\"README.md\"}"}}]}}],
+			"usage":{"prompt_tokens":42,"completion_tokens":7}}`))
+	}))
+	defer srv.Close()
+
+	tr := NewProxyTransport(proxyConfig(t, srv.URL))
+	resp, err := tr.Chat(context.Background(), ChatReq{
+		System: "be brief",
+		Messages: []Msg{
+			{Role: "user", Content: "summarise"},
+			{Role: "assistant", ToolCalls: []ToolCall{{ID: "call_0", Name: "list_candidates", ArgsJSON: `{}`}}},
+			{Role: "tool", ToolCallID: "call_0", Content: "README.md"},
+		},
+		Tools:     []Tool{{Name: "fetch_repo_text", Description: "Read a file", JSONSchema: `{"type":"object","properties":{"path":{"type":"string"}}}`}},
+		MaxTokens: 256,
+	})
+	if err != nil {
+		t.Fatalf("Chat: %v", err)
+	}
+	if len(resp.ToolCalls) != 1 || resp.ToolCalls[0].ID != "call_1" || resp.ToolCalls[0].Name != "fetch_repo_text" || resp.ToolCalls[0].ArgsJSON != `{"path":"README.md"}` {
+		t.Fatalf("tool calls = %+v", resp.ToolCalls)
+	}
+	if resp.Usage != (Usage{InputTokens: 42, OutputTokens: 7}) {
+		t.Errorf("usage = %+v", resp.Usage)
+	}
+
+	if got.Model != "test-model" || got.MaxTokens != 256 {
+		t.Errorf("model/max_tokens = %q/%d", got.Model, got.MaxTokens)
+	}
+	if len(got.Messages) != 4 || got.Messages[0].Role != "system" || *got.Messages[0].Content != "be brief" {
+		t.Fatalf("messages = %+v", got.Messages)
+	}
+	if call := got.Messages[2]; call.Content != nil || len(call.ToolCalls) != 1 || call.ToolCalls[0].Function.Name != "list_candidates" {
+		t.Errorf("assistant tool call message = %+v", call)
+	}
+	if got.Messages[3].ToolCallID != "call_0" {
+		t.Errorf("tool result message = %+v", got.Messages[3])
+	}
+	if len(got.Tools) != 1 || got.Tools[0].Type != "function" || got.Tools[0].Function.Name != "fetch_repo_text" {
+		t.Fatalf("tools = %+v", got.Tools)
+	}
+	params, _ := json.Marshal(got.Tools[0].Function.Parameters)
+	if !strings.Contains(string(params), `"path"`) {
+		t.Errorf("parameters = %s", params)
+	}
+}
+
+func TestProxyTransport_RetriesServerErrors(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			http.Error(w, `{"error":{"message":"overloaded"}}`, http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"ok"}}]}`))
+	}))
+	defer srv.Close()
+
+	resp, err := NewProxyTransport(proxyConfig(t, srv.URL+"/v1")).Chat(context.Background(), ChatReq{Messages: []Msg{{Role: "user", Content: "hi"}}})
+	if err != nil {
+		t.Fatalf("Chat: %v", err)
+	}
+	if resp.Text != "ok" || atomic.LoadInt32(&calls) != 2 {
+		t.Fatalf("text=%q calls=%d", resp.Text, calls)
+	}
+}
+
+func TestProxyTransport_HonoursRetryAfter(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			http.Error(w, `{"error":{"message":"rate limited"}}`, http.StatusTooManyRequests)
+			return
+		}
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"ok"}}]}`))
+	}))
+	defer srv.Close()
+
+	start := time.Now()
+	resp, err := NewProxyTransport(proxyConfig(t, srv.URL+"/v1")).Chat(context.Background(), ChatReq{Messages: []Msg{{Role: "user", Content: "hi"}}})
+	if err != nil || resp.Text != "ok" {
+		t.Fatalf("resp=%+v err=%v", resp, err)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Fatalf("retried after %v, want the 1s Retry-After", elapsed)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2026, 1, 2, 15, 4, 5, 0, time.UTC)
+	cases := map[string]time.Duration{
+		"":                              0,
+		"3":                             3 * time.Second,
+		"-1":                            0,
+		"soon":                          0,
+		"3600":                          maxRetryAfter,
+		"Fri, 02 Jan 2026 15:04:15 GMT": 10 * time.Second,
+	}
+	for in, want := range cases {
+		if got := parseRetryAfter(in, now); got != want {
+			t.Errorf("parseRetryAfter(%q) = %v, want %v", in, got, want)
+		}
+	}
+}
+
+func TestProxyTransport_ClientErrorNotRetried(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"error":{"message":"invalid api key"}}`))
+	}))
+	defer srv.Close()
+
+	_, err := NewProxyTransport(proxyConfig(t, srv.URL)).Chat(context.Background(), ChatReq{Messages: []Msg{{Role: "user", Content: "hi"}}})
+	if err == nil || !strings.Contains(err.Error(), "invalid api key") {
+		t.Fatalf("err = %v", err)
+	}
+	if atomic.LoadInt32(&calls) != 1 {
+		t.Fatalf("calls = %d, want 1", calls)
+	}
+}
+
+func TestChatCompletionsURL(t *testing.T) {
+	cases := map[string]string{
+		"":                                      "https://api.openai.com/v1/chat/completions",
+		"https://llm.internal":                  "https://llm.internal/v1/chat/completions",
+		"https://llm.internal/v1/":              "https://llm.internal/v1/chat/completions",
+		"https://llm.internal/chat/completions": "https://llm.internal/chat/completions",
+	}
+	for in, want := range cases {
+		if got := chatCompletionsURL(in); got != want {
+			t.Errorf("chatCompletionsURL(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package brain
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kelvin/tgsflow/src/core/config"
+)
+
+// AzureAPIVersion is the Azure OpenAI REST API version sent with every request.
+const AzureAPIVersion = "2024-06-01"
+
+// NewSDKTransport returns the native API client for ai.provider (openai,
+// anthropic, azure or vertex — the choices offered by config.PromptInteractive).
+func NewSDKTransport(cfg config.Config) (Transport, error) {
+	switch strings.ToLower(strings.TrimSpace(cfg.AI.Provider)) {
+	case "", "openai":
+		t := NewProxyTransport(cfg).(*proxyTransport)
+		t.label = "openai transport"
+		return t, nil
+	case "anthropic":
+		return newAnthropicTransport(cfg), nil
+	case "azure":
+		return newAzureTransport(cfg)
+	case "vertex":
+		return newVertexTransport(cfg)
+	default:
+		return nil, fmt.Errorf("ai.provider %q unsupported for ai.mode sdk; expected openai|anthropic|azure|vertex", cfg.AI.Provider)
+	}
+}
+
+// newAzureTransport targets an Azure OpenAI deployment. ai.endpoint is the
+// resource URL (https://<resource>.openai.azure.com) and ai.model the
+// deployment name; a full deployment URL is used as-is.
+func newAzureTransport(cfg config.Config) (Transport, error) {
+	endpoint := strings.TrimRight(strings.TrimSpace(cfg.AI.Endpoint), "/")
+	if endpoint == "" {
+		return nil, fmt.Errorf("ai.provider azure requires ai.endpoint (https://<resource>.openai.azure.com)")
+	}
+	url := endpoint
+	if !strings.Contains(url, "/deployments/") {
+		if strings.TrimSpace(cfg.AI.Model) == "" {
+			return nil, fmt.Errorf("ai.provider azure requires ai.model (the deployment name)")
+		}
+		url += "/openai/deployments/" + cfg.AI.Model + "/chat/completions"
+	}
+	if !strings.Contains(url, "api-version=") {
+		url += "?api-version=" + AzureAPIVersion
+	}
+	t := &proxyTransport{label: "azure transport", url: url, model: cfg.AI.Model, headers: map[string]string{}, http: newHTTPDoer(cfg)}
+	if key := apiKey(cfg); key != "" {
+		t.headers["api-key"] = key
+	}
+	return t, nil
+}
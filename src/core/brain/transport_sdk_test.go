@@ -0,0 +1,206 @@
+package brain
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kelvin/tgsflow/src/core/config"
+)
+
+func sdkConfig(t *testing.T, provider, endpoint string) config.Config {
+	t.Helper()
+	t.Setenv("TGS_TEST_SDK_KEY", "secret")
+	cfg := config.Default()
+	cfg.AI.Mode = "sdk"
+	cfg.AI.Provider = provider
+	cfg.AI.Endpoint = endpoint
+	cfg.AI.APIKeyEnv = "TGS_TEST_SDK_KEY"
+	cfg.AI.Model = "test-model"
+	cfg.AI.Retry.BackoffMS = 1
+	return cfg
+}
+
+func TestSDKTransport_Anthropic(t *testing.T) {
+	var got anthropicRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/messages" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		if r.Header.Get("x-api-key") != "secret" || r.Header.Get("anthropic-version") != AnthropicVersion {
+			t.Errorf("headers = %v", r.Header)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		_, _ = w.Write([]byte(`{"stop_reason":"tool_use","content":[
+			{"type":"text","text":"Reading it."},
+			{"type":"tool_use","id":"toolu_2","name":"fetch_repo_text","input":{"path":"go.mod"}}]}`))
+	}))
+	defer srv.Close()
+
+	cfg := sdkConfig(t, "anthropic", srv.URL)
+	cfg.AI.Toolpack.Budgets = map[string]int{"max_tokens": 333}
+	tr, err := NewSDKTransport(cfg)
+	if err != nil {
+		t.Fatalf("NewSDKTransport: %v", err)
+	}
+	resp, err := tr.Chat(context.Background(), ChatReq{
+		System: "be brief",
+		Messages: []Msg{
+			{Role: "user", Content: "which module?"},
+			{Role: "assistant", ToolCalls: []ToolCall{{ID: "toolu_0", Name: "list_candidates"}, {ID: "toolu_1", Name: "fetch_repo_text", ArgsJSON: `{"path":"README.md"}`}}},
+			{Role: "tool", ToolCallID: "toolu_0", Content: "go.mod"},
+			{Role: "tool", ToolCallID: "toolu_1", Content: "# TGSFlow"},
+		},
+		Tools: []Tool{{Name: "fetch_repo_text", Description: "Read a file", JSONSchema: `{"type":"object","properties":{"path":{"type":"string"}}}`}},
+	})
+	if err != nil {
+		t.Fatalf("Chat: %v", err)
+	}
+	if resp.Text != "Reading it." || len(resp.ToolCalls) != 1 || resp.ToolCalls[0].ID != "toolu_2" || resp.ToolCalls[0].ArgsJSON != `{"path":"go.mod"}` {
+		t.Fatalf("resp = %+v", resp)
+	}
+
+	if got.System != "be brief" || got.MaxTokens != 333 || got.Model != "test-model" {
+		t.Errorf("system/max_tokens/model = %q/%d/%q", got.System, got.MaxTokens, got.Model)
+	}
+	if len(got.Messages) != 3 {
+		t.Fatalf("messages = %+v", got.Messages)
+	}
+	if use := got.Messages[1].Content; len(use) != 2 || use[0].Type != "tool_use" || string(use[0].Input) != "{}" || use[1].Name != "fetch_repo_text" {
+		t.Errorf("tool_use blocks = %+v", use)
+	}
+	if res := got.Messages[2]; res.Role != "user" || len(res.Content) != 2 || res.Content[1].Type != "tool_result" || res.Content[1].ToolUseID != "toolu_1" {
+		t.Errorf("tool_result turn = %+v", res)
+	}
+	if len(got.Tools) != 1 || !strings.Contains(string(got.Tools[0].InputSchema), `"path"`) {
+		t.Errorf("tools = %+v", got.Tools)
+	}
+}
+
+func TestSDKTransport_AnthropicRequestMaxTokensWins(t *testing.T) {
+	var got anthropicRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		_, _ = w.Write([]byte(`{"content":[{"type":"text","text":"ok"}]}`))
+	}))
+	defer srv.Close()
+
+	tr, _ := NewSDKTransport(sdkConfig(t, "anthropic", srv.URL+"/v1"))
+	if _, err := tr.Chat(context.Background(), ChatReq{Messages: []Msg{{Role: "user", Content: "hi"}}, MaxTokens: 50}); err != nil {
+		t.Fatalf("Chat: %v", err)
+	}
+	if got.MaxTokens != 50 {
+		t.Fatalf("max_tokens = %d, want 50", got.MaxTokens)
+	}
+}
+
+func TestSDKTransport_Azure(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/openai/deployments/test-model/chat/completions" || r.URL.Query().Get("api-version") != AzureAPIVersion {
+			t.Errorf("url = %s", r.URL)
+		}
+		if r.Header.Get("api-key") != "secret" || r.Header.Get("Authorization") != "" {
+			t.Errorf("headers = %v", r.Header)
+		}
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"from azure"}}]}`))
+	}))
+	defer srv.Close()
+
+	tr, err := NewSDKTransport(sdkConfig(t, "azure", srv.URL))
+	if err != nil {
+		t.Fatalf("NewSDKTransport: %v", err)
+	}
+	resp, err := tr.Chat(context.Background(), ChatReq{Messages: []Msg{{Role: "user", Content: "hi"}}})
+	if err != nil || resp.Text != "from azure" {
+		t.Fatalf("resp=%+v err=%v", resp, err)
+	}
+
+	if _, err := NewSDKTransport(sdkConfig(t, "azure", "")); err == nil {
+		t.Fatal("expected azure without ai.endpoint to fail")
+	}
+	cfg := sdkConfig(t, "azure", srv.URL)
+	cfg.AI.Model = ""
+	if _, err := NewSDKTransport(cfg); err == nil || !strings.Contains(err.Error(), "ai.model") {
+		t.Fatalf("expected azure without ai.model to fail, got %v", err)
+	}
+}
+
+func TestSDKTransport_Vertex(t *testing.T) {
+	var got geminiRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/models/test-model:generateContent" || r.Header.Get("x-goog-api-key") != "secret" {
+			t.Errorf("url=%s headers=%v", r.URL, r.Header)
+		}
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		_, _ = w.Write([]byte(`{"candidates":[{"content":{"role":"model","parts":[{"functionCall":{"name":"list_candidates","args":{"query":"auth"}}}]}}]}`))
+	}))
+	defer srv.Close()
+
+	tr, err := NewSDKTransport(sdkConfig(t, "vertex", srv.URL))
+	if err != nil {
+		t.Fatalf("NewSDKTransport: %v", err)
+	}
+	resp, err := tr.Chat(context.Background(), ChatReq{
+		System: "be brief",
+		Messages: []Msg{
+			{Role: "user", Content: "find auth"},
+			{Role: "assistant", ToolCalls: []ToolCall{{ID: "call_0", Name: "fetch_repo_text", ArgsJSON: `{"path":"a.go"}`}}},
+			{Role: "tool", ToolCallID: "call_0", Content: "package a"},
+		},
+		Tools:     []Tool{{Name: "list_candidates"}},
+		MaxTokens: 64,
+	})
+	if err != nil {
+		t.Fatalf("Chat: %v", err)
+	}
+	if len(resp.ToolCalls) != 1 || resp.ToolCalls[0].Name != "list_candidates" || resp.ToolCalls[0].ArgsJSON != `{"query":"auth"}` {
+		t.Fatalf("resp = %+v", resp)
+	}
+	if got.SystemInstruction == nil || got.SystemInstruction.Parts[0].Text != "be brief" || got.GenerationConfig == nil || got.GenerationConfig.MaxOutputTokens != 64 {
+		t.Errorf("system/generationConfig = %+v %+v", got.SystemInstruction, got.GenerationConfig)
+	}
+	if len(got.Contents) != 3 || got.Contents[1].Role != "model" || got.Contents[2].Parts[0].FunctionResponse == nil || got.Contents[2].Parts[0].FunctionResponse.Name != "fetch_repo_text" {
+		t.Errorf("contents = %+v", got.Contents)
+	}
+	if len(got.Tools) != 1 || string(got.Tools[0].FunctionDeclarations[0].Parameters) != `{"type":"object"}` {
+		t.Errorf("tools = %+v", got.Tools)
+	}
+
+	if _, err := NewSDKTransport(sdkConfig(t, "vertex", "")); err == nil || !strings.Contains(err.Error(), "aiplatform.googleapis.com") {
+		t.Fatalf("expected vertex without ai.endpoint to fail, got %v", err)
+	}
+}
+
+func TestSDKTransport_VertexAIURL(t *testing.T) {
+	var path string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		_, _ = w.Write([]byte(`{"candidates":[{"content":{"role":"model","parts":[{"text":"ok"}]}}]}`))
+	}))
+	defer srv.Close()
+
+	tr, err := newVertexTransport(sdkConfig(t, "vertex", srv.URL+"/v1/projects/p1/locations/europe-west4/publishers/google"))
+	if err != nil {
+		t.Fatalf("newVertexTransport: %v", err)
+	}
+	if _, err := tr.Chat(context.Background(), ChatReq{Messages: []Msg{{Role: "user", Content: "hi"}}}); err != nil {
+		t.Fatalf("Chat: %v", err)
+	}
+	if path != "/v1/projects/p1/locations/europe-west4/publishers/google/models/test-model:generateContent" {
+		t.Errorf("path = %s", path)
+	}
+}
+
+func TestSDKTransport_UnknownProvider(t *testing.T) {
+	cfg := config.Default()
+	cfg.AI.Mode = "sdk"
+	cfg.AI.Provider = "llama"
+	if tr, err := NewTransport(cfg); err == nil || tr != nil {
+		t.Fatalf("expected error for unknown provider, got %v", err)
+	}
+}
@@ -24,6 +24,7 @@ func writeTempAdapter(t *testing.T, content string) string {
 	return path
 }
 
+// Verifies: SR-020
 func TestShellTransport_HappyPath_Text(t *testing.T) {
 	if runtime.GOOS == "windows" {
 		t.Skip("shell adapter tests require sh")
@@ -49,6 +50,7 @@ echo "Hello from adapter"
 	}
 }
 
+// Verifies: SR-020
 func TestShellTransport_ErrorPath(t *testing.T) {
 	if runtime.GOOS == "windows" {
 		t.Skip("shell adapter tests require sh")
@@ -69,6 +71,7 @@ exit 7
 	}
 }
 
+// Verifies: SR-020
 func TestShellTransport_Timeout(t *testing.T) {
 	if runtime.GOOS == "windows" {
 		t.Skip("shell adapter tests require sh")
@@ -0,0 +1,187 @@
+package brain
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/kelvin/tgsflow/src/core/config"
+)
+
+// vertexTransport speaks the Gemini generateContent API shared by Vertex AI and
+// Google AI Studio.
+type vertexTransport struct {
+	url       string
+	headers   map[string]string
+	maxTokens int
+	http      httpDoer
+}
+
+// newVertexTransport targets ai.endpoint: a Vertex AI publisher URL
+// (https://<location>-aiplatform.googleapis.com/v1/projects/<project>/locations/<location>/publishers/google)
+// or the Gemini API (https://generativelanguage.googleapis.com/v1beta). The
+// model is appended unless the endpoint already ends in ":generateContent".
+func newVertexTransport(cfg config.Config) (*vertexTransport, error) {
+	base := strings.TrimRight(strings.TrimSpace(cfg.AI.Endpoint), "/")
+	if base == "" {
+		return nil, errors.New("ai.provider vertex requires ai.endpoint (https://<location>-aiplatform.googleapis.com/v1/projects/<project>/locations/<location>/publishers/google)")
+	}
+	url := base
+	if !strings.HasSuffix(url, ":generateContent") {
+		if strings.TrimSpace(cfg.AI.Model) == "" {
+			return nil, errors.New("ai.provider vertex requires ai.model")
+		}
+		url += "/models/" + cfg.AI.Model + ":generateContent"
+	}
+	t := &vertexTransport{url: url, headers: map[string]string{}, maxTokens: Budget(cfg, "max_tokens", 0), http: newHTTPDoer(cfg)}
+	if key := apiKey(cfg); key != "" {
+		// Vertex AI takes an OAuth access token; the Gemini API an API key.
+		if strings.Contains(base, "aiplatform.googleapis.com") {
+			t.headers["Authorization"] = "Bearer " + key
+		} else {
+			t.headers["x-goog-api-key"] = key
+		}
+	}
+	return t, nil
+}
+
+type geminiPart struct {
+	Text             string                  `json:"text,omitempty"`
+	FunctionCall     *geminiFunctionCall     `json:"functionCall,omitempty"`
+	FunctionResponse *geminiFunctionResponse `json:"functionResponse,omitempty"`
+}
+
+type geminiFunctionCall struct {
+	Name string          `json:"name"`
+	Args json.RawMessage `json:"args,omitempty"`
+}
+
+type geminiFunctionResponse struct {
+	Name     string `json:"name"`
+	Response struct {
+		Content string `json:"content"`
+	} `json:"response"`
+}
+
+type geminiContent struct {
+	Role  string       `json:"role,omitempty"`
+	Parts []geminiPart `json:"parts"`
+}
+
+type geminiFunctionDecl struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Parameters  json.RawMessage `json:"parameters"`
+}
+
+type geminiRequest struct {
+	SystemInstruction *geminiContent  `json:"systemInstruction,omitempty"`
+	Contents          []geminiContent `json:"contents"`
+	Tools             []struct {
+		FunctionDeclarations []geminiFunctionDecl `json:"functionDeclarations"`
+	} `json:"tools,omitempty"`
+	GenerationConfig *struct {
+		MaxOutputTokens int `json:"maxOutputTokens"`
+	} `json:"generationConfig,omitempty"`
+}
+
+type geminiResponse struct {
+	Candidates []struct {
+		Content geminiContent `json:"content"`
+	} `json:"candidates"`
+	UsageMetadata struct {
+		PromptTokenCount     int `json:"promptTokenCount"`
+		CandidatesTokenCount int `json:"candidatesTokenCount"`
+	} `json:"usageMetadata"`
+}
+
+func (t *vertexTransport) Chat(ctx context.Context, req ChatReq) (ChatResp, error) {
+	if emptyRequest(req) {
+		return ChatResp{}, errors.New("empty prompt")
+	}
+	var body geminiRequest
+	system := req.System
+	// Gemini function responses are keyed by name, not call ID.
+	callNames := map[string]string{}
+	for _, m := range req.Messages {
+		var c geminiContent
+		switch m.Role {
+		case "system":
+			system = strings.TrimSpace(system + "\n\n" + m.Content)
+			continue
+		case "assistant":
+			c.Role = "model"
+			if m.Content != "" {
+				c.Parts = append(c.Parts, geminiPart{Text: m.Content})
+			}
+			for _, call := range m.ToolCalls {
+				callNames[call.ID] = call.Name
+				c.Parts = append(c.Parts, geminiPart{FunctionCall: &geminiFunctionCall{Name: call.Name, Args: toolArgs(call)}})
+			}
+		case "tool":
+			c.Role = "user"
+			fr := &geminiFunctionResponse{Name: callNames[m.ToolCallID]}
+			fr.Response.Content = m.Content
+			c.Parts = append(c.Parts, geminiPart{FunctionResponse: fr})
+		default:
+			c.Role = "user"
+			if m.Content != "" {
+				c.Parts = append(c.Parts, geminiPart{Text: m.Content})
+			}
+		}
+		if len(c.Parts) == 0 {
+			continue
+		}
+		if n := len(body.Contents); n > 0 && body.Contents[n-1].Role == c.Role {
+			body.Contents[n-1].Parts = append(body.Contents[n-1].Parts, c.Parts...)
+			continue
+		}
+		body.Contents = append(body.Contents, c)
+	}
+	if strings.TrimSpace(system) != "" {
+		body.SystemInstruction = &geminiContent{Parts: []geminiPart{{Text: system}}}
+	}
+	if len(req.Tools) > 0 {
+		body.Tools = make([]struct {
+			FunctionDeclarations []geminiFunctionDecl `json:"functionDeclarations"`
+		}, 1)
+		for _, tool := range req.Tools {
+			body.Tools[0].FunctionDeclarations = append(body.Tools[0].FunctionDeclarations, geminiFunctionDecl{Name: tool.Name, Description: tool.Description, Parameters: toolSchema(tool)})
+		}
+	}
+	maxTokens := req.MaxTokens
+	if maxTokens <= 0 {
+		maxTokens = t.maxTokens
+	}
+	if maxTokens > 0 {
+		body.GenerationConfig = &struct {
+			MaxOutputTokens int `json:"maxOutputTokens"`
+		}{maxTokens}
+	}
+
+	var out geminiResponse
+	if err := t.http.postJSON(ctx, t.url, t.headers, body, &out); err != nil {
+		return ChatResp{}, fmt.Errorf("vertex transport: %w", err)
+	}
+	if len(out.Candidates) == 0 {
+		return ChatResp{}, errors.New("vertex transport: response has no candidates")
+	}
+	resp := ChatResp{Usage: Usage{InputTokens: out.UsageMetadata.PromptTokenCount, OutputTokens: out.UsageMetadata.CandidatesTokenCount}}
+	var text []string
+	for i, p := range out.Candidates[0].Content.Parts {
+		if p.FunctionCall != nil {
+			args := string(p.FunctionCall.Args)
+			if args == "" {
+				args = "{}"
+			}
+			// Gemini does not assign call IDs; synthesize stable ones.
+			resp.ToolCalls = append(resp.ToolCalls, ToolCall{ID: fmt.Sprintf("call_%d", i), Name: p.FunctionCall.Name, ArgsJSON: args})
+			continue
+		}
+		text = append(text, p.Text)
+	}
+	resp.Text = strings.Join(text, "")
+	return resp, nil
+}
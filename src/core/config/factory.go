@@ -51,9 +51,12 @@ type TemplateData struct {
 }
 
 type EARSFields struct {
-	Enable       bool
-	RequireShall bool
-	Paths        []string
+	Enable            bool
+	RequireShall      bool
+	AllowNegative     bool
+	RequireMeasurable bool
+	Language          string
+	Paths             []string
 }
 
 // DefaultTemplateData returns sane beginner-friendly defaults.
@@ -87,9 +90,12 @@ func DefaultTemplateData(projectName string) TemplateData {
 		PRTemplate:       ".github/PULL_REQUEST_TEMPLATE.md",
 		CommitConvention: "conventional",
 		EARS: EARSFields{
-			Enable:       false,
-			RequireShall: false,
-			Paths:        []string{"tgs/design/10_needs.md", "tgs/design/20_requirements.md"},
+			Enable:            false,
+			RequireShall:      false,
+			AllowNegative:     true,
+			RequireMeasurable: false,
+			Language:          "en",
+			Paths:             []string{"tgs/design/10_needs.md", "tgs/design/20_requirements.md"},
 		},
 
 		AgentName:         "aider-main",
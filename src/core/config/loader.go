@@ -50,6 +50,11 @@ func Default() Config {
 					{Name: "fetch_repo_text", Desc: "Read small text slices by path+line range for ranking/quotes."},
 					{Name: "list_candidates", Desc: "Return candidate doc sections with path, anchor, and score."},
 					{Name: "propose_brief", Desc: "Return ordered brief sections within a token budget."},
+					{Name: "context_pack", Desc: "Build a brief of the doc sections most relevant to a query, with source pointers."},
+					{Name: "verify_ears", Desc: "Lint EARS requirements in the design docs and report path:line findings."},
+					{Name: "parse_requirement", Desc: "Parse one requirement into its EARS shape and parts."},
+					{Name: "list_thoughts", Desc: "List thought directories and mark the active one."},
+					{Name: "active_thought", Desc: "Return the active thought directory and its files."},
 				},
 				Redaction: AIRedaction{
 					RedactEnvKeys:  []string{"API_KEY", "TOKEN", "PASSWORD"},
@@ -69,9 +74,12 @@ func Default() Config {
 			PRTemplate:       ".github/PULL_REQUEST_TEMPLATE.md",
 			CommitConvention: "conventional",
 			EARS: EARSConfig{
-				Enable:       false,
-				RequireShall: false,
-				Paths:        []string{"tgs/design/10_needs.md", "tgs/design/20_requirements.md"},
+				Enable:            false,
+				RequireShall:      false,
+				AllowNegative:     true,
+				RequireMeasurable: false,
+				Language:          "en",
+				Paths:             []string{"tgs/design/10_needs.md", "tgs/design/20_requirements.md"},
 			},
 		},
 		Agents: []Agent{},
@@ -129,6 +137,15 @@ type AI struct {
 	ShellClaudeCmd   string     `yaml:"shell_claude_cmd"`
 	Retry            AIRetry    `yaml:"retry"`
 	Toolpack         AIToolpack `yaml:"toolpack"`
+	MCP              AIMCP      `yaml:"mcp"`
+}
+
+// AIMCP selects the MCP server used by ai.mode "mcp": a stdio command to spawn
+// or a streamable HTTP URL (defaults to ai.endpoint).
+type AIMCP struct {
+	Command []string          `yaml:"command"`
+	Env     map[string]string `yaml:"env"`
+	URL     string            `yaml:"url"`
 }
 
 type AIRetry struct {
@@ -145,6 +162,14 @@ type AIToolpack struct {
 	Redaction AIRedaction       `yaml:"redaction"`
 }
 
+// Budget returns budgets[key] when set to a positive value, else def.
+func (t AIToolpack) Budget(key string, def int) int {
+	if v, ok := t.Budgets[key]; ok && v > 0 {
+		return v
+	}
+	return def
+}
+
 type AITool struct {
 	Name string `yaml:"name"`
 	Desc string `yaml:"desc"`
@@ -219,7 +244,16 @@ type Context struct {
 }
 
 type EARSConfig struct {
-	Enable       bool     `yaml:"enable"`
-	RequireShall bool     `yaml:"require_shall"`
-	Paths        []string `yaml:"paths"`
+	Enable       bool `yaml:"enable"`
+	RequireShall bool `yaml:"require_shall"`
+	// AllowNegative permits "shall not" requirements; when false they are reported as issues
+	AllowNegative bool `yaml:"allow_negative"`
+	// RequireMeasurable reports performance wording ("quickly", "real-time") without a numeric bound
+	RequireMeasurable bool `yaml:"require_measurable"`
+	// Language selects the EARS keyword profile (en, de, fr); a Markdown file may override
+	// it with an <!-- ears-language: xx --> marker
+	Language string `yaml:"language"`
+	// Glossary is a glossary.yml or Markdown file with a "Glossary" table; empty auto-detects
+	Glossary string   `yaml:"glossary"`
+	Paths    []string `yaml:"paths"`
 }
@@ -105,3 +105,16 @@ func TestLoad_Table(t *testing.T) {
 		})
 	}
 }
+
+func TestAIToolpack_Budget(t *testing.T) {
+	tp := AIToolpack{Budgets: map[string]int{"agent_steps": 4, "max_tokens": 0}}
+	if got := tp.Budget("agent_steps", 8); got != 4 {
+		t.Errorf("agent_steps = %d, want 4", got)
+	}
+	if got := tp.Budget("max_tokens", 1024); got != 1024 {
+		t.Errorf("non-positive budget should fall back, got %d", got)
+	}
+	if got := tp.Budget("missing", 7); got != 7 {
+		t.Errorf("missing = %d, want 7", got)
+	}
+}
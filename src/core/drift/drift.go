@@ -0,0 +1,286 @@
+// Package drift finds references in design and thought documents to paths,
+// CLI commands, config keys and Go symbols, and reports the ones that no longer
+// exist in the repository.
+package drift
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Kind classifies a reference.
+type Kind string
+
+const (
+	KindPath    Kind = "path"
+	KindCommand Kind = "command"
+	KindConfig  Kind = "config"
+	KindSymbol  Kind = "symbol"
+)
+
+// Ref is a reference found in a document.
+type Ref struct {
+	Kind  Kind   `json:"kind"`
+	Value string `json:"value"`
+	Path  string `json:"path"` // repo-relative document path
+	Line  int    `json:"line"`
+}
+
+// Finding is a reference that does not resolve.
+type Finding struct {
+	Ref
+	Message string `json:"message"`
+}
+
+func (f Finding) String() string {
+	return fmt.Sprintf("%s:%d: %s", f.Path, f.Line, f.Message)
+}
+
+var (
+	codeSpanRe  = regexp.MustCompile("`([^`]+)`")
+	configKeyRe = regexp.MustCompile(`^[a-z][a-z0-9_]*(\.[a-z0-9_]+)+$`)
+	symbolRe    = regexp.MustCompile(`^([A-Za-z][A-Za-z0-9_]*)\.([A-Z][A-Za-z0-9_]*)(?:\.([A-Z][A-Za-z0-9_]*))?(?:\(\))?$`)
+	lineSuffix  = regexp.MustCompile(`(:[0-9]+(-[0-9]+)?)+$`)
+	commandHead = regexp.MustCompile(`^(?:\$\s+)?(?:\./bin/|\./)?tgs(\s|$)`)
+	fileExts    = map[string]bool{"go": true, "md": true, "yml": true, "yaml": true, "json": true, "jsonl": true, "sh": true, "txt": true, "g4": true, "mmd": true, "html": true, "tmpl": true, "mod": true, "sum": true}
+)
+
+// Extract returns the references in a Markdown document: code spans and the
+// lines of fenced code blocks. Fenced lines only contribute commands.
+func Extract(path, content string) []Ref {
+	var out []Ref
+	inFence := false
+	for i, ln := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(ln)
+		if strings.HasPrefix(trimmed, "```") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			if commandHead.MatchString(trimmed) {
+				out = append(out, Ref{Kind: KindCommand, Value: normalizeCommand(trimmed), Path: path, Line: i + 1})
+			}
+			continue
+		}
+		for _, m := range codeSpanRe.FindAllStringSubmatch(ln, -1) {
+			if r, ok := classify(strings.TrimSpace(m[1])); ok {
+				r.Path, r.Line = path, i+1
+				out = append(out, r)
+			}
+		}
+	}
+	return out
+}
+
+func classify(span string) (Ref, bool) {
+	if commandHead.MatchString(span) {
+		return Ref{Kind: KindCommand, Value: normalizeCommand(span)}, true
+	}
+	key := span // "ai.mode: proxy" or "ai.mode=proxy"
+	if i := strings.IndexAny(key, ":="); i > 0 {
+		key = key[:i]
+	}
+	if configKeyRe.MatchString(key) && !fileExts[key[strings.LastIndex(key, ".")+1:]] {
+		return Ref{Kind: KindConfig, Value: key}, true
+	}
+	if strings.ContainsAny(span, " \t<>*{}$|") || strings.Contains(span, "...") || strings.Contains(span, "://") {
+		return Ref{}, false
+	}
+	if symbolRe.MatchString(span) {
+		return Ref{Kind: KindSymbol, Value: strings.TrimSuffix(span, "()")}, true
+	}
+	if strings.Contains(span, "/") && !strings.HasPrefix(span, "/") && !strings.HasPrefix(span, "~") {
+		return Ref{Kind: KindPath, Value: strings.TrimRight(lineSuffix.ReplaceAllString(span, ""), ".,;:")}, true
+	}
+	return Ref{}, false
+}
+
+// normalizeCommand drops the prompt and binary prefix ("$ ./bin/tgs") and any
+// trailing comment, leaving "tgs <args>".
+func normalizeCommand(s string) string {
+	s = strings.TrimSpace(strings.TrimPrefix(s, "$"))
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "./bin/"), "./")
+	if i := strings.Index(s, " #"); i >= 0 {
+		s = s[:i]
+	}
+	return strings.TrimSpace(s)
+}
+
+// Checker resolves references against the repository.
+type Checker struct {
+	RepoRoot string
+	// Commands maps command paths ("tgs", "tgs verify ears") to the flags they
+	// accept, without dashes.
+	Commands map[string][]string
+	// Config is the set of known config keys; see ConfigKeys.
+	Config Keys
+	// Symbols indexes the Go declarations of the repository; see GoSymbols.
+	Symbols *Symbols
+}
+
+// Check returns the references that do not resolve.
+func (c *Checker) Check(refs []Ref) []Finding {
+	var out []Finding
+	for _, r := range refs {
+		var msg string
+		switch r.Kind {
+		case KindPath:
+			msg = c.checkPath(r)
+		case KindCommand:
+			if c.Commands != nil {
+				msg = c.checkCommand(r.Value)
+			}
+		case KindConfig:
+			if c.Config != nil {
+				msg = c.checkConfig(r.Value)
+			}
+		case KindSymbol:
+			if c.Symbols != nil {
+				msg = c.checkSymbol(r.Value)
+			}
+		}
+		if msg != "" {
+			out = append(out, Finding{Ref: r, Message: msg})
+		}
+	}
+	return out
+}
+
+// checkPath reports a missing path. Paths starting with ../ resolve against the
+// document and ./ against the document or the repository root; others against
+// the repository root, and only when their first segment exists there (so
+// "owner/repo" or "application/json" are not mistaken for paths).
+func (c *Checker) checkPath(r Ref) string {
+	p := r.Value
+	candidates := []string{p}
+	switch {
+	case strings.HasPrefix(p, "../"):
+		candidates = []string{filepath.Join(filepath.Dir(r.Path), p)}
+	case strings.HasPrefix(p, "./"):
+		candidates = append(candidates, filepath.Join(filepath.Dir(r.Path), p))
+	default:
+		first := strings.SplitN(p, "/", 2)[0]
+		if _, err := os.Stat(filepath.Join(c.RepoRoot, first)); err != nil {
+			return ""
+		}
+	}
+	for _, cand := range candidates {
+		if _, err := os.Stat(filepath.Join(c.RepoRoot, cand)); err == nil {
+			return ""
+		}
+	}
+	return fmt.Sprintf("path %s does not exist", r.Value)
+}
+
+// checkCommand walks "tgs a b --flag" through the command tree: words select
+// subcommands while the current command has them, and every flag must be
+// accepted by the command it follows.
+func (c *Checker) checkCommand(line string) string {
+	path := "tgs"
+	for _, tok := range strings.Fields(line)[1:] {
+		if tok == "|" || tok == "&&" || tok == ";" || tok == "||" || strings.HasPrefix(tok, ">") || strings.HasPrefix(tok, "#") {
+			break
+		}
+		if flag := strings.Trim(tok, "[]()"); strings.HasPrefix(flag, "-") {
+			tok = flag
+		}
+		if strings.HasPrefix(tok, "-") && len(tok) > 1 {
+			// "--prompt-text|--prompt-file" lists alternatives
+			for _, alt := range strings.Split(tok, "|") {
+				name := strings.TrimLeft(alt, "-")
+				if i := strings.IndexAny(name, "= "); i >= 0 {
+					name = name[:i]
+				}
+				if !strings.HasPrefix(alt, "-") || name == "" || name == "h" || name == "help" {
+					continue
+				}
+				if !contains(c.Commands[path], name) {
+					return fmt.Sprintf("command `%s` has no flag %s", path, alt)
+				}
+			}
+			continue
+		}
+		if sub := path + " " + tok; c.Commands[sub] != nil {
+			path = sub
+			continue
+		}
+		if c.hasSubcommands(path) && isWord(tok) {
+			return fmt.Sprintf("command `%s %s` does not exist", path, tok)
+		}
+	}
+	return ""
+}
+
+func (c *Checker) hasSubcommands(path string) bool {
+	for p := range c.Commands {
+		if strings.HasPrefix(p, path+" ") {
+			return true
+		}
+	}
+	return false
+}
+
+func isWord(tok string) bool {
+	for _, r := range tok {
+		if !(r >= 'a' && r <= 'z' || r == '-') {
+			return false
+		}
+	}
+	return tok != ""
+}
+
+func (c *Checker) checkConfig(key string) string {
+	if !c.Config.Known(strings.SplitN(key, ".", 2)[0]) {
+		return "" // not a config reference, e.g. "tgs.yml"
+	}
+	if !c.Config.Known(key) {
+		return fmt.Sprintf("config key %s is not a field of config.Config", key)
+	}
+	return ""
+}
+
+func (c *Checker) checkSymbol(sym string) string {
+	if ok, known := c.Symbols.Resolve(sym); known && !ok {
+		return fmt.Sprintf("Go symbol %s does not exist", sym)
+	}
+	return ""
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Documents returns the Markdown files under the repo-relative dirs, sorted.
+func Documents(repoRoot string, dirs []string) ([]string, error) {
+	var out []string
+	for _, d := range dirs {
+		root := filepath.Join(repoRoot, d)
+		if _, err := os.Stat(root); os.IsNotExist(err) {
+			continue
+		}
+		err := filepath.WalkDir(root, func(p string, e os.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !e.IsDir() && strings.HasSuffix(p, ".md") {
+				rel, _ := filepath.Rel(repoRoot, p)
+				out = append(out, filepath.ToSlash(rel))
+			}
+			return nil
+		})
+		if err != nil {
+			return out, err
+		}
+	}
+	sort.Strings(out)
+	return out, nil
+}
@@ -0,0 +1,133 @@
+package drift
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type sampleConfig struct {
+	AI struct {
+		Mode     string         `yaml:"mode"`
+		Adapter  string         `yaml:"shell_adapter_path"`
+		Budgets  map[string]int `yaml:"budgets"`
+		Unnamed  string
+		internal string
+	} `yaml:"ai"`
+	Agents []struct {
+		Name string `yaml:"name"`
+	} `yaml:"agents"`
+}
+
+func TestExtract(t *testing.T) {
+	doc := "Run `tgs verify ears --ci` and set `ai.mode: proxy`.\n" +
+		"See `src/cmd/verify.go:42`, `config.Config` and `NewRootCommand()`.\n" +
+		"Ignore `tgs.yml`, `context.go`, `<placeholder>/x` and `a b`.\n" +
+		"```bash\n$ ./bin/tgs trace --format json # graph\necho `src/x.go`\n```\n"
+	var got []string
+	for _, r := range Extract("doc.md", doc) {
+		got = append(got, string(r.Kind)+"="+r.Value)
+	}
+	want := "command=tgs verify ears --ci config=ai.mode path=src/cmd/verify.go symbol=config.Config command=tgs trace --format json"
+	if strings.Join(got, " ") != want {
+		t.Fatalf("unexpected refs:\n got %v\nwant %s", got, want)
+	}
+}
+
+func TestConfigKeys(t *testing.T) {
+	keys := ConfigKeys(sampleConfig{})
+	for _, k := range []string{"ai", "ai.mode", "ai.shell_adapter_path", "ai.unnamed", "ai.budgets.plan", "agents.name"} {
+		if !keys.Known(k) {
+			t.Errorf("expected %s to be known", k)
+		}
+	}
+	for _, k := range []string{"ai.model", "ai.internal", "agents.role"} {
+		if keys.Known(k) {
+			t.Errorf("expected %s to be unknown", k)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	root := t.TempDir()
+	write := func(rel, content string) {
+		p := filepath.Join(root, rel)
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("src/config/config.go", "package config\n\ntype Config struct{ AI string }\n\nfunc (c Config) Validate() error { return nil }\n\nfunc Load() {}\n")
+	write("docs/guide.md", "")
+	symbols, err := GoSymbols(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &Checker{
+		RepoRoot: root,
+		Commands: map[string][]string{"tgs": {"json"}, "tgs verify": {"ci"}, "tgs verify ears": {"ci", "repo"}},
+		Config:   ConfigKeys(sampleConfig{}),
+		Symbols:  symbols,
+	}
+	doc := "- `tgs --json verify ears --repo=. --ci`\n" +
+		"- `tgs verify drift`\n" +
+		"- `tgs verify ears --fix`\n" +
+		"- `tgs version`\n" +
+		"- `ai.mode` `ai.model` `steps.plan_prompt`\n" +
+		"- `config.Config` `config.Load` `config.Config.Validate` `Config.AI` `os.Exit`\n" +
+		"- `config.Save` `config.Config.Check` `Config.Mode`\n" +
+		"- `src/config/config.go` `docs/guide.md` `../docs/guide.md` `src/gone.go` `owner/repo`\n"
+	var got []string
+	for _, f := range c.Check(Extract("docs/guide.md", doc)) {
+		got = append(got, f.String())
+	}
+	want := []string{
+		"docs/guide.md:2: command `tgs verify drift` does not exist",
+		"docs/guide.md:3: command `tgs verify ears` has no flag --fix",
+		"docs/guide.md:4: command `tgs version` does not exist",
+		"docs/guide.md:5: config key ai.model is not a field of config.Config",
+		"docs/guide.md:7: Go symbol config.Save does not exist",
+		"docs/guide.md:7: Go symbol config.Config.Check does not exist",
+		"docs/guide.md:7: Go symbol Config.Mode does not exist",
+		"docs/guide.md:8: path src/gone.go does not exist",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("unexpected findings:\n%s", strings.Join(got, "\n"))
+	}
+}
+
+func TestSymbols_SameTypeNameInTwoPackages(t *testing.T) {
+	root := t.TempDir()
+	for rel, content := range map[string]string{
+		"src/lsp/server.go": "package lsp\n\ntype Server struct{ Logf func() }\n\nfunc (s *Server) Serve() {}\n",
+		"src/mcp/server.go": "package mcp\n\ntype Server struct{ Info string }\n\nfunc (s *Server) AddTool() {}\n",
+	} {
+		p := filepath.Join(root, rel)
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	s, err := GoSymbols(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for sym, want := range map[string]bool{
+		"lsp.Server.Serve":   true,
+		"lsp.Server.Logf":    true,
+		"lsp.Server.AddTool": false,
+		"mcp.Server.Info":    true,
+		"mcp.Server.Serve":   false,
+		"Server.AddTool":     true,
+		"Server.Missing":     false,
+	} {
+		if ok, known := s.Resolve(sym); !known || ok != want {
+			t.Errorf("Resolve(%s) = %v, %v; want %v", sym, ok, known, want)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package drift
+
+import (
+	"reflect"
+	"strings"
+)
+
+// Keys is the set of dotted config keys, e.g. "ai.shell_adapter_path". A
+// "prefix.*" entry accepts any key below a map-typed field.
+type Keys map[string]bool
+
+// ConfigKeys derives the known keys from the yaml tags of v (e.g. config.Config{}).
+// Slices of structs are traversed so "agents.name" is known.
+func ConfigKeys(v any) Keys {
+	keys := Keys{}
+	collectKeys(reflect.TypeOf(v), "", keys)
+	return keys
+}
+
+func collectKeys(t reflect.Type, prefix string, keys Keys) {
+	for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Map:
+		if prefix != "" {
+			keys[prefix+".*"] = true
+		}
+		return
+	case reflect.Struct:
+	default:
+		return
+	}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name := strings.Split(f.Tag.Get("yaml"), ",")[0]
+		if name == "-" || !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(f.Name)
+		}
+		key := name
+		if prefix != "" {
+			key = prefix + "." + name
+		}
+		keys[key] = true
+		collectKeys(f.Type, key, keys)
+	}
+}
+
+// Known reports whether key, or a map-typed ancestor of it, is a config key.
+func (k Keys) Known(key string) bool {
+	if k[key] {
+		return true
+	}
+	for i := strings.LastIndex(key, "."); i > 0; i = strings.LastIndex(key[:i], ".") {
+		if k[key[:i]+".*"] {
+			return true
+		}
+	}
+	return false
+}
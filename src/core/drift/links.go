@@ -0,0 +1,195 @@
+package drift
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Link is a relative Markdown link, or a "(Source: path#anchor)" pointer.
+type Link struct {
+	Path   string `json:"path"` // repo-relative document path
+	Line   int    `json:"line"`
+	Target string `json:"target"` // as written, e.g. ../design/20_requirements.md#interfaces
+	Source bool   `json:"source,omitempty"`
+}
+
+var (
+	inlineLinkRe = regexp.MustCompile(`!?\[[^\]]*\]\(\s*<?([^)\s>]+)>?(?:\s+"[^"]*")?\s*\)`)
+	refDefRe     = regexp.MustCompile(`^\s{0,3}\[[^\]]+\]:\s+<?(\S+?)>?(?:\s+.*)?$`)
+	sourceRe     = regexp.MustCompile(`\((?i:source):\s*([^)\s]+)\)`)
+	headingRe    = regexp.MustCompile(`^\s{0,3}(#{1,6})\s+(.*?)\s*#*\s*$`)
+	htmlAnchorRe = regexp.MustCompile(`<a\s+(?:name|id)="([^"]+)"|\sid="([^"]+)"`)
+	schemeRe     = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9+.-]*:`)
+	lineRangeRe  = regexp.MustCompile(`^L?([0-9]+)(?:-L?([0-9]+))?$`)
+	sourceLineRe = regexp.MustCompile(`:[0-9]+(-[0-9]+)?$`)
+)
+
+// ExtractLinks returns the relative links of a Markdown document outside code
+// fences and code spans. With sources, "(Source: path#anchor)" pointers written
+// into briefs are returned as well; their paths are repo-relative.
+func ExtractLinks(path, content string, sources bool) []Link {
+	var out []Link
+	inFence := false
+	for i, ln := range strings.Split(content, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(ln), "```") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+		text := codeSpanRe.ReplaceAllString(ln, "")
+		add := func(target string, source bool) {
+			if target == "" || schemeRe.MatchString(target) || strings.HasPrefix(target, "//") {
+				return
+			}
+			out = append(out, Link{Path: path, Line: i + 1, Target: target, Source: source})
+		}
+		for _, m := range inlineLinkRe.FindAllStringSubmatch(text, -1) {
+			add(m[1], false)
+		}
+		if m := refDefRe.FindStringSubmatch(text); m != nil {
+			add(m[1], false)
+		}
+		if sources {
+			for _, m := range sourceRe.FindAllStringSubmatch(ln, -1) {
+				target := strings.TrimRight(m[1], ".,;")
+				// path:12 and path:12-20 are line pointers, like path#L12-L20
+				if loc := sourceLineRe.FindStringIndex(target); loc != nil && !strings.Contains(target, "#") {
+					target = target[:loc[0]] + "#L" + strings.ReplaceAll(target[loc[0]+1:], "-", "-L")
+				}
+				// skip template placeholders such as "path#anchor"
+				if p, _, _ := strings.Cut(target, "#"); strings.ContainsAny(p, "/.") {
+					add(target, true)
+				}
+			}
+		}
+	}
+	return out
+}
+
+// Anchors returns the fragment IDs a Markdown document defines: GitHub-style
+// heading slugs (with -1, -2 suffixes for duplicates) and explicit HTML ids.
+func Anchors(content string) map[string]bool {
+	anchors := map[string]bool{}
+	counts := map[string]int{}
+	inFence := false
+	for _, ln := range strings.Split(content, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(ln), "```") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+		for _, m := range htmlAnchorRe.FindAllStringSubmatch(ln, -1) {
+			anchors[m[1]+m[2]] = true
+		}
+		m := headingRe.FindStringSubmatch(ln)
+		if m == nil {
+			continue
+		}
+		slug := Slug(m[2])
+		if n := counts[slug]; n > 0 {
+			anchors[slug+"-"+strconv.Itoa(n)] = true
+		} else {
+			anchors[slug] = true
+		}
+		counts[slug]++
+	}
+	return anchors
+}
+
+var (
+	slugLinkRe = regexp.MustCompile(`\[([^\]]*)\]\([^)]*\)`)
+	slugMarkup = regexp.MustCompile("[`*~]|<[^>]+>")
+)
+
+// Slug converts heading text to its GitHub anchor: lowercase, markup and
+// punctuation removed, spaces turned into hyphens.
+func Slug(heading string) string {
+	s := slugLinkRe.ReplaceAllString(heading, "$1")
+	s = slugMarkup.ReplaceAllString(s, "")
+	var b strings.Builder
+	for _, r := range strings.ToLower(strings.TrimSpace(s)) {
+		switch {
+		case r == ' ':
+			b.WriteRune('-')
+		case r == '-' || r == '_' || r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r > 127 && isLetter(r):
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func isLetter(r rune) bool {
+	return strings.ToLower(string(r)) != strings.ToUpper(string(r))
+}
+
+// CheckLinks resolves each link against the repository: the target file (or
+// directory) must exist and a fragment must match an anchor of a Markdown
+// target. Source pointers may also name a line range, e.g. path#L10-L20.
+func CheckLinks(repoRoot string, links []Link) []Finding {
+	anchors := map[string]map[string]bool{}
+	lines := map[string]int{}
+	read := func(rel string) {
+		if _, ok := anchors[rel]; ok {
+			return
+		}
+		data, err := os.ReadFile(filepath.Join(repoRoot, rel))
+		if err != nil {
+			anchors[rel] = nil
+			return
+		}
+		anchors[rel] = Anchors(string(data))
+		lines[rel] = strings.Count(string(data), "\n") + 1
+	}
+	var out []Finding
+	for _, l := range links {
+		target, frag, _ := strings.Cut(l.Target, "#")
+		rel := l.Path
+		switch {
+		case target == "":
+		case l.Source || strings.HasPrefix(target, "/"):
+			rel = filepath.ToSlash(filepath.Clean(strings.TrimPrefix(target, "/")))
+		default:
+			rel = filepath.ToSlash(filepath.Join(filepath.Dir(l.Path), target))
+		}
+		ref := Ref{Kind: KindPath, Value: l.Target, Path: l.Path, Line: l.Line}
+		if strings.HasPrefix(rel, "../") || rel == ".." {
+			out = append(out, Finding{Ref: ref, Message: fmt.Sprintf("link %s points outside the repository", l.Target)})
+			continue
+		}
+		st, err := os.Stat(filepath.Join(repoRoot, rel))
+		if err != nil {
+			out = append(out, Finding{Ref: ref, Message: fmt.Sprintf("broken link %s: %s does not exist", l.Target, rel)})
+			continue
+		}
+		if frag == "" || st.IsDir() {
+			continue
+		}
+		if m := lineRangeRe.FindStringSubmatch(frag); m != nil && (l.Source || !strings.HasSuffix(rel, ".md")) {
+			read(rel)
+			end, _ := strconv.Atoi(m[1])
+			if m[2] != "" {
+				end, _ = strconv.Atoi(m[2])
+			}
+			if end > lines[rel] {
+				out = append(out, Finding{Ref: ref, Message: fmt.Sprintf("broken link %s: %s has %d lines", l.Target, rel, lines[rel])})
+			}
+			continue
+		}
+		if !strings.HasSuffix(rel, ".md") {
+			continue
+		}
+		read(rel)
+		if !anchors[rel][strings.ToLower(frag)] && !anchors[rel][frag] {
+			out = append(out, Finding{Ref: ref, Message: fmt.Sprintf("broken anchor %s: no heading #%s in %s", l.Target, frag, rel)})
+		}
+	}
+	return out
+}
@@ -0,0 +1,72 @@
+package drift
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSlug(t *testing.T) {
+	for in, want := range map[string]string{
+		"Components (C4 Level 3)":          "components-c4-level-3",
+		"Data & Models (if AI/ML is used)": "data--models-if-aiml-is-used",
+		"`tgs verify` **links**":           "tgs-verify-links",
+		"[Linked](x.md) snake_case":        "linked-snake_case",
+	} {
+		if got := Slug(in); got != want {
+			t.Errorf("Slug(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestAnchors_Duplicates(t *testing.T) {
+	a := Anchors("# Notes\n## Notes\n```\n# not a heading\n```\n<a name=\"custom\"></a>\n")
+	for _, want := range []string{"notes", "notes-1", "custom"} {
+		if !a[want] {
+			t.Errorf("missing anchor %q in %v", want, a)
+		}
+	}
+	if a["not-a-heading"] {
+		t.Error("headings inside code fences must be ignored")
+	}
+}
+
+func TestCheckLinks(t *testing.T) {
+	root := t.TempDir()
+	write := func(rel, content string) {
+		p := filepath.Join(root, rel)
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("tgs/design/20_requirements.md", "# Requirements\n\n## Interfaces\n- IF-001\n")
+	doc := "See [reqs](../design/20_requirements.md#interfaces) and [top](#guide).\n" +
+		"# Guide\n" +
+		"Broken: [gone](../agentops/review_checklist.md), [anchor](../design/20_requirements.md#nfr), [self](#nope).\n" +
+		"External [site](https://example.com) and `[code](missing.md)` are skipped.\n" +
+		"[ref]: ../design/\n" +
+		"- SR-001 (Source: tgs/design/20_requirements.md#interfaces) (Source: tgs/design/20_requirements.md:3-9) (Source: path#anchor)\n"
+	write("tgs/prompts/review.md", doc)
+
+	links := ExtractLinks("tgs/prompts/review.md", doc, true)
+	if len(links) != 8 {
+		t.Fatalf("expected 8 links, got %+v", links)
+	}
+	var got []string
+	for _, f := range CheckLinks(root, links) {
+		got = append(got, f.String())
+	}
+	want := []string{
+		"tgs/prompts/review.md:3: broken link ../agentops/review_checklist.md: tgs/agentops/review_checklist.md does not exist",
+		"tgs/prompts/review.md:3: broken anchor ../design/20_requirements.md#nfr: no heading #nfr in tgs/design/20_requirements.md",
+		"tgs/prompts/review.md:3: broken anchor #nope: no heading #nope in tgs/prompts/review.md",
+		"tgs/prompts/review.md:6: broken link tgs/design/20_requirements.md#L3-L9: tgs/design/20_requirements.md has 5 lines",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("unexpected findings:\n%s", strings.Join(got, "\n"))
+	}
+}
@@ -0,0 +1,164 @@
+package drift
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"path/filepath"
+	"strings"
+)
+
+// Symbols indexes top-level Go declarations by package, plus the methods and
+// struct fields of each type. Packages are identified by their directory
+// relative to the repository root, so same-named types in different packages
+// (two Config or Server types) keep separate member sets.
+type Symbols struct {
+	packages map[string][]string        // package name -> package paths
+	decls    map[string]map[string]bool // package path -> declared names
+	members  map[string]map[string]bool // "pkgpath.Type" -> methods and fields
+	types    map[string][]string        // type name -> package paths declaring it
+}
+
+// GoSymbols parses the non-test Go files under repoRoot, skipping dot
+// directories, vendor, testdata and node_modules.
+func GoSymbols(repoRoot string) (*Symbols, error) {
+	s := &Symbols{
+		packages: map[string][]string{},
+		decls:    map[string]map[string]bool{},
+		members:  map[string]map[string]bool{},
+		types:    map[string][]string{},
+	}
+	fset := token.NewFileSet()
+	err := filepath.WalkDir(repoRoot, func(p string, e fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if e.IsDir() {
+			name := e.Name()
+			if p != repoRoot && (strings.HasPrefix(name, ".") || name == "vendor" || name == "testdata" || name == "node_modules") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(p, ".go") || strings.HasSuffix(p, "_test.go") {
+			return nil
+		}
+		f, err := parser.ParseFile(fset, p, nil, parser.SkipObjectResolution)
+		if err != nil {
+			return nil // unparsable files are reported by the compiler, not here
+		}
+		dir, err := filepath.Rel(repoRoot, filepath.Dir(p))
+		if err != nil {
+			return nil
+		}
+		s.add(filepath.ToSlash(dir), f)
+		return nil
+	})
+	return s, err
+}
+
+func (s *Symbols) add(pkgPath string, f *ast.File) {
+	pkg := f.Name.Name
+	if s.decls[pkgPath] == nil {
+		s.decls[pkgPath] = map[string]bool{}
+		s.packages[pkg] = append(s.packages[pkg], pkgPath)
+	}
+	names := s.decls[pkgPath]
+	member := func(typ, name string) {
+		key := pkgPath + "." + typ
+		if s.members[key] == nil {
+			s.members[key] = map[string]bool{}
+		}
+		s.members[key][name] = true
+	}
+	for _, d := range f.Decls {
+		switch d := d.(type) {
+		case *ast.FuncDecl:
+			if d.Recv == nil || len(d.Recv.List) == 0 {
+				names[d.Name.Name] = true
+				continue
+			}
+			if typ := receiverType(d.Recv.List[0].Type); typ != "" {
+				member(typ, d.Name.Name)
+			}
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				switch spec := spec.(type) {
+				case *ast.TypeSpec:
+					typ := spec.Name.Name
+					names[typ] = true
+					s.types[typ] = append(s.types[typ], pkgPath)
+					if s.members[pkgPath+"."+typ] == nil {
+						s.members[pkgPath+"."+typ] = map[string]bool{}
+					}
+					switch t := spec.Type.(type) {
+					case *ast.StructType:
+						for _, fld := range t.Fields.List {
+							for _, n := range fld.Names {
+								member(typ, n.Name)
+							}
+							if len(fld.Names) == 0 {
+								member(typ, receiverType(fld.Type))
+							}
+						}
+					case *ast.InterfaceType:
+						for _, m := range t.Methods.List {
+							for _, n := range m.Names {
+								member(typ, n.Name)
+							}
+						}
+					}
+				case *ast.ValueSpec:
+					for _, n := range spec.Names {
+						names[n.Name] = true
+					}
+				}
+			}
+		}
+	}
+}
+
+func receiverType(e ast.Expr) string {
+	switch t := e.(type) {
+	case *ast.StarExpr:
+		return receiverType(t.X)
+	case *ast.Ident:
+		return t.Name
+	case *ast.SelectorExpr:
+		return t.Sel.Name
+	case *ast.IndexExpr:
+		return receiverType(t.X)
+	case *ast.IndexListExpr:
+		return receiverType(t.X)
+	}
+	return ""
+}
+
+// Resolve looks up "pkg.Name", "pkg.Type.Member" or "Type.Member". known is
+// false when the qualifier is not a package or type of the repository (e.g.
+// "os.Exit"), in which case the reference is not checked. A package or type
+// name shared by several packages resolves when any of them declares the name.
+func (s *Symbols) Resolve(sym string) (ok, known bool) {
+	parts := strings.Split(sym, ".")
+	if paths, isPkg := s.packages[parts[0]]; isPkg {
+		for _, p := range paths {
+			if !s.decls[p][parts[1]] {
+				continue
+			}
+			if len(parts) == 2 || s.members[p+"."+parts[1]][parts[2]] {
+				return true, true
+			}
+		}
+		return false, true
+	}
+	if paths, isType := s.types[parts[0]]; isType && len(parts) == 2 {
+		for _, p := range paths {
+			if s.members[p+"."+parts[0]][parts[1]] {
+				return true, true
+			}
+		}
+		return false, true
+	}
+	return false, false
+}
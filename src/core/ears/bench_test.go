@@ -0,0 +1,34 @@
+package ears
+
+import "testing"
+
+// Run with: go test -bench . -benchmem ./src/core/ears
+
+func BenchmarkParseAll_Sequential(b *testing.B) {
+	lines := sampleLines(1000)
+	for i := 0; i < b.N; i++ {
+		(*Linter)(nil).ParseAll("en", lines)
+	}
+}
+
+func BenchmarkParseAll_Parallel(b *testing.B) {
+	lines := sampleLines(1000)
+	l := &Linter{}
+	for i := 0; i < b.N; i++ {
+		l.ParseAll("en", lines)
+	}
+}
+
+func BenchmarkParseAll_Cached(b *testing.B) {
+	lines := sampleLines(1000)
+	c, err := OpenCache(b.TempDir())
+	if err != nil {
+		b.Fatal(err)
+	}
+	l := &Linter{Cache: c}
+	l.ParseAll("en", lines) // warm
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.ParseAll("en", lines)
+	}
+}
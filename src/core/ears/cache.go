@@ -0,0 +1,252 @@
+package ears
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// cacheVersion is mixed into every key; bump it when parsing behaviour changes
+// so stale results from older binaries are ignored.
+const cacheVersion = "ears-v2"
+
+// CacheFile is the cache file name inside the cache directory.
+const CacheFile = "ears-cache.json"
+
+// MaxCacheEntries caps the number of cached parse results; the least recently
+// used entries are evicted beyond it.
+const MaxCacheEntries = 20000
+
+type cacheEntry struct {
+	Key    string  `json:"key"`
+	Result *Result `json:"result,omitempty"`
+	Err    string  `json:"err,omitempty"`
+}
+
+// Cache memoises parse results by a content hash of (language, line) and is
+// persisted as JSON so unchanged requirements are not re-parsed across runs.
+// It holds at most MaxCacheEntries results, evicting the least recently used.
+// It is safe for concurrent use.
+type Cache struct {
+	mu      sync.Mutex
+	path    string
+	max     int
+	order   *list.List // of *cacheEntry, most recently used first
+	entries map[string]*list.Element
+	dirty   bool
+	hits    int
+	misses  int
+}
+
+// OpenCache loads dir/ears-cache.json, starting empty when it does not exist or is unreadable.
+func OpenCache(dir string) (*Cache, error) {
+	c := &Cache{path: filepath.Join(dir, CacheFile), max: MaxCacheEntries, order: list.New(), entries: make(map[string]*list.Element)}
+	data, err := os.ReadFile(c.path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return c, nil
+		}
+		return c, err
+	}
+	var stored []*cacheEntry
+	if err := json.Unmarshal(data, &stored); err != nil {
+		// a corrupt cache is discarded and rewritten on Save
+		c.dirty = true
+		return c, nil
+	}
+	for _, e := range stored {
+		if _, dup := c.entries[e.Key]; dup || e.Key == "" {
+			continue
+		}
+		c.entries[e.Key] = c.order.PushBack(e)
+	}
+	c.evict()
+	return c, nil
+}
+
+// evict drops the least recently used entries beyond the cap. c.mu must be held.
+func (c *Cache) evict() {
+	for c.order.Len() > c.max {
+		last := c.order.Back()
+		delete(c.entries, last.Value.(*cacheEntry).Key)
+		c.order.Remove(last)
+		c.dirty = true
+	}
+}
+
+// cacheKey hashes the language, the known system names (in order) and the line.
+func cacheKey(lang string, names []string, line string) string {
+	sum := sha256.Sum256([]byte(cacheVersion + "\x00" + lang + "\x00" + strings.Join(names, "\x1f") + "\x00" + line))
+	return hex.EncodeToString(sum[:])
+}
+
+// Get returns a cached parse result for line parsed with the given system names.
+func (c *Cache) Get(lang string, names []string, line string) (Result, error, bool) {
+	if c == nil {
+		return Result{}, nil, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.entries[cacheKey(lang, names, line)]
+	if !ok {
+		c.misses++
+		return Result{}, nil, false
+	}
+	c.hits++
+	if el != c.order.Front() {
+		c.order.MoveToFront(el)
+		c.dirty = true
+	}
+	e := el.Value.(*cacheEntry)
+	if e.Err != "" {
+		return Result{}, errors.New(e.Err), true
+	}
+	if e.Result == nil {
+		return Result{}, nil, true
+	}
+	return *e.Result, nil, true
+}
+
+// Put records the parse result for line parsed with the given system names.
+func (c *Cache) Put(lang string, names []string, line string, res Result, err error) {
+	if c == nil {
+		return
+	}
+	e := &cacheEntry{Key: cacheKey(lang, names, line)}
+	if err != nil {
+		e.Err = err.Error()
+	} else {
+		e.Result = &res
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.entries[e.Key]; ok {
+		el.Value = e
+		c.order.MoveToFront(el)
+	} else {
+		c.entries[e.Key] = c.order.PushFront(e)
+		c.evict()
+	}
+	c.dirty = true
+}
+
+// Stats returns the number of cache hits and misses since the cache was opened.
+func (c *Cache) Stats() (hits, misses int) {
+	if c == nil {
+		return 0, 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.hits, c.misses
+}
+
+// Save writes the cache to disk if it changed.
+func (c *Cache) Save() error {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.dirty {
+		return nil
+	}
+	stored := make([]*cacheEntry, 0, c.order.Len())
+	for el := c.order.Front(); el != nil; el = el.Next() {
+		stored = append(stored, el.Value.(*cacheEntry))
+	}
+	data, err := json.Marshal(stored)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o755); err != nil {
+		return err
+	}
+	tmp := c.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, c.path); err != nil {
+		return err
+	}
+	c.dirty = false
+	return nil
+}
+
+// Parsed is the outcome of parsing one line.
+type Parsed struct {
+	Result Result
+	Err    error
+}
+
+// Linter parses batches of requirement lines on a worker pool, consulting an
+// optional Cache first. The zero value parses on GOMAXPROCS workers without caching.
+type Linter struct {
+	Workers int
+	Cache   *Cache
+	// Names are known system names, such as glossary terms, passed to
+	// ParseRequirementWithNames.
+	Names []string
+}
+
+// ParseAll parses lines with the keyword profile for lang; results are returned
+// in input order. A nil Linter parses sequentially without a cache.
+func (l *Linter) ParseAll(lang string, lines []string) []Parsed {
+	out := make([]Parsed, len(lines))
+	parseOne := func(i int) {
+		var (
+			cache *Cache
+			names []string
+		)
+		if l != nil {
+			cache, names = l.Cache, l.Names
+		}
+		if res, err, ok := cache.Get(lang, names, lines[i]); ok {
+			out[i] = Parsed{Result: res, Err: err}
+			return
+		}
+		res, err := ParseRequirementWithNames(lang, lines[i], names)
+		cache.Put(lang, names, lines[i], res, err)
+		out[i] = Parsed{Result: res, Err: err}
+	}
+	workers := 1
+	if l != nil {
+		workers = l.Workers
+		if workers <= 0 {
+			workers = runtime.GOMAXPROCS(0)
+		}
+	}
+	if workers > len(lines) {
+		workers = len(lines)
+	}
+	if workers <= 1 {
+		for i := range lines {
+			parseOne(i)
+		}
+		return out
+	}
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				parseOne(i)
+			}
+		}()
+	}
+	for i := range lines {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	return out
+}
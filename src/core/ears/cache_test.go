@@ -0,0 +1,117 @@
+package ears
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func sampleLines(n int) []string {
+	forms := []string{
+		"When request %d arrives, the gateway shall log the request",
+		"While in mode %d, the system shall alert within 200 ms",
+		"If overheating %d occurs, then the controller shall shut down",
+		"The service shall record event %d",
+		"Because of %d the system might respond",
+	}
+	lines := make([]string, n)
+	for i := range lines {
+		lines[i] = fmt.Sprintf(forms[i%len(forms)], i)
+	}
+	return lines
+}
+
+func TestLinter_ParallelMatchesSequential(t *testing.T) {
+	lines := sampleLines(50)
+	seq := (*Linter)(nil).ParseAll("en", lines)
+	par := (&Linter{Workers: 4}).ParseAll("en", lines)
+	for i := range lines {
+		if !reflect.DeepEqual(seq[i].Result, par[i].Result) || (seq[i].Err == nil) != (par[i].Err == nil) {
+			t.Fatalf("line %d differs: %+v vs %+v", i, seq[i], par[i])
+		}
+	}
+	if par[4].Err == nil {
+		t.Fatalf("expected invalid line to report an error")
+	}
+}
+
+func TestCache_PersistsResults(t *testing.T) {
+	dir := t.TempDir()
+	lines := sampleLines(10)
+	c, err := OpenCache(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := (&Linter{Workers: 2, Cache: c}).ParseAll("en", lines)
+	if hits, misses := c.Stats(); hits != 0 || misses != 10 {
+		t.Fatalf("expected 0 hits/10 misses, got %d/%d", hits, misses)
+	}
+	if err := c.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	c2, err := OpenCache(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second := (&Linter{Workers: 2, Cache: c2}).ParseAll("en", lines)
+	if hits, misses := c2.Stats(); hits != 10 || misses != 0 {
+		t.Fatalf("expected 10 hits/0 misses, got %d/%d", hits, misses)
+	}
+	for i := range lines {
+		if !reflect.DeepEqual(first[i].Result, second[i].Result) {
+			t.Fatalf("line %d: cached result differs: %+v vs %+v", i, first[i].Result, second[i].Result)
+		}
+		if (first[i].Err == nil) != (second[i].Err == nil) || (first[i].Err != nil && first[i].Err.Error() != second[i].Err.Error()) {
+			t.Fatalf("line %d: cached error differs: %v vs %v", i, first[i].Err, second[i].Err)
+		}
+	}
+	// Language is part of the key
+	(&Linter{Cache: c2}).ParseAll("de", lines[:1])
+	if _, misses := c2.Stats(); misses != 1 {
+		t.Fatalf("expected a miss for another language, got %d", misses)
+	}
+}
+
+func TestCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	dir := t.TempDir()
+	lines := sampleLines(4)
+	c, err := OpenCache(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.max = 3
+	l := &Linter{Cache: c}
+	l.ParseAll("en", lines[:3])
+	l.ParseAll("en", lines[:1]) // lines[0] is now the most recently used
+	l.ParseAll("en", lines[3:]) // evicts lines[1]
+	if err := c.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	c2, err := OpenCache(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, want := range []bool{true, false, true, true} {
+		if _, _, ok := c2.Get("en", nil, lines[i]); ok != want {
+			t.Errorf("line %d cached = %v, want %v", i, ok, want)
+		}
+	}
+}
+
+func TestLinter_NamesArePartOfTheKey(t *testing.T) {
+	c, err := OpenCache(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	line := []string{"Wenn eine Zahlung eingeht, soll der Payment Service Belege erzeugen"}
+	plain := (&Linter{Cache: c}).ParseAll("de", line)
+	named := (&Linter{Cache: c, Names: []string{"Payment Service"}}).ParseAll("de", line)
+	if plain[0].Result.System != "Payment" || named[0].Result.System != "Payment Service" {
+		t.Fatalf("systems = %q, %q", plain[0].Result.System, named[0].Result.System)
+	}
+	if hits, _ := c.Stats(); hits != 0 {
+		t.Fatalf("expected the named parse not to reuse the plain entry, got %d hits", hits)
+	}
+}
@@ -0,0 +1,72 @@
+package ears
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Format rewrites a valid English requirement into the canonical EARS layout:
+// capitalised leading keyword, lowercase keywords elsewhere, single spaces, no
+// space before commas and "the <system> shall [not] <response>". Clause wording
+// (including "and"/"or" between preconditions) is preserved.
+func Format(line string) (string, error) {
+	res, err := ParseRequirement(line)
+	if err != nil {
+		return "", err
+	}
+	segs := strings.Split(line, ",")
+	leading := 0
+	switch res.Shape {
+	case ShapeComplex:
+		leading = 2
+	case ShapeEvent, ShapeState:
+		leading = 1
+	case ShapeUnwanted:
+		leading = 1
+		if len(res.Preconditions) > 0 {
+			leading = 2
+		}
+	}
+	var parts []string
+	for i := 0; i < leading && i < len(segs); i++ {
+		words := strings.Fields(segs[i])
+		if len(words) == 0 {
+			continue
+		}
+		parts = append(parts, strings.ToLower(words[0])+" "+strings.Join(words[1:], " "))
+	}
+	main := "the " + strings.Join(strings.Fields(res.System), " ")
+	if strings.EqualFold(res.System, "it") && isPronounSubject(segs, leading) {
+		main = "it"
+	}
+	if res.Shape == ShapeUnwanted {
+		main = "then " + main
+	}
+	main += " shall "
+	if res.Polarity == PolarityNegative {
+		main += "not "
+	}
+	main += strings.Join(strings.Fields(res.Response), " ")
+	parts = append(parts, main)
+	return capitalize(strings.Join(parts, ", ")), nil
+}
+
+func isPronounSubject(segs []string, leading int) bool {
+	if leading >= len(segs) {
+		return false
+	}
+	words := strings.Fields(strings.ToLower(segs[leading]))
+	if len(words) > 0 && words[0] == "then" {
+		words = words[1:]
+	}
+	return len(words) > 0 && words[0] == "it"
+}
+
+func capitalize(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+	if r == utf8.RuneError {
+		return s
+	}
+	return string(unicode.ToUpper(r)) + s[size:]
+}
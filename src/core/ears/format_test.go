@@ -0,0 +1,24 @@
+package ears
+
+import "testing"
+
+func TestFormat_Canonical(t *testing.T) {
+	cases := map[string]string{
+		"the  system SHALL record events":                                                          "The system shall record events",
+		"WHEN button is pressed , THE controller Shall start":                                      "When button is pressed, the controller shall start",
+		"while battery is low or door is open, when charger is connected, the device shall charge": "While battery is low or door is open, when charger is connected, the device shall charge",
+		"If overheating, THEN it shall NOT resume":                                                 "If overheating, then it shall not resume",
+	}
+	for in, want := range cases {
+		got, err := Format(in)
+		if err != nil {
+			t.Fatalf("%q: %v", in, err)
+		}
+		if got != want {
+			t.Fatalf("%q: got %q want %q", in, got, want)
+		}
+	}
+	if _, err := Format("Because of X the system might respond"); err == nil {
+		t.Fatalf("expected error for invalid requirement")
+	}
+}
@@ -0,0 +1,360 @@
+package ears
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Term is a controlled vocabulary entry with its accepted synonyms.
+// A term with ReplacedBy set (or Deprecated true) should no longer be used.
+type Term struct {
+	Name       string   `yaml:"name"`
+	Synonyms   []string `yaml:"synonyms"`
+	Deprecated bool     `yaml:"deprecated"`
+	ReplacedBy string   `yaml:"replaced_by"`
+}
+
+func (t Term) isDeprecated() bool { return t.Deprecated || strings.TrimSpace(t.ReplacedBy) != "" }
+
+// Glossary lists the allowed system/component names, states and events.
+type Glossary struct {
+	Systems    []Term `yaml:"systems"`
+	Components []Term `yaml:"components"`
+	States     []Term `yaml:"states"`
+	Events     []Term `yaml:"events"`
+}
+
+// DefaultGlossaryPaths are probed in order when no explicit glossary is configured.
+func DefaultGlossaryPaths() []string {
+	return []string{"tgs/design/glossary.yml", "tgs/design/00_context.md"}
+}
+
+// LoadGlossary reads a glossary from a YAML file or from the "Glossary" section
+// of a Markdown document. It returns (nil, nil) when a Markdown file has no glossary.
+func LoadGlossary(path string) (*Glossary, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	ext := strings.ToLower(filepath.Ext(path))
+	if ext == ".yml" || ext == ".yaml" {
+		var g Glossary
+		if err := yaml.Unmarshal(data, &g); err != nil {
+			return nil, fmt.Errorf("parse glossary %s: %w", path, err)
+		}
+		return &g, nil
+	}
+	return ParseGlossaryMarkdown(string(data))
+}
+
+// FindGlossary loads the configured glossary relative to repoRoot, or probes
+// DefaultGlossaryPaths when configured is empty. It returns (nil, nil) if none exists.
+func FindGlossary(repoRoot, configured string) (*Glossary, error) {
+	if strings.TrimSpace(configured) != "" {
+		return LoadGlossary(filepath.Join(repoRoot, configured))
+	}
+	for _, rel := range DefaultGlossaryPaths() {
+		g, err := LoadGlossary(filepath.Join(repoRoot, rel))
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			return nil, err
+		}
+		if g != nil {
+			return g, nil
+		}
+	}
+	return nil, nil
+}
+
+// ParseGlossaryMarkdown extracts a glossary from a Markdown table placed under a
+// heading named "Glossary", e.g.:
+//
+//	## Glossary
+//	| Term | Kind | Synonyms | Replaced By |
+//	|------|------|----------|-------------|
+//	| tgs CLI | system | the CLI, tgs | |
+//	| TGS tool | system | | tgs CLI |
+//
+// Kind is one of system, component, state or event. Synonyms are comma-separated.
+func ParseGlossaryMarkdown(doc string) (*Glossary, error) {
+	var (
+		g       Glossary
+		found   bool
+		inGloss bool
+		header  []string
+	)
+	sc := bufio.NewScanner(strings.NewReader(doc))
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for sc.Scan() {
+		trimmed := strings.TrimSpace(sc.Text())
+		if strings.HasPrefix(trimmed, "#") {
+			title := strings.TrimSpace(strings.TrimLeft(trimmed, "#"))
+			inGloss = strings.EqualFold(title, "glossary")
+			header = nil
+			continue
+		}
+		if !inGloss || !strings.HasPrefix(trimmed, "|") {
+			continue
+		}
+		cells := splitTableRow(trimmed)
+		if header == nil {
+			header = make([]string, len(cells))
+			for i, c := range cells {
+				header[i] = strings.ToLower(c)
+			}
+			continue
+		}
+		if isTableSeparator(cells) {
+			continue
+		}
+		row := make(map[string]string, len(cells))
+		for i, c := range cells {
+			if i < len(header) {
+				row[header[i]] = c
+			}
+		}
+		term := Term{Name: row["term"], ReplacedBy: row["replaced by"]}
+		if term.Name == "" {
+			continue
+		}
+		for _, s := range strings.Split(row["synonyms"], ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				term.Synonyms = append(term.Synonyms, s)
+			}
+		}
+		found = true
+		switch strings.ToLower(row["kind"]) {
+		case "component":
+			g.Components = append(g.Components, term)
+		case "state":
+			g.States = append(g.States, term)
+		case "event":
+			g.Events = append(g.Events, term)
+		default:
+			g.Systems = append(g.Systems, term)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, nil
+	}
+	return &g, nil
+}
+
+func splitTableRow(row string) []string {
+	row = strings.TrimSpace(row)
+	row = strings.TrimPrefix(row, "|")
+	row = strings.TrimSuffix(row, "|")
+	parts := strings.Split(row, "|")
+	for i := range parts {
+		parts[i] = strings.Trim(strings.TrimSpace(parts[i]), "`")
+	}
+	return parts
+}
+
+func isTableSeparator(cells []string) bool {
+	for _, c := range cells {
+		if strings.Trim(c, "-: ") != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// normalizeTerm lowercases, strips a leading article and collapses whitespace.
+func normalizeTerm(s string) string {
+	s = strings.ToLower(strings.Trim(strings.TrimSpace(s), "`*\"'"))
+	s = strings.Join(strings.Fields(s), " ")
+	s = strings.TrimPrefix(s, "the ")
+	return strings.Trim(s, "`")
+}
+
+// SystemNames lists the system and component names with their synonyms.
+func (g *Glossary) SystemNames() []string {
+	if g == nil {
+		return nil
+	}
+	var out []string
+	for _, t := range append(append([]Term{}, g.Systems...), g.Components...) {
+		out = append(append(out, t.Name), t.Synonyms...)
+	}
+	return out
+}
+
+// CheckSystem validates a parsed system name against the glossary and returns
+// a message when it is unknown, a synonym, or deprecated. Pronouns are skipped.
+func (g *Glossary) CheckSystem(system string) string {
+	if g == nil {
+		return ""
+	}
+	name := normalizeTerm(system)
+	if name == "" || name == "it" {
+		return ""
+	}
+	terms := append(append([]Term{}, g.Systems...), g.Components...)
+	if len(terms) == 0 {
+		return ""
+	}
+	for _, t := range terms {
+		if normalizeTerm(t.Name) == name {
+			if t.isDeprecated() {
+				return deprecatedMsg("system name", system, t)
+			}
+			return ""
+		}
+	}
+	for _, t := range terms {
+		for _, syn := range t.Synonyms {
+			if normalizeTerm(syn) == name {
+				if t.isDeprecated() {
+					return deprecatedMsg("system name", system, t)
+				}
+				return fmt.Sprintf("non-canonical system name %q (use %q)", system, t.Name)
+			}
+		}
+	}
+	if s := closestTerm(name, terms); s != "" {
+		return fmt.Sprintf("unknown system name %q (did you mean %q?)", system, s)
+	}
+	return fmt.Sprintf("unknown system name %q (not in glossary)", system)
+}
+
+// CheckPhrases flags synonyms or deprecated terms of states and events used in
+// preconditions and triggers. Unlisted free text is not reported.
+func (g *Glossary) CheckPhrases(res Result) []string {
+	if g == nil {
+		return nil
+	}
+	var msgs []string
+	for _, pc := range res.Preconditions {
+		msgs = append(msgs, checkPhrase("state", pc, g.States)...)
+	}
+	if res.Trigger != "" {
+		msgs = append(msgs, checkPhrase("event", res.Trigger, g.Events)...)
+	}
+	return msgs
+}
+
+// Check runs all glossary checks for a parsed requirement.
+func (g *Glossary) Check(res Result) []string {
+	if g == nil {
+		return nil
+	}
+	var msgs []string
+	if m := g.CheckSystem(res.System); m != "" {
+		msgs = append(msgs, m)
+	}
+	return append(msgs, g.CheckPhrases(res)...)
+}
+
+func checkPhrase(kind, text string, terms []Term) []string {
+	var msgs []string
+	for _, t := range terms {
+		if t.isDeprecated() && containsPhrase(text, t.Name) {
+			msgs = append(msgs, deprecatedMsg(kind, t.Name, t))
+			continue
+		}
+		if containsPhrase(text, t.Name) {
+			continue
+		}
+		for _, syn := range t.Synonyms {
+			if containsPhrase(text, syn) {
+				if t.isDeprecated() {
+					msgs = append(msgs, deprecatedMsg(kind, syn, t))
+				} else {
+					msgs = append(msgs, fmt.Sprintf("non-canonical %s %q (use %q)", kind, syn, t.Name))
+				}
+				break
+			}
+		}
+	}
+	return msgs
+}
+
+func deprecatedMsg(kind, used string, t Term) string {
+	if strings.TrimSpace(t.ReplacedBy) != "" {
+		return fmt.Sprintf("deprecated %s %q (use %q)", kind, used, t.ReplacedBy)
+	}
+	return fmt.Sprintf("deprecated %s %q", kind, used)
+}
+
+// containsPhrase reports whether phrase occurs in text on word boundaries, ignoring case.
+func containsPhrase(text, phrase string) bool {
+	phrase = normalizeTerm(phrase)
+	if phrase == "" {
+		return false
+	}
+	re, err := regexp.Compile(`(?i)(^|[^\pL\pN])` + regexp.QuoteMeta(phrase) + `($|[^\pL\pN])`)
+	if err != nil {
+		return false
+	}
+	return re.MatchString(strings.Join(strings.Fields(text), " "))
+}
+
+// closestTerm suggests the canonical term nearest to name by edit distance
+// or shared words; it returns "" when nothing is reasonably close.
+func closestTerm(name string, terms []Term) string {
+	best, bestDist := "", -1
+	for _, t := range terms {
+		if t.isDeprecated() {
+			continue
+		}
+		cands := append([]string{t.Name}, t.Synonyms...)
+		for _, c := range cands {
+			d := levenshtein(name, normalizeTerm(c))
+			if bestDist < 0 || d < bestDist {
+				best, bestDist = t.Name, d
+			}
+		}
+	}
+	if bestDist >= 0 && bestDist <= max(2, len(name)/3) {
+		return best
+	}
+	nameWords := strings.Fields(name)
+	for _, t := range terms {
+		if t.isDeprecated() {
+			continue
+		}
+		for _, w := range strings.Fields(normalizeTerm(t.Name)) {
+			for _, nw := range nameWords {
+				if len(w) > 2 && w == nw {
+					return t.Name
+				}
+			}
+		}
+	}
+	return ""
+}
+
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
@@ -0,0 +1,95 @@
+package ears
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const glossaryYAML = `systems:
+  - name: tgs CLI
+    synonyms: [the CLI, tgs]
+  - name: TGS tool
+    replaced_by: tgs CLI
+components:
+  - name: EARS linter
+states:
+  - name: CI mode
+    synonyms: [pipeline mode]
+events:
+  - name: verification is requested
+    synonyms: [user runs verify]
+`
+
+func TestGlossary_CheckSystem(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "glossary.yml")
+	if err := os.WriteFile(path, []byte(glossaryYAML), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	g, err := LoadGlossary(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	cases := []struct {
+		line string
+		want string // substring of message; empty means no issue
+	}{
+		{line: "The tgs CLI shall report results", want: ""},
+		{line: "The EARS linter shall report results", want: ""},
+		{line: "It shall report results", want: ""},
+		{line: "The CLI shall report results", want: `use "tgs CLI"`},
+		{line: "The TGS tool shall report results", want: "deprecated system name"},
+		{line: "The tgs CLl shall report results", want: `did you mean "tgs CLI"`},
+		{line: "The payment gateway shall report results", want: "not in glossary"},
+	}
+	for _, tc := range cases {
+		res, err := ParseRequirement(tc.line)
+		if err != nil {
+			t.Fatalf("parse %q: %v", tc.line, err)
+		}
+		got := g.CheckSystem(res.System)
+		if tc.want == "" && got != "" {
+			t.Fatalf("%q: unexpected issue %q", tc.line, got)
+		}
+		if tc.want != "" && !strings.Contains(got, tc.want) {
+			t.Fatalf("%q: want message containing %q, got %q", tc.line, tc.want, got)
+		}
+	}
+}
+
+func TestGlossary_CheckPhrases(t *testing.T) {
+	g := &Glossary{
+		States: []Term{{Name: "CI mode", Synonyms: []string{"pipeline mode"}}},
+		Events: []Term{{Name: "verification is requested", Synonyms: []string{"user runs verify"}}},
+	}
+	res, err := ParseRequirement("While in pipeline mode, when user runs verify, the tgs CLI shall exit non-zero on issues")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	msgs := g.CheckPhrases(res)
+	if len(msgs) != 2 {
+		t.Fatalf("expected 2 messages, got %v", msgs)
+	}
+	if !strings.Contains(msgs[0], `use "CI mode"`) || !strings.Contains(msgs[1], `use "verification is requested"`) {
+		t.Fatalf("unexpected messages: %v", msgs)
+	}
+}
+
+func TestGlossary_Markdown(t *testing.T) {
+	doc := "# Context\n\n## Glossary\n| Term | Kind | Synonyms | Replaced By |\n|---|---|---|---|\n| tgs CLI | system | the CLI, tgs | |\n| TGS tool | system | | tgs CLI |\n| armed | state | | |\n\n## Scope\n| not | a | glossary |\n"
+	g, err := ParseGlossaryMarkdown(doc)
+	if err != nil || g == nil {
+		t.Fatalf("parse markdown glossary: g=%v err=%v", g, err)
+	}
+	if len(g.Systems) != 2 || len(g.States) != 1 {
+		t.Fatalf("unexpected glossary: %+v", g)
+	}
+	if got := g.CheckSystem("tgs"); !strings.Contains(got, "non-canonical") {
+		t.Fatalf("expected synonym to be flagged, got %q", got)
+	}
+	if none, _ := ParseGlossaryMarkdown("# Context\n\nNo glossary here.\n"); none != nil {
+		t.Fatalf("expected nil glossary when section missing")
+	}
+}
@@ -0,0 +1,399 @@
+package ears
+
+import (
+	"errors"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// Language is a keyword profile for writing EARS requirements in a language other
+// than English. Localized lines are rewritten onto the English keywords understood
+// by the grammar, so every profile yields the same Result structure; free text
+// (system, preconditions, trigger, response) is kept as written.
+type Language struct {
+	Code     string
+	While    []string // e.g. "während"
+	When     []string // e.g. "wenn", "sobald"
+	If       []string // e.g. "falls"
+	Then     []string // e.g. "dann"
+	Shall    []string // modal verbs, e.g. "soll", "muss"
+	Not      []string // negation words removed from the response, e.g. "nicht"
+	Articles []string // definite articles introducing the system name
+	Pronouns []string // pronoun subjects equivalent to "it"
+	And      []string // precondition conjunctions
+	Or       []string
+	// ModalFirst is set for verb-second languages (German) where the modal precedes
+	// the system after a leading clause: "Wenn X, soll das System Y".
+	ModalFirst bool
+	// NegationAround lists words wrapping the modal for negation, e.g. French "ne doit pas".
+	NegationAround [2]string
+	// DecimalComma reads "0,5" in quantities as 0.5.
+	DecimalComma bool
+	// Comparisons maps lower-case comparison phrases to comparators, e.g.
+	// "innerhalb von" to "<="; they are matched next to the English ones.
+	Comparisons map[string]Comparator
+	// Units maps lower-case unit spellings to canonical symbols, e.g. "sekunden" to "s".
+	Units map[string]string
+
+	boundOnce sync.Once
+	boundRe   *regexp.Regexp
+}
+
+var languages = map[string]*Language{
+	"de": {
+		Code:         "de",
+		While:        []string{"während", "solange"},
+		When:         []string{"wenn", "sobald"},
+		If:           []string{"falls"},
+		Then:         []string{"dann"},
+		Shall:        []string{"soll", "muss", "sollen", "müssen"},
+		Not:          []string{"nicht"},
+		Articles:     []string{"der", "die", "das"},
+		Pronouns:     []string{"es", "er", "sie"},
+		And:          []string{"und"},
+		Or:           []string{"oder"},
+		ModalFirst:   true,
+		DecimalComma: true,
+		Comparisons: map[string]Comparator{
+			"innerhalb von": CompLE, "innerhalb": CompLE, "binnen": CompLE,
+			"höchstens": CompLE, "maximal": CompLE, "bis zu": CompLE, "nicht mehr als": CompLE, "spätestens nach": CompLE,
+			"mindestens": CompGE, "nicht weniger als": CompGE,
+			"weniger als": CompLT, "unter": CompLT, "unterhalb von": CompLT,
+			"mehr als": CompGT, "oberhalb von": CompGT,
+		},
+		Units: map[string]string{
+			"millisekunde": "ms", "millisekunden": "ms",
+			"sekunde": "s", "sekunden": "s",
+			"minuten": "min",
+			"stunde":  "h", "stunden": "h",
+			"tag": "d", "tage": "d", "tagen": "d",
+			"prozent": "%",
+		},
+	},
+	"fr": {
+		Code:           "fr",
+		While:          []string{"tant que", "pendant que"},
+		When:           []string{"lorsque", "lorsqu'", "quand", "dès que", "dès qu'"},
+		If:             []string{"si", "s'"},
+		Then:           []string{"alors"},
+		Shall:          []string{"doit", "doivent", "devra"},
+		Articles:       []string{"le", "la", "les", "l'"},
+		Pronouns:       []string{"il", "elle"},
+		And:            []string{"et"},
+		Or:             []string{"ou"},
+		NegationAround: [2]string{"ne", "pas"},
+		DecimalComma:   true,
+		Comparisons: map[string]Comparator{
+			"en moins de": CompLE, "dans un délai de": CompLE, "sous": CompLE,
+			"au plus": CompLE, "au maximum": CompLE, "jusqu'à": CompLE, "pas plus de": CompLE,
+			"au moins": CompGE, "au minimum": CompGE, "pas moins de": CompGE,
+			"moins de": CompLT,
+			"plus de":  CompGT,
+		},
+		Units: map[string]string{
+			"milliseconde": "ms", "millisecondes": "ms",
+			"seconde": "s", "secondes": "s",
+			"heure": "h", "heures": "h",
+			"jour": "d", "jours": "d",
+			"pour cent": "%",
+			"octet":     "B", "octets": "B",
+		},
+	},
+}
+
+// Languages returns the supported language codes, including "en".
+func Languages() []string {
+	out := []string{"en"}
+	for code := range languages {
+		out = append(out, code)
+	}
+	sort.Strings(out[1:])
+	return out
+}
+
+// LookupLanguage returns the profile for code; English (and "") returns nil, true.
+func LookupLanguage(code string) (*Language, bool) {
+	code = strings.ToLower(strings.TrimSpace(code))
+	if code == "" || code == "en" {
+		return nil, true
+	}
+	l, ok := languages[code]
+	return l, ok
+}
+
+var languageMarkerRe = regexp.MustCompile(`(?im)^\s*<!--\s*ears-language:\s*([a-z]{2})\s*-->\s*$`)
+
+// DetectLanguage returns the language selected by an `<!-- ears-language: de -->`
+// marker in a Markdown document, or "" when the document has none.
+func DetectLanguage(doc string) string {
+	if m := languageMarkerRe.FindStringSubmatch(doc); m != nil {
+		return strings.ToLower(m[1])
+	}
+	return ""
+}
+
+// ParseRequirementIn parses line using the keyword profile for lang.
+func ParseRequirementIn(lang, line string) (Result, error) {
+	return ParseRequirementWithNames(lang, line, nil)
+}
+
+// ParseRequirementWithNames is ParseRequirementIn with known system names, such
+// as glossary terms. In verb-second profiles the system after a leading clause
+// is one noun; a listed multi-word name is kept whole instead.
+func ParseRequirementWithNames(lang, line string, names []string) (Result, error) {
+	l, ok := LookupLanguage(lang)
+	if !ok {
+		return Result{}, errors.New("unsupported EARS language " + `"` + lang + `"`)
+	}
+	if l == nil {
+		return ParseRequirement(line)
+	}
+	if l.countModals(line) > 1 {
+		return Result{}, errors.New("multiple 'shall' in one requirement (split into singular requirements)")
+	}
+	res, err := ParseRequirement(l.canonical(line, names))
+	if err == nil {
+		res.Bound = l.extractBound(res.Response, res.Polarity)
+	}
+	return res, err
+}
+
+// boundRegexp returns the bound pattern extended with the profile's phrases and units.
+func (l *Language) boundRegexp() *regexp.Regexp {
+	l.boundOnce.Do(func() { l.boundRe = buildBoundRe(l.Comparisons, l.Units) })
+	return l.boundRe
+}
+
+// HasStarter reports whether s begins with a condition keyword, article or pronoun.
+func (l *Language) HasStarter(s string) bool {
+	low := strings.ToLower(s)
+	for _, set := range [][]string{l.While, l.When, l.If, l.Articles, l.Pronouns} {
+		if _, ok := matchKeyword(low, set); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// HasModal reports whether s contains one of the profile's modal verbs.
+func (l *Language) HasModal(s string) bool { return l.countModals(s) > 0 }
+
+func (l *Language) countModals(s string) int {
+	n := 0
+	for _, w := range splitWords(s) {
+		if containsFold(l.Shall, w) {
+			n++
+		}
+	}
+	return n
+}
+
+// Canonical rewrites a localized requirement onto English EARS keywords, e.g.
+// "Wenn der Knopf gedrückt wird, soll das System den Alarm auslösen" becomes
+// "When der Knopf gedrückt wird, the System shall den Alarm auslösen".
+// Lines that do not follow the profile are returned with only the recognised
+// keywords replaced so that the parser reports the usual errors.
+func (l *Language) Canonical(line string) string { return l.canonical(line, nil) }
+
+func (l *Language) canonical(line string, names []string) string {
+	segs := strings.Split(line, ",")
+	var out []string
+	i := 0
+	for ; i < len(segs); i++ {
+		seg := strings.TrimSpace(segs[i])
+		low := strings.ToLower(seg)
+		if kw, ok := matchKeyword(low, l.While); ok {
+			out = append(out, "while "+l.conjunctions(strings.TrimSpace(seg[len(kw):])))
+			continue
+		}
+		if kw, ok := matchKeyword(low, l.When); ok {
+			out = append(out, "when "+strings.TrimSpace(seg[len(kw):]))
+			continue
+		}
+		if kw, ok := matchKeyword(low, l.If); ok {
+			out = append(out, "if "+strings.TrimSpace(seg[len(kw):]))
+			continue
+		}
+		break
+	}
+	if i >= len(segs) {
+		return strings.Join(out, ", ")
+	}
+	main := strings.TrimSpace(strings.Join(segs[i:], ","))
+	prefix := ""
+	if kw, ok := matchKeyword(strings.ToLower(main), l.Then); ok {
+		prefix = "then "
+		main = strings.TrimSpace(main[len(kw):])
+	}
+	out = append(out, prefix+l.mainClause(main, len(out) > 0, names))
+	return strings.Join(out, ", ")
+}
+
+// mainClause rewrites "<article> <system> <modal> <response>" (or, for verb-second
+// languages after a leading clause, "<modal> <article> <system> <response>").
+func (l *Language) mainClause(s string, afterClause bool, names []string) string {
+	words := strings.Fields(s)
+	if len(words) == 0 {
+		return s
+	}
+	if l.ModalFirst && afterClause && containsFold(l.Shall, words[0]) {
+		subj, rest := l.splitSubjectV2(words[1:], names)
+		return subj + " shall " + l.response(rest)
+	}
+	for j, w := range words {
+		if !containsFold(l.Shall, w) {
+			continue
+		}
+		subjWords := words[:j]
+		rest := words[j+1:]
+		neg := false
+		if na := l.NegationAround; na[0] != "" && len(subjWords) > 0 && strings.EqualFold(subjWords[len(subjWords)-1], na[0]) &&
+			len(rest) > 0 && strings.EqualFold(rest[0], na[1]) {
+			subjWords, rest, neg = subjWords[:len(subjWords)-1], rest[1:], true
+		}
+		resp := l.response(rest)
+		if neg && !strings.HasPrefix(resp, "not ") {
+			resp = "not " + resp
+		}
+		return l.subject(subjWords) + " shall " + resp
+	}
+	return l.subject(words)
+}
+
+// splitSubjectV2 takes the subject after a leading modal: an article or pronoun
+// followed by one of names, or by `code` words and a single noun ("das
+// Zahlungssystem", "der `tgs` Dienst"). German capitalises every noun, so the
+// object in "soll das System Alarme auslösen" is not part of the name.
+func (l *Language) splitSubjectV2(words []string, names []string) (string, []string) {
+	if len(words) == 0 {
+		return "", nil
+	}
+	if containsFold(l.Pronouns, words[0]) {
+		return "it", words[1:]
+	}
+	if !containsFold(l.Articles, words[0]) {
+		return "", words
+	}
+	rest := words[1:]
+	n := 0
+	for _, name := range names {
+		nw := strings.Fields(strings.Trim(name, "`"))
+		if len(nw) > n && len(nw) <= len(rest) && equalFoldWords(rest[:len(nw)], nw) {
+			n = len(nw)
+		}
+	}
+	if n == 0 {
+		for n < len(rest) && strings.HasPrefix(rest[n], "`") {
+			n++
+		}
+		if n < len(rest) && isNameWord(rest[n]) {
+			n++
+		}
+	}
+	return "the " + strings.Join(rest[:n], " "), rest[n:]
+}
+
+func equalFoldWords(a, b []string) bool {
+	for i := range a {
+		if !strings.EqualFold(strings.Trim(a[i], "`"), b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func (l *Language) subject(words []string) string {
+	if len(words) == 0 {
+		return ""
+	}
+	if len(words) == 1 && containsFold(l.Pronouns, words[0]) {
+		return "it"
+	}
+	first := words[0]
+	for _, a := range l.Articles {
+		if strings.HasSuffix(a, "'") && len(first) > len(a) && strings.EqualFold(first[:len(a)], a) {
+			return "the " + strings.Join(append([]string{first[len(a):]}, words[1:]...), " ")
+		}
+	}
+	if containsFold(l.Articles, first) {
+		return "the " + strings.Join(words[1:], " ")
+	}
+	return strings.Join(words, " ")
+}
+
+// response drops negation words and marks the response as negative.
+func (l *Language) response(words []string) string {
+	neg := false
+	kept := make([]string, 0, len(words))
+	for _, w := range words {
+		if containsFold(l.Not, w) {
+			neg = true
+			continue
+		}
+		kept = append(kept, w)
+	}
+	s := strings.Join(kept, " ")
+	if neg {
+		return "not " + s
+	}
+	return s
+}
+
+func (l *Language) conjunctions(s string) string {
+	words := strings.Fields(s)
+	for i, w := range words {
+		switch {
+		case containsFold(l.And, w):
+			words[i] = "and"
+		case containsFold(l.Or, w):
+			words[i] = "or"
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// matchKeyword returns the keyword from set that starts low on a word boundary.
+// Elided keywords ending in an apostrophe ("lorsqu'") match without a following space.
+func matchKeyword(low string, set []string) (string, bool) {
+	best := ""
+	for _, kw := range set {
+		if !strings.HasPrefix(low, kw) {
+			continue
+		}
+		rest := low[len(kw):]
+		if strings.HasSuffix(kw, "'") || rest == "" || rest[0] == ' ' || rest[0] == '\t' {
+			if len(kw) > len(best) {
+				best = kw
+			}
+		}
+	}
+	return best, best != ""
+}
+
+func containsFold(set []string, w string) bool {
+	for _, s := range set {
+		if strings.EqualFold(s, w) {
+			return true
+		}
+	}
+	return false
+}
+
+func isNameWord(w string) bool {
+	if strings.HasPrefix(w, "`") {
+		return true
+	}
+	for _, r := range w {
+		return unicode.IsUpper(r)
+	}
+	return false
+}
+
+// splitWords splits s on Unicode whitespace and punctuation, so "shall." and
+// "shall,\tnot" both yield the bare modal.
+func splitWords(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool { return unicode.IsSpace(r) || unicode.IsPunct(r) })
+}
@@ -0,0 +1,92 @@
+package ears
+
+import "testing"
+
+func TestParseRequirementIn_German(t *testing.T) {
+	cases := []struct {
+		line    string
+		shape   Shape
+		system  string
+		resp    string
+		negated bool
+	}{
+		{"Das Zahlungssystem soll Ereignisse protokollieren", ShapeUbiquitous, "Zahlungssystem", "Ereignisse protokollieren", false},
+		{"Wenn der Knopf gedrückt wird, soll das System den Alarm auslösen", ShapeEvent, "System", "den Alarm auslösen", false},
+		{"Während der Akku schwach ist und die Tür offen ist, soll das Gerät piepen", ShapeState, "Gerät", "piepen", false},
+		{"Falls das Token abgelaufen ist, dann soll das Gateway die Anfrage nicht weiterleiten", ShapeUnwanted, "Gateway", "die Anfrage weiterleiten", true},
+		{"Wenn ein Sensor auslöst, soll das System Alarme auslösen", ShapeEvent, "System", "Alarme auslösen", false},
+		{"Wenn ein Job endet, soll der `tgs` Dienst Berichte schreiben", ShapeEvent, "`tgs` Dienst", "Berichte schreiben", false},
+	}
+	for _, c := range cases {
+		r, err := ParseRequirementIn("de", c.line)
+		if err != nil {
+			t.Fatalf("%q: %v", c.line, err)
+		}
+		if r.Shape != c.shape || r.System != c.system || r.Response != c.resp {
+			t.Fatalf("%q: got %+v", c.line, r)
+		}
+		if (r.Polarity == PolarityNegative) != c.negated {
+			t.Fatalf("%q: polarity %v", c.line, r.Polarity)
+		}
+	}
+	r, _ := ParseRequirementIn("de", "Während der Akku schwach ist und die Tür offen ist, soll das Gerät piepen")
+	if len(r.Preconditions) != 2 {
+		t.Fatalf("expected 2 preconditions split on 'und', got %v", r.Preconditions)
+	}
+}
+
+func TestParseRequirementWithNames_GlossaryTerm(t *testing.T) {
+	line := "Wenn eine Zahlung eingeht, soll das Payment Gateway Belege erzeugen"
+	r, err := ParseRequirementWithNames("de", line, []string{"Ledger", "Payment Gateway"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.System != "Payment Gateway" || r.Response != "Belege erzeugen" {
+		t.Fatalf("got %+v", r)
+	}
+	if r, _ := ParseRequirementIn("de", line); r.System != "Payment" {
+		t.Fatalf("without names: got system %q", r.System)
+	}
+}
+
+func TestParseRequirementIn_French(t *testing.T) {
+	r, err := ParseRequirementIn("fr", "Lorsque le bouton est pressé, l'application doit démarrer")
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if r.Shape != ShapeEvent || r.System != "application" || r.Trigger != "le bouton est pressé" {
+		t.Fatalf("got %+v", r)
+	}
+	r, err = ParseRequirementIn("fr", "Si le jeton est expiré, alors la passerelle ne doit pas transmettre la requête")
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if r.Shape != ShapeUnwanted || r.Polarity != PolarityNegative || r.Response != "transmettre la requête" {
+		t.Fatalf("got %+v", r)
+	}
+	r, err = ParseRequirementIn("fr", "Tant que le mode est armé, le système doit alerter")
+	if err != nil || r.Shape != ShapeState {
+		t.Fatalf("got %+v err=%v", r, err)
+	}
+}
+
+func TestParseRequirementIn_Errors(t *testing.T) {
+	if _, err := ParseRequirementIn("de", "Wenn der Knopf gedrückt wird, soll starten"); err == nil {
+		t.Fatalf("expected missing system error")
+	}
+	if _, err := ParseRequirementIn("de", "Das System soll speichern und muss melden"); err == nil {
+		t.Fatalf("expected multiple modal error")
+	}
+	if _, err := ParseRequirementIn("xx", "The system shall work"); err == nil {
+		t.Fatalf("expected unsupported language error")
+	}
+}
+
+func TestDetectLanguage(t *testing.T) {
+	if got := DetectLanguage("# Anforderungen\n<!-- ears-language: de -->\n- ..."); got != "de" {
+		t.Fatalf("got %q", got)
+	}
+	if got := DetectLanguage("# Requirements\n"); got != "" {
+		t.Fatalf("got %q", got)
+	}
+}
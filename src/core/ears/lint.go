@@ -4,6 +4,7 @@ import (
 	"errors"
 	"regexp"
 	"strings"
+	"unicode"
 
 	antlr "github.com/antlr4-go/antlr/v4"
 	earsp "github.com/kelvin/tgsflow/src/core/ears/gen/src/core/ears"
@@ -20,13 +21,24 @@ const (
 	ShapeUnwanted   Shape = "unwanted"
 )
 
+// Polarity distinguishes positive ("shall") from negative ("shall not") requirements.
+type Polarity string
+
+const (
+	PolarityPositive Polarity = "positive"
+	PolarityNegative Polarity = "negative"
+)
+
 // Result is the structured parse result for a requirement line.
 type Result struct {
-	Shape         Shape
-	System        string
-	Preconditions []string
-	Trigger       string
-	Response      string
+	Shape         Shape    `json:"shape"`
+	System        string   `json:"system"`
+	Preconditions []string `json:"preconditions,omitempty"`
+	Trigger       string   `json:"trigger,omitempty"`
+	Response      string   `json:"response"`
+	Polarity      Polarity `json:"polarity"`
+	// Bound is the measurable threshold extracted from Response, nil when none is stated
+	Bound *Bound `json:"bound,omitempty"`
 }
 
 // Issue represents a linting issue.
@@ -41,6 +53,15 @@ func Available() bool { return true }
 
 // ParseRequirement parses a single requirement line and returns a structured Result.
 func ParseRequirement(line string) (Result, error) {
+	res, err := parse(line)
+	if err != nil {
+		return Result{}, err
+	}
+	res.Bound = ExtractBound(res.Response, res.Polarity)
+	return res, nil
+}
+
+func parse(line string) (Result, error) {
 	input := antlr.NewInputStream(line)
 	lexer := earsp.NewearsLexer(input)
 	stream := antlr.NewCommonTokenStream(lexer, antlr.TokenDefaultChannel)
@@ -58,6 +79,10 @@ func ParseRequirement(line string) (Result, error) {
 	if parser.HasError() {
 		return Result{}, errors.New("syntax error")
 	}
+	// INCOSE singularity: one modal verb per requirement
+	if countModals(line) > 1 {
+		return Result{}, errors.New("multiple 'shall' in one requirement (split into singular requirements)")
+	}
 
 	res := Result{}
 	req := root.(*earsp.RequirementContext)
@@ -80,7 +105,7 @@ func ParseRequirement(line string) (Result, error) {
 		if err := ensureHasShall(line); err != nil {
 			return Result{}, err
 		}
-		res.Response = extractResponseText(ctx.Response())
+		res.Response, res.Polarity = extractResponse(line)
 		return res, nil
 	}
 	if ctx := req.EventReq(); ctx != nil {
@@ -100,7 +125,7 @@ func ParseRequirement(line string) (Result, error) {
 		if err := ensureHasShall(line); err != nil {
 			return Result{}, err
 		}
-		res.Response = extractResponseText(ctx.Response())
+		res.Response, res.Polarity = extractResponse(line)
 		return res, nil
 	}
 	if ctx := req.StateReq(); ctx != nil {
@@ -117,7 +142,7 @@ func ParseRequirement(line string) (Result, error) {
 		if err := ensureHasShall(line); err != nil {
 			return Result{}, err
 		}
-		res.Response = extractResponseText(ctx.Response())
+		res.Response, res.Polarity = extractResponse(line)
 		return res, nil
 	}
 	if ctx := req.UnwantedReq(); ctx != nil {
@@ -140,7 +165,7 @@ func ParseRequirement(line string) (Result, error) {
 		if err := ensureHasShall(line); err != nil {
 			return Result{}, err
 		}
-		res.Response = extractResponseText(ctx.Response())
+		res.Response, res.Polarity = extractResponse(line)
 		return res, nil
 	}
 	if ctx := req.UbiquitousReq(); ctx != nil {
@@ -156,7 +181,7 @@ func ParseRequirement(line string) (Result, error) {
 		if err := ensureHasShall(line); err != nil {
 			return Result{}, err
 		}
-		res.Response = extractResponseText(ctx.Response())
+		res.Response, res.Polarity = extractResponse(line)
 		return res, nil
 	}
 
@@ -179,16 +204,17 @@ func textFrom(ctx antlr.ParserRuleContext) string {
 	if ctx == nil {
 		return ""
 	}
-	return strings.TrimSpace(ctx.GetText())
+	// GetText concatenates tokens without the skipped whitespace; rebuild from words instead
+	return joinWords(wordsFromRule(ctx))
 }
 
-func extractSystemText(s earsp.ISystemContext) string { return textFrom(s) }
-func extractResponseText(r earsp.IResponseContext) string {
-	if r == nil {
-		return ""
-	}
-	return textFrom(r)
+func joinWords(words []string) string {
+	s := strings.Join(words, " ")
+	s = strings.ReplaceAll(s, " ,", ",")
+	return strings.TrimSpace(s)
 }
+
+func extractSystemText(s earsp.ISystemContext) string  { return textFrom(s) }
 func extractClauseText(c earsp.ITriggerContext) string { return textFrom(c) }
 
 func extractPreconditions(pc earsp.IPreconditionsContext) []string {
@@ -297,8 +323,48 @@ func validateSystemSegment(line string, nComma int) error {
 	return nil
 }
 
+// countModals returns how many standalone 'shall' words the line contains.
+func countModals(line string) int {
+	n := 0
+	for _, w := range splitWords(line) {
+		if strings.EqualFold(w, "shall") {
+			n++
+		}
+	}
+	return n
+}
+
+// extractResponse returns the raw text after the modal verb and its polarity.
+// The response is taken from the line rather than the parse tree so that
+// punctuation and pronouns inside the response are preserved verbatim.
+func extractResponse(line string) (string, Polarity) {
+	loc := modalRe.FindStringSubmatchIndex(line)
+	if loc == nil {
+		return "", PolarityPositive
+	}
+	rest := trimSeparators(line[loc[3]:])
+	if notRe.MatchString(rest) {
+		return trimSeparators(rest[len("not"):]), PolarityNegative
+	}
+	return rest, PolarityPositive
+}
+
+// modalRe and notRe match 'shall' and a following 'not' as whole words, bounded
+// by Unicode whitespace or punctuation ("shall\tnot", "shall, not").
+var (
+	modalRe = regexp.MustCompile(`(?i)(?:^|[\s\p{P}])(shall)(?:[\s\p{P}]|$)`)
+	notRe   = regexp.MustCompile(`(?i)^not(?:[\s\p{P}]|$)`)
+)
+
+// trimSeparators drops leading whitespace and clause punctuation so that
+// "shall, not log" yields the response "log" rather than ", not log".
+func trimSeparators(s string) string {
+	s = strings.TrimLeftFunc(s, func(r rune) bool { return unicode.IsSpace(r) || strings.ContainsRune(",;:.!?", r) })
+	return strings.TrimSpace(s)
+}
+
 func ensureHasShall(line string) error {
-	if !strings.Contains(strings.ToLower(line), " shall") {
+	if !modalRe.MatchString(line) {
 		return errors.New("missing shall")
 	}
 	return nil
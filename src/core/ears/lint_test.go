@@ -93,3 +93,82 @@ func TestLint_Batch(t *testing.T) {
 		t.Fatalf("expected issue at line 2, got %d", issues[0].Line)
 	}
 }
+
+func TestParse_Polarity(t *testing.T) {
+	r, err := ParseRequirement("The system shall record events")
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if r.Polarity != PolarityPositive {
+		t.Fatalf("expected positive polarity, got %v", r.Polarity)
+	}
+	r, err = ParseRequirement("If the token is expired, then the gateway shall not forward the request")
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if r.Polarity != PolarityNegative {
+		t.Fatalf("expected negative polarity, got %v", r.Polarity)
+	}
+	if r.Response != "forward the request" {
+		t.Fatalf("expected response without 'not', got %q", r.Response)
+	}
+}
+
+func TestExtractResponse_SeparatorsAroundModal(t *testing.T) {
+	cases := []struct {
+		line     string
+		response string
+		polarity Polarity
+	}{
+		{"The system shall\tnot log secrets", "log secrets", PolarityNegative},
+		{"The system shall, not log secrets", "log secrets", PolarityNegative},
+		{"The system\tshall\tlog events", "log events", PolarityPositive},
+		{"The system shall: log events", "log events", PolarityPositive},
+		{"The system shall notify users", "notify users", PolarityPositive},
+	}
+	for _, c := range cases {
+		resp, pol := extractResponse(c.line)
+		if resp != c.response || pol != c.polarity {
+			t.Fatalf("%q: response=%q polarity=%v", c.line, resp, pol)
+		}
+	}
+}
+
+func TestCountModals_Punctuation(t *testing.T) {
+	for line, want := range map[string]int{
+		"The system shall record events; it shall.":     2,
+		"The system shall,\tand the user shall (later)": 2,
+		"The marshall shall log":                        1,
+	} {
+		if got := countModals(line); got != want {
+			t.Fatalf("%q: got %d modals, want %d", line, got, want)
+		}
+	}
+}
+
+func TestParse_MultipleModals(t *testing.T) {
+	for _, s := range []string{
+		"The system shall record events and shall log them",
+		"When saved, the system shall persist data, and the system shall notify users",
+	} {
+		if _, err := ParseRequirement(s); err == nil {
+			t.Fatalf("expected error for multiple 'shall' in %q", s)
+		}
+	}
+}
+
+func TestExtracts_TextKeepsSpacing(t *testing.T) {
+	r, err := ParseRequirement("When user clicks save, the payment service shall create an invoice, then email it")
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if r.Trigger != "user clicks save" {
+		t.Fatalf("trigger=%q", r.Trigger)
+	}
+	if r.System != "payment service" {
+		t.Fatalf("system=%q", r.System)
+	}
+	if r.Response != "create an invoice, then email it" {
+		t.Fatalf("response=%q", r.Response)
+	}
+}
@@ -0,0 +1,212 @@
+package ears
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Comparator is the relation a Bound places on a measured quantity.
+type Comparator string
+
+const (
+	CompLE Comparator = "<="
+	CompLT Comparator = "<"
+	CompGE Comparator = ">="
+	CompGT Comparator = ">"
+	CompEQ Comparator = "="
+)
+
+// Bound is a measurable threshold stated in a response, e.g. "within 30 seconds"
+// becomes {Comparator: "<=", Value: 30, Unit: "s"}. V&V tooling can turn it into a
+// test threshold.
+type Bound struct {
+	Comparator Comparator `json:"comparator"`
+	Value      float64    `json:"value"`
+	Unit       string     `json:"unit"`
+	Text       string     `json:"text"` // matched phrase as written
+}
+
+// comparisonPhrases maps phrases to comparators; longer phrases are matched first.
+var comparisonPhrases = []struct {
+	phrase string
+	comp   Comparator
+}{
+	{"less than or equal to", CompLE},
+	{"greater than or equal to", CompGE},
+	{"no more than", CompLE},
+	{"not more than", CompLE},
+	{"no less than", CompGE},
+	{"not less than", CompGE},
+	{"no later than", CompLE},
+	{"a maximum of", CompLE},
+	{"a minimum of", CompGE},
+	{"at most", CompLE},
+	{"at least", CompGE},
+	{"up to", CompLE},
+	{"within", CompLE},
+	{"less than", CompLT},
+	{"fewer than", CompLT},
+	{"more than", CompGT},
+	{"greater than", CompGT},
+	{"under", CompLT},
+	{"below", CompLT},
+	{"above", CompGT},
+	{"over", CompGT},
+	{"exceeding", CompGT},
+	{"exceeds", CompGT},
+	{"exceed", CompGT},
+}
+
+// units maps accepted spellings to a canonical unit symbol.
+var units = map[string]string{
+	"ms": "ms", "millisecond": "ms", "milliseconds": "ms",
+	"s": "s", "sec": "s", "secs": "s", "second": "s", "seconds": "s",
+	"min": "min", "mins": "min", "minute": "min", "minutes": "min",
+	"h": "h", "hr": "h", "hrs": "h", "hour": "h", "hours": "h",
+	"day": "d", "days": "d",
+	"%": "%", "percent": "%",
+	"b": "B", "byte": "B", "bytes": "B",
+	"kb": "KB", "kib": "KB", "mb": "MB", "mib": "MB", "gb": "GB", "gib": "GB",
+	"token": "tokens", "tokens": "tokens",
+	"request": "requests", "requests": "requests",
+	"rps": "req/s", "qps": "req/s",
+	"user": "users", "users": "users",
+	"retry": "retries", "retries": "retries",
+	"attempt": "attempts", "attempts": "attempts",
+	"line": "lines", "lines": "lines",
+	"file": "files", "files": "files",
+}
+
+var boundRe = buildBoundRe(nil, nil)
+
+// buildBoundRe compiles the bound pattern for the English phrases and units plus
+// a profile's localized comparisons and units.
+func buildBoundRe(comparisons map[string]Comparator, extraUnits map[string]string) *regexp.Regexp {
+	phrases := make([]string, 0, len(comparisonPhrases)+len(comparisons))
+	for _, p := range comparisonPhrases {
+		phrases = append(phrases, regexp.QuoteMeta(p.phrase))
+	}
+	for p := range comparisons {
+		phrases = append(phrases, regexp.QuoteMeta(p))
+	}
+	// longest phrases first so "no more than" wins over "more than"
+	sort.SliceStable(phrases, func(i, j int) bool { return len(phrases[i]) > len(phrases[j]) })
+	unitAlts := make([]string, 0, len(units)+len(extraUnits))
+	for u := range units {
+		unitAlts = append(unitAlts, regexp.QuoteMeta(u))
+	}
+	for u := range extraUnits {
+		unitAlts = append(unitAlts, regexp.QuoteMeta(u))
+	}
+	// longest unit spellings first so "ms" wins over "m…" prefixes and "seconds" over "s"
+	sort.Slice(unitAlts, func(i, j int) bool { return len(unitAlts[i]) > len(unitAlts[j]) })
+	return regexp.MustCompile(`(?i)(?:\b(` + strings.Join(phrases, "|") + `)\s+)?(\d{1,3}(?:,\d{3})+(?:\.\d+)?|\d+(?:[.,]\d+)?)\s*(` + strings.Join(unitAlts, "|") + `)(?:\b|$|\s)(?:\s*(?:per|/)\s*(second|sec|s|minute|min)\b)?`)
+}
+
+// thousandsRe matches a number grouped with comma thousands separators ("10,000").
+var thousandsRe = regexp.MustCompile(`^\d{1,3}(?:,\d{3})+(?:\.\d+)?$`)
+
+// ExtractBound returns the first quantity with a unit in response, together with
+// its comparison phrase. A bare quantity is an exact (=) bound. For negative
+// requirements ("shall not exceed 500 ms") the comparator is inverted.
+func ExtractBound(response string, polarity Polarity) *Bound {
+	return (*Language)(nil).extractBound(response, polarity)
+}
+
+// extractBound implements ExtractBound for the profile l (nil for English),
+// which adds localized comparison phrases and units and may read "0,5" as 0.5.
+func (l *Language) extractBound(response string, polarity Polarity) *Bound {
+	re, decimalComma := boundRe, false
+	if l != nil {
+		re, decimalComma = l.boundRegexp(), l.DecimalComma
+	}
+	m := re.FindStringSubmatch(response)
+	if m == nil {
+		return nil
+	}
+	v, err := parseNumber(m[2], decimalComma)
+	if err != nil {
+		return nil
+	}
+	b := &Bound{Comparator: CompEQ, Value: v, Unit: l.unit(m[3]), Text: strings.TrimSpace(m[0])}
+	if m[4] != "" {
+		b.Unit += "/" + units[strings.ToLower(m[4])]
+	}
+	phrase := strings.ToLower(m[1])
+	if c, ok := l.comparison(phrase); ok {
+		b.Comparator = c
+	}
+	if polarity == PolarityNegative {
+		b.Comparator = invert(b.Comparator)
+	}
+	return b
+}
+
+// parseNumber strips comma thousands separators ("1,000"); any other comma is a
+// decimal comma when decimalComma is set and otherwise not a number.
+func parseNumber(s string, decimalComma bool) (float64, error) {
+	switch {
+	case thousandsRe.MatchString(s):
+		s = strings.ReplaceAll(s, ",", "")
+	case decimalComma:
+		s = strings.ReplaceAll(s, ",", ".")
+	}
+	return strconv.ParseFloat(s, 64)
+}
+
+// unit returns the canonical symbol for a unit spelling.
+func (l *Language) unit(s string) string {
+	s = strings.ToLower(s)
+	if l != nil {
+		if u, ok := l.Units[s]; ok {
+			return u
+		}
+	}
+	return units[s]
+}
+
+// comparison returns the comparator for a lower-cased phrase.
+func (l *Language) comparison(phrase string) (Comparator, bool) {
+	if l != nil {
+		if c, ok := l.Comparisons[phrase]; ok {
+			return c, true
+		}
+	}
+	for _, p := range comparisonPhrases {
+		if p.phrase == phrase {
+			return p.comp, true
+		}
+	}
+	return "", false
+}
+
+func invert(c Comparator) Comparator {
+	switch c {
+	case CompLE:
+		return CompGT
+	case CompLT:
+		return CompGE
+	case CompGE:
+		return CompLT
+	case CompGT:
+		return CompLE
+	}
+	return c
+}
+
+// vagueTermsRe matches performance or quality expectations that need a measurable bound.
+var vagueTermsRe = regexp.MustCompile(`(?i)\b(fast|faster|quickly|rapidly|promptly|responsive|timely|efficiently|immediately|instantly|real[- ]time|low latency|minimal latency|high throughput|scalable|performant|in a reasonable time|as soon as possible|minimi[sz]e (?:the )?(?:time|delay|latency|memory|cpu))\b`)
+
+// CheckMeasurable returns a message when a response expresses a performance or
+// non-functional expectation (e.g. "respond quickly") without a measurable bound.
+func CheckMeasurable(res Result) string {
+	if res.Bound != nil {
+		return ""
+	}
+	if m := vagueTermsRe.FindString(res.Response); m != "" {
+		return "non-functional requirement lacks a measurable bound (" + strings.ToLower(m) + "; state e.g. \"within 200 ms\")"
+	}
+	return ""
+}
@@ -0,0 +1,109 @@
+package ears
+
+import "testing"
+
+func TestExtractBound(t *testing.T) {
+	cases := []struct {
+		response string
+		polarity Polarity
+		comp     Comparator
+		value    float64
+		unit     string
+	}{
+		{"complete within 30 seconds", PolarityPositive, CompLE, 30, "s"},
+		{"respond in at most 200 ms", PolarityPositive, CompLE, 200, "ms"},
+		{"use no more than 512 MB of memory", PolarityPositive, CompLE, 512, "MB"},
+		{"sustain at least 100 requests per second", PolarityPositive, CompGE, 100, "requests/s"},
+		{"retry 3 times after 2 minutes", PolarityPositive, CompEQ, 2, "min"},
+		{"exceed 500 ms", PolarityNegative, CompLE, 500, "ms"},
+		{"maintain 99.9% availability", PolarityPositive, CompEQ, 99.9, "%"},
+	}
+	for _, c := range cases {
+		b := ExtractBound(c.response, c.polarity)
+		if b == nil {
+			t.Fatalf("%q: expected bound", c.response)
+		}
+		if b.Comparator != c.comp || b.Value != c.value || b.Unit != c.unit {
+			t.Fatalf("%q: got %+v", c.response, *b)
+		}
+	}
+	if b := ExtractBound("record events", PolarityPositive); b != nil {
+		t.Fatalf("expected no bound, got %+v", *b)
+	}
+}
+
+func TestExtractBound_Commas(t *testing.T) {
+	cases := []struct {
+		response string
+		lang     string  // "de" reads decimal commas
+		value    float64 // -1: no bound
+	}{
+		{"accept 1,000 requests", "en", 1000},
+		{"serve 10,000 users", "en", 10000},
+		{"serve 1,000,000 users", "en", 1000000},
+		{"serve 10,000 users", "de", 10000},
+		{"respond within 0,5 seconds", "de", 0.5},
+		{"respond within 2,25 seconds", "de", 2.25},
+		{"respond within 0,5 seconds", "en", -1},
+	}
+	for _, c := range cases {
+		l, _ := LookupLanguage(c.lang)
+		b := l.extractBound(c.response, PolarityPositive)
+		switch {
+		case c.value < 0 && b != nil:
+			t.Errorf("%q (%s): expected no bound, got %+v", c.response, c.lang, *b)
+		case c.value >= 0 && (b == nil || b.Value != c.value):
+			t.Errorf("%q (%s): got %+v, want %v", c.response, c.lang, b, c.value)
+		}
+	}
+}
+
+func TestParseRequirementIn_LocalizedComparisons(t *testing.T) {
+	cases := []struct {
+		lang, line string
+		want       Bound
+	}{
+		{"de", "Wenn eine Anfrage eingeht, soll das System innerhalb von 200 ms antworten", Bound{Comparator: CompLE, Value: 200, Unit: "ms"}},
+		{"de", "Das System soll mindestens 3 Sekunden warten", Bound{Comparator: CompGE, Value: 3, Unit: "s"}},
+		{"de", "Das System soll weniger als 0,5 Sekunden benötigen", Bound{Comparator: CompLT, Value: 0.5, Unit: "s"}},
+		{"fr", "Lorsqu'une requête arrive, le système doit répondre en moins de 2 secondes", Bound{Comparator: CompLE, Value: 2, Unit: "s"}},
+		{"fr", "Le système doit conserver au moins 30 jours de journaux", Bound{Comparator: CompGE, Value: 30, Unit: "d"}},
+	}
+	for _, c := range cases {
+		res, err := ParseRequirementIn(c.lang, c.line)
+		if err != nil {
+			t.Errorf("%s %q: %v", c.lang, c.line, err)
+			continue
+		}
+		if b := res.Bound; b == nil || b.Comparator != c.want.Comparator || b.Value != c.want.Value || b.Unit != c.want.Unit {
+			t.Errorf("%s %q: got bound %+v, want %s %v %s", c.lang, c.line, b, c.want.Comparator, c.want.Value, c.want.Unit)
+		}
+	}
+}
+
+func TestParse_ExposesBound(t *testing.T) {
+	r, err := ParseRequirement("When a brief is requested, the system shall complete within 30 seconds")
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if r.Bound == nil || r.Bound.Value != 30 || r.Bound.Unit != "s" {
+		t.Fatalf("expected 30 s bound, got %+v", r.Bound)
+	}
+}
+
+func TestCheckMeasurable(t *testing.T) {
+	r, err := ParseRequirement("When a query is received, the service shall respond quickly")
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if CheckMeasurable(r) == "" {
+		t.Fatalf("expected vague performance requirement to be flagged")
+	}
+	r, err = ParseRequirement("When a query is received, the service shall respond within 200 ms")
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if m := CheckMeasurable(r); m != "" {
+		t.Fatalf("expected bounded requirement to pass, got %q", m)
+	}
+}
@@ -0,0 +1,208 @@
+// Package jsonrpc implements the JSON-RPC 2.0 message envelope, the
+// Content-Length framing used by the Language Server Protocol and the
+// newline-delimited framing used by the Model Context Protocol's stdio transport.
+package jsonrpc
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Standard JSON-RPC error codes.
+const (
+	CodeParseError     = -32700
+	CodeInvalidRequest = -32600
+	CodeMethodNotFound = -32601
+	CodeInvalidParams  = -32602
+	CodeInternalError  = -32603
+)
+
+// Message is a request, notification or response. Requests carry ID and Method,
+// notifications only Method, responses ID and Result or Error.
+type Message struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+}
+
+// IsRequest reports whether m expects a response.
+func (m Message) IsRequest() bool { return m.Method != "" && len(m.ID) > 0 }
+
+// IsNotification reports whether m is a method call without an ID.
+func (m Message) IsNotification() bool { return m.Method != "" && len(m.ID) == 0 }
+
+// Error is a JSON-RPC error object.
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string { return fmt.Sprintf("jsonrpc error %d: %s", e.Code, e.Message) }
+
+// NewRequest builds a request with a numeric id.
+func NewRequest(id int, method string, params any) (Message, error) {
+	m := Message{JSONRPC: "2.0", ID: json.RawMessage(strconv.Itoa(id)), Method: method}
+	return m, m.setParams(params)
+}
+
+// NewNotification builds a notification.
+func NewNotification(method string, params any) (Message, error) {
+	m := Message{JSONRPC: "2.0", Method: method}
+	return m, m.setParams(params)
+}
+
+// NewResponse builds a successful response to the request with id.
+func NewResponse(id json.RawMessage, result any) (Message, error) {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return Message{}, err
+	}
+	return Message{JSONRPC: "2.0", ID: id, Result: data}, nil
+}
+
+// NewErrorResponse builds an error response to the request with id.
+func NewErrorResponse(id json.RawMessage, code int, msg string) Message {
+	return Message{JSONRPC: "2.0", ID: id, Error: &Error{Code: code, Message: msg}}
+}
+
+func (m *Message) setParams(params any) error {
+	if params == nil {
+		return nil
+	}
+	data, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+	m.Params = data
+	return nil
+}
+
+// Conn is a framed, bidirectional message stream.
+type Conn interface {
+	Read() (Message, error)
+	Write(Message) error
+}
+
+// HeaderConn reads and writes messages framed with a Content-Length header,
+// as used by LSP. Writes are serialised so handlers may reply concurrently.
+type HeaderConn struct {
+	r  *bufio.Reader
+	w  io.Writer
+	mu sync.Mutex
+}
+
+// NewHeaderConn wraps a reader/writer pair, e.g. os.Stdin and os.Stdout.
+func NewHeaderConn(r io.Reader, w io.Writer) *HeaderConn {
+	return &HeaderConn{r: bufio.NewReader(r), w: w}
+}
+
+// Read returns the next message. io.EOF is returned when the peer closes the stream.
+func (c *HeaderConn) Read() (Message, error) {
+	length := -1
+	for {
+		line, err := c.r.ReadString('\n')
+		if err != nil {
+			if err == io.EOF && line == "" {
+				return Message{}, io.EOF
+			}
+			return Message{}, err
+		}
+		line = strings.TrimRight(line, "\r\n")
+		if line == "" {
+			break
+		}
+		name, value, ok := strings.Cut(line, ":")
+		if ok && strings.EqualFold(strings.TrimSpace(name), "Content-Length") {
+			n, err := strconv.Atoi(strings.TrimSpace(value))
+			if err != nil {
+				return Message{}, fmt.Errorf("invalid Content-Length %q", value)
+			}
+			length = n
+		}
+	}
+	if length < 0 {
+		return Message{}, fmt.Errorf("missing Content-Length header")
+	}
+	body := make([]byte, length)
+	if _, err := io.ReadFull(c.r, body); err != nil {
+		return Message{}, err
+	}
+	var m Message
+	if err := json.Unmarshal(body, &m); err != nil {
+		return Message{}, &Error{Code: CodeParseError, Message: err.Error()}
+	}
+	return m, nil
+}
+
+// Write sends one message.
+func (c *HeaderConn) Write(m Message) error {
+	if m.JSONRPC == "" {
+		m.JSONRPC = "2.0"
+	}
+	data, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, err := fmt.Fprintf(c.w, "Content-Length: %d\r\n\r\n", len(data)); err != nil {
+		return err
+	}
+	_, err = c.w.Write(data)
+	return err
+}
+
+// LineConn reads and writes one JSON message per line, as used by MCP over
+// stdio. Blank lines are skipped; writes are serialised.
+type LineConn struct {
+	r  *bufio.Reader
+	w  io.Writer
+	mu sync.Mutex
+}
+
+// NewLineConn wraps a reader/writer pair, e.g. a child process's stdout and stdin.
+func NewLineConn(r io.Reader, w io.Writer) *LineConn {
+	return &LineConn{r: bufio.NewReader(r), w: w}
+}
+
+// Read returns the next message. io.EOF is returned when the peer closes the stream.
+func (c *LineConn) Read() (Message, error) {
+	for {
+		line, err := c.r.ReadBytes('\n')
+		if len(bytes.TrimSpace(line)) == 0 {
+			if err != nil {
+				return Message{}, err
+			}
+			continue
+		}
+		var m Message
+		if jerr := json.Unmarshal(line, &m); jerr != nil {
+			return Message{}, &Error{Code: CodeParseError, Message: jerr.Error()}
+		}
+		return m, nil
+	}
+}
+
+// Write sends one message followed by a newline.
+func (c *LineConn) Write(m Message) error {
+	if m.JSONRPC == "" {
+		m.JSONRPC = "2.0"
+	}
+	data, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, err = c.w.Write(append(data, '\n'))
+	return err
+}
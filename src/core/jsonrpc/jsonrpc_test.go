@@ -0,0 +1,87 @@
+package jsonrpc
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestHeaderConn_RoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewHeaderConn(nil, &buf)
+	req, err := NewRequest(1, "initialize", map[string]any{"rootUri": "file:///tmp"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Write(req); err != nil {
+		t.Fatal(err)
+	}
+	note, _ := NewNotification("initialized", nil)
+	if err := w.Write(note); err != nil {
+		t.Fatal(err)
+	}
+
+	r := NewHeaderConn(&buf, nil)
+	got, err := r.Read()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.IsRequest() || got.Method != "initialize" || string(got.ID) != "1" {
+		t.Fatalf("unexpected request: %+v", got)
+	}
+	got, err = r.Read()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.IsNotification() || got.Method != "initialized" {
+		t.Fatalf("unexpected notification: %+v", got)
+	}
+	if _, err := r.Read(); err != io.EOF {
+		t.Fatalf("expected EOF, got %v", err)
+	}
+}
+
+func TestLineConn_RoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewLineConn(nil, &buf)
+	req, _ := NewRequest(7, "tools/list", nil)
+	if err := w.Write(req); err != nil {
+		t.Fatal(err)
+	}
+	resp, _ := NewResponse(req.ID, map[string]any{"text": "line one\nline two"})
+	if err := w.Write(resp); err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(buf.String(), "\n"); n != 2 {
+		t.Fatalf("expected one line per message, got %d newlines in %q", n, buf.String())
+	}
+	buf.WriteString("\n") // blank lines between messages are tolerated
+
+	r := NewLineConn(&buf, nil)
+	got, err := r.Read()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.IsRequest() || got.Method != "tools/list" || string(got.ID) != "7" {
+		t.Fatalf("unexpected request: %+v", got)
+	}
+	got, err = r.Read()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got.ID) != "7" || !strings.Contains(string(got.Result), `line one\nline two`) {
+		t.Fatalf("unexpected response: %+v", got)
+	}
+	if _, err := r.Read(); err != io.EOF {
+		t.Fatalf("expected EOF, got %v", err)
+	}
+}
+
+func TestLineConn_ParseError(t *testing.T) {
+	r := NewLineConn(strings.NewReader("{not json}\n"), nil)
+	_, err := r.Read()
+	if rpcErr, ok := err.(*Error); !ok || rpcErr.Code != CodeParseError {
+		t.Fatalf("expected parse error, got %v", err)
+	}
+}
@@ -0,0 +1,479 @@
+// Package lsp implements a Language Server Protocol server that lints EARS
+// requirements in the Markdown documents configured under guardrails.ears.paths.
+package lsp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/kelvin/tgsflow/src/core/config"
+	"github.com/kelvin/tgsflow/src/core/ears"
+	"github.com/kelvin/tgsflow/src/core/jsonrpc"
+	"github.com/kelvin/tgsflow/src/core/reqs"
+)
+
+// ErrExitWithoutShutdown is returned by Serve when the client sends "exit"
+// before "shutdown"; LSP asks servers to exit with code 1 in that case.
+var ErrExitWithoutShutdown = errors.New("exit received before shutdown")
+
+var idRe = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9]*-[0-9][A-Za-z0-9.]*$`)
+
+// Server holds the workspace configuration and the open documents.
+type Server struct {
+	repoRoot string
+	rules    reqs.Rules
+	lang     string
+	paths    []string        // configured requirement documents, repo-relative
+	managed  map[string]bool // same set for lookup
+	docs     map[string]string
+	conn     *jsonrpc.HeaderConn
+	shutdown bool
+	// Logf receives diagnostics about the server itself; defaults to stderr.
+	Logf func(format string, args ...any)
+}
+
+// NewServer creates a server for repoRoot; configuration is (re)loaded on initialize.
+func NewServer(repoRoot string) *Server {
+	return &Server{
+		repoRoot: repoRoot,
+		docs:     make(map[string]string),
+		Logf: func(format string, args ...any) {
+			fmt.Fprintf(os.Stderr, "tgs lsp: "+format+"\n", args...)
+		},
+	}
+}
+
+// Serve processes messages from r and writes replies to w until the client exits
+// or closes the stream. A malformed message body is answered with a parse error
+// and skipped; only framing and I/O errors end the session.
+func (s *Server) Serve(r io.Reader, w io.Writer) error {
+	s.conn = jsonrpc.NewHeaderConn(r, w)
+	s.configure()
+	for {
+		msg, err := s.conn.Read()
+		if err != nil {
+			var rpcErr *jsonrpc.Error
+			if errors.As(err, &rpcErr) {
+				s.Logf("%s", rpcErr.Message)
+				if werr := s.conn.Write(jsonrpc.NewErrorResponse(json.RawMessage("null"), rpcErr.Code, rpcErr.Message)); werr != nil {
+					return werr
+				}
+				continue
+			}
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+		if msg.Method == "exit" {
+			if !s.shutdown {
+				return ErrExitWithoutShutdown
+			}
+			return nil
+		}
+		result, rpcErr := s.handle(msg)
+		if !msg.IsRequest() {
+			if rpcErr != nil {
+				s.Logf("%s: %s", msg.Method, rpcErr.Message)
+			}
+			continue
+		}
+		var reply jsonrpc.Message
+		if rpcErr != nil {
+			reply = jsonrpc.NewErrorResponse(msg.ID, rpcErr.Code, rpcErr.Message)
+		} else if reply, err = jsonrpc.NewResponse(msg.ID, result); err != nil {
+			reply = jsonrpc.NewErrorResponse(msg.ID, jsonrpc.CodeInternalError, err.Error())
+		}
+		if err := s.conn.Write(reply); err != nil {
+			return err
+		}
+	}
+}
+
+// configure loads tgs.yml, the glossary and the document set for the current root.
+func (s *Server) configure() {
+	cfg, err := config.Load(s.repoRoot)
+	if err != nil {
+		s.Logf("failed to load config: %v", err)
+	}
+	s.rules, err = reqs.LoadRules(s.repoRoot, cfg.Guardrails.EARS)
+	if err != nil {
+		s.Logf("glossary: %v", err)
+	}
+	s.lang = reqs.DefaultLanguage(cfg)
+	s.paths = reqs.DefaultPaths(cfg)
+	s.managed = make(map[string]bool, len(s.paths))
+	for _, p := range s.paths {
+		s.managed[filepath.ToSlash(filepath.Clean(p))] = true
+	}
+}
+
+func (s *Server) handle(msg jsonrpc.Message) (any, *jsonrpc.Error) {
+	switch msg.Method {
+	case "initialize":
+		var p InitializeParams
+		if err := decode(msg.Params, &p); err != nil {
+			return nil, err
+		}
+		if root := uriToPath(p.RootURI); root != "" {
+			s.repoRoot = root
+		} else if p.RootPath != "" {
+			s.repoRoot = p.RootPath
+		}
+		s.configure()
+		return InitializeResult{
+			Capabilities: ServerCapabilities{
+				TextDocumentSync:   1,
+				HoverProvider:      true,
+				DefinitionProvider: true,
+				CodeActionProvider: true,
+				CompletionProvider: &CompletionOptions{TriggerCharacters: []string{"-"}},
+			},
+			ServerInfo: ServerInfo{Name: "tgs-ears"},
+		}, nil
+	case "initialized", "$/cancelRequest", "$/setTrace", "textDocument/didSave", "workspace/didChangeConfiguration":
+		return nil, nil
+	case "shutdown":
+		s.shutdown = true
+		return nil, nil
+	case "textDocument/didOpen":
+		var p DidOpenTextDocumentParams
+		if err := decode(msg.Params, &p); err != nil {
+			return nil, err
+		}
+		s.docs[p.TextDocument.URI] = p.TextDocument.Text
+		return nil, s.publish(p.TextDocument.URI)
+	case "textDocument/didChange":
+		var p DidChangeTextDocumentParams
+		if err := decode(msg.Params, &p); err != nil {
+			return nil, err
+		}
+		if n := len(p.ContentChanges); n > 0 {
+			s.docs[p.TextDocument.URI] = p.ContentChanges[n-1].Text
+		}
+		return nil, s.publish(p.TextDocument.URI)
+	case "textDocument/didClose":
+		var p DidCloseTextDocumentParams
+		if err := decode(msg.Params, &p); err != nil {
+			return nil, err
+		}
+		delete(s.docs, p.TextDocument.URI)
+		return nil, s.notify("textDocument/publishDiagnostics", PublishDiagnosticsParams{URI: p.TextDocument.URI, Diagnostics: []Diagnostic{}})
+	case "textDocument/hover":
+		var p TextDocumentPositionParams
+		if err := decode(msg.Params, &p); err != nil {
+			return nil, err
+		}
+		return s.hover(p), nil
+	case "textDocument/definition":
+		var p TextDocumentPositionParams
+		if err := decode(msg.Params, &p); err != nil {
+			return nil, err
+		}
+		return s.definition(p), nil
+	case "textDocument/completion":
+		var p TextDocumentPositionParams
+		if err := decode(msg.Params, &p); err != nil {
+			return nil, err
+		}
+		return s.completion(), nil
+	case "textDocument/codeAction":
+		var p CodeActionParams
+		if err := decode(msg.Params, &p); err != nil {
+			return nil, err
+		}
+		return s.codeActions(p), nil
+	}
+	if msg.IsRequest() {
+		return nil, &jsonrpc.Error{Code: jsonrpc.CodeMethodNotFound, Message: "method not found: " + msg.Method}
+	}
+	return nil, nil
+}
+
+func decode(raw json.RawMessage, v any) *jsonrpc.Error {
+	if len(raw) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		return &jsonrpc.Error{Code: jsonrpc.CodeInvalidParams, Message: err.Error()}
+	}
+	return nil
+}
+
+func (s *Server) notify(method string, params any) *jsonrpc.Error {
+	m, err := jsonrpc.NewNotification(method, params)
+	if err == nil {
+		err = s.conn.Write(m)
+	}
+	if err != nil {
+		return &jsonrpc.Error{Code: jsonrpc.CodeInternalError, Message: err.Error()}
+	}
+	return nil
+}
+
+// publish sends diagnostics for an open document; documents outside the
+// configured EARS paths get an empty list so stale markers are cleared.
+func (s *Server) publish(uri string) *jsonrpc.Error {
+	diags := []Diagnostic{}
+	text := s.docs[uri]
+	lines := strings.Split(text, "\n")
+	for _, r := range s.scan(uri, text) {
+		for _, m := range s.rules.CheckRequirement(r) {
+			diags = append(diags, Diagnostic{Range: lineRange(lines, r.Line-1), Severity: SeverityError, Source: "tgs-ears", Message: m})
+		}
+	}
+	return s.notify("textDocument/publishDiagnostics", PublishDiagnosticsParams{URI: uri, Diagnostics: diags})
+}
+
+// scan returns the requirements of a managed document, nil otherwise.
+func (s *Server) scan(uri, text string) []reqs.Requirement {
+	rel, ok := s.relPath(uri)
+	if !ok || !s.managed[rel] {
+		return nil
+	}
+	return reqs.ScanWith(&ears.Linter{Workers: 1, Names: s.rules.Glossary.SystemNames()}, rel, text, reqs.IsRequirementsDoc(rel), s.lang)
+}
+
+// items returns every ID'd bullet (needs, requirements, constraints, ...) of
+// the configured documents, preferring unsaved editor contents.
+func (s *Server) items() []reqs.Item {
+	var out []reqs.Item
+	for _, rel := range s.paths {
+		uri := pathToURI(filepath.Join(s.repoRoot, rel))
+		text, ok := s.docs[uri]
+		if !ok {
+			data, err := os.ReadFile(filepath.Join(s.repoRoot, rel))
+			if err != nil {
+				continue
+			}
+			text = string(data)
+		}
+		out = append(out, reqs.ScanItems(rel, text)...)
+	}
+	return out
+}
+
+func (s *Server) hover(p TextDocumentPositionParams) *Hover {
+	text, ok := s.docs[p.TextDocument.URI]
+	if !ok {
+		return nil
+	}
+	lines := strings.Split(text, "\n")
+	if p.Position.Line < 0 || p.Position.Line >= len(lines) {
+		return nil
+	}
+	// Hovering an ID that is defined on another line shows that item
+	if word := wordAt(lines[p.Position.Line], p.Position.Character); idRe.MatchString(word) {
+		for _, r := range s.items() {
+			if r.ID == word && !(s.isURI(p.TextDocument.URI, r.Path) && r.Line == p.Position.Line+1) {
+				return &Hover{Contents: MarkupContent{Kind: "markdown", Value: fmt.Sprintf("**%s** (%s:%d)\n\n%s", r.ID, r.Path, r.Line, r.Text)}}
+			}
+		}
+	}
+	for _, r := range s.scan(p.TextDocument.URI, text) {
+		if r.Line != p.Position.Line+1 {
+			continue
+		}
+		rng := lineRange(lines, p.Position.Line)
+		return &Hover{Contents: MarkupContent{Kind: "markdown", Value: describe(r)}, Range: &rng}
+	}
+	return nil
+}
+
+// describe renders the parsed structure of a requirement for hover.
+func describe(r reqs.Requirement) string {
+	var b strings.Builder
+	if r.Err != nil {
+		fmt.Fprintf(&b, "**EARS**: invalid — %s", r.Err)
+		return b.String()
+	}
+	res := r.Result
+	fmt.Fprintf(&b, "**EARS %s**", res.Shape)
+	if res.Polarity == ears.PolarityNegative {
+		b.WriteString(" (negative)")
+	}
+	fmt.Fprintf(&b, "\n\n- System: %s", res.System)
+	if len(res.Preconditions) > 0 {
+		fmt.Fprintf(&b, "\n- Preconditions: %s", strings.Join(res.Preconditions, "; "))
+	}
+	if res.Trigger != "" {
+		fmt.Fprintf(&b, "\n- Trigger: %s", res.Trigger)
+	}
+	fmt.Fprintf(&b, "\n- Response: %s", res.Response)
+	if res.Bound != nil {
+		fmt.Fprintf(&b, "\n- Bound: %s %g %s", res.Bound.Comparator, res.Bound.Value, res.Bound.Unit)
+	}
+	if r.Verification != "" {
+		fmt.Fprintf(&b, "\n- Verification: %s", r.Verification)
+	}
+	return b.String()
+}
+
+func (s *Server) definition(p TextDocumentPositionParams) []Location {
+	text, ok := s.docs[p.TextDocument.URI]
+	if !ok {
+		return nil
+	}
+	lines := strings.Split(text, "\n")
+	if p.Position.Line < 0 || p.Position.Line >= len(lines) {
+		return nil
+	}
+	word := wordAt(lines[p.Position.Line], p.Position.Character)
+	if !idRe.MatchString(word) {
+		return nil
+	}
+	for _, r := range s.items() {
+		if r.ID != word {
+			continue
+		}
+		return []Location{{URI: pathToURI(filepath.Join(s.repoRoot, r.Path)), Range: Range{Start: Position{Line: r.Line - 1}, End: Position{Line: r.Line - 1}}}}
+	}
+	return []Location{}
+}
+
+// completion offers item IDs and glossary terms; clients filter by prefix.
+func (s *Server) completion() []CompletionItem {
+	items := []CompletionItem{}
+	seen := map[string]bool{}
+	for _, r := range s.items() {
+		if seen[r.ID] {
+			continue
+		}
+		seen[r.ID] = true
+		items = append(items, CompletionItem{Label: r.ID, Kind: CompletionKindReference, Detail: r.Text})
+	}
+	if g := s.rules.Glossary; g != nil {
+		for _, set := range []struct {
+			kind  string
+			terms []ears.Term
+		}{{"system", g.Systems}, {"component", g.Components}, {"state", g.States}, {"event", g.Events}} {
+			for _, t := range set.terms {
+				if t.Deprecated || t.ReplacedBy != "" {
+					continue
+				}
+				items = append(items, CompletionItem{Label: t.Name, Kind: CompletionKindKeyword, Detail: "glossary " + set.kind})
+			}
+		}
+	}
+	// IDs first, then glossary terms, each alphabetically
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i].Kind != items[j].Kind {
+			return items[i].Kind > items[j].Kind
+		}
+		return items[i].Label < items[j].Label
+	})
+	return items
+}
+
+// codeActions offers the canonical EARS formatting for requirements in range.
+func (s *Server) codeActions(p CodeActionParams) []CodeAction {
+	actions := []CodeAction{}
+	text, ok := s.docs[p.TextDocument.URI]
+	if !ok {
+		return actions
+	}
+	lines := strings.Split(text, "\n")
+	for _, r := range s.scan(p.TextDocument.URI, text) {
+		ln := r.Line - 1
+		if ln < p.Range.Start.Line || ln > p.Range.End.Line || !r.Valid() || r.Language != "en" {
+			continue
+		}
+		formatted, err := ears.Format(r.Text)
+		if err != nil || formatted == r.Text {
+			continue
+		}
+		col := strings.Index(lines[ln], r.Text)
+		if col < 0 {
+			continue
+		}
+		start := utf16Len(lines[ln][:col])
+		edit := TextEdit{
+			Range:   Range{Start: Position{Line: ln, Character: start}, End: Position{Line: ln, Character: start + utf16Len(r.Text)}},
+			NewText: formatted,
+		}
+		actions = append(actions, CodeAction{
+			Title: "Format as canonical EARS",
+			Kind:  "quickfix",
+			Edit:  WorkspaceEdit{Changes: map[string][]TextEdit{p.TextDocument.URI: {edit}}},
+		})
+	}
+	return actions
+}
+
+func (s *Server) relPath(uri string) (string, bool) {
+	path := uriToPath(uri)
+	if path == "" {
+		return "", false
+	}
+	root, err := filepath.Abs(s.repoRoot)
+	if err != nil {
+		return "", false
+	}
+	rel, err := filepath.Rel(root, path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return "", false
+	}
+	return filepath.ToSlash(rel), true
+}
+
+func (s *Server) isURI(uri, rel string) bool {
+	got, ok := s.relPath(uri)
+	return ok && got == rel
+}
+
+func uriToPath(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "file" {
+		return ""
+	}
+	return filepath.FromSlash(u.Path)
+}
+
+func pathToURI(path string) string {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		abs = path
+	}
+	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(abs)}).String()
+}
+
+func lineRange(lines []string, line int) Range {
+	end := 0
+	if line >= 0 && line < len(lines) {
+		end = utf16Len(strings.TrimRight(lines[line], "\r"))
+	}
+	return Range{Start: Position{Line: line}, End: Position{Line: line, Character: end}}
+}
+
+func utf16Len(s string) int { return len(utf16.Encode([]rune(s))) }
+
+// wordAt returns the ID-like word around a UTF-16 column.
+func wordAt(line string, col int) string {
+	off, units := 0, 0
+	for off < len(line) && units < col {
+		r, size := utf8.DecodeRuneInString(line[off:])
+		units += len(utf16.Encode([]rune{r}))
+		off += size
+	}
+	isWord := func(b byte) bool {
+		return b == '-' || b == '.' || b == '_' || b >= '0' && b <= '9' || b >= 'A' && b <= 'Z' || b >= 'a' && b <= 'z'
+	}
+	start, end := off, off
+	for start > 0 && isWord(line[start-1]) {
+		start--
+	}
+	for end < len(line) && isWord(line[end]) {
+		end++
+	}
+	return strings.Trim(line[start:end], ".-")
+}
@@ -0,0 +1,252 @@
+package lsp
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kelvin/tgsflow/src/core/jsonrpc"
+)
+
+// client is a minimal in-process LSP client speaking to a Server over pipes.
+type client struct {
+	t      *testing.T
+	conn   *jsonrpc.HeaderConn
+	raw    io.Writer // client -> server stream, for malformed input
+	nextID int
+	notes  []jsonrpc.Message
+	done   chan error
+}
+
+func startServer(t *testing.T, root string) *client {
+	t.Helper()
+	cr, sw := io.Pipe() // server -> client
+	sr, cw := io.Pipe() // client -> server
+	srv := NewServer(root)
+	srv.Logf = func(string, ...any) {}
+	c := &client{t: t, conn: jsonrpc.NewHeaderConn(cr, cw), raw: cw, done: make(chan error, 1)}
+	go func() {
+		err := srv.Serve(sr, sw)
+		sw.Close()
+		c.done <- err
+	}()
+	t.Cleanup(func() { cw.Close() })
+	return c
+}
+
+func (c *client) call(method string, params, result any) {
+	c.t.Helper()
+	c.nextID++
+	req, err := jsonrpc.NewRequest(c.nextID, method, params)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	if err := c.conn.Write(req); err != nil {
+		c.t.Fatal(err)
+	}
+	for {
+		msg, err := c.conn.Read()
+		if err != nil {
+			c.t.Fatalf("%s: %v", method, err)
+		}
+		if msg.IsNotification() {
+			c.notes = append(c.notes, msg)
+			continue
+		}
+		if msg.Error != nil {
+			c.t.Fatalf("%s: %v", method, msg.Error)
+		}
+		if result != nil {
+			if err := json.Unmarshal(msg.Result, result); err != nil {
+				c.t.Fatalf("%s: decode: %v", method, err)
+			}
+		}
+		return
+	}
+}
+
+func (c *client) notify(method string, params any) {
+	c.t.Helper()
+	m, err := jsonrpc.NewNotification(method, params)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	if err := c.conn.Write(m); err != nil {
+		c.t.Fatal(err)
+	}
+}
+
+// diagnostics reads until the next publishDiagnostics notification.
+func (c *client) diagnostics() PublishDiagnosticsParams {
+	c.t.Helper()
+	for {
+		var msg jsonrpc.Message
+		if len(c.notes) > 0 {
+			msg, c.notes = c.notes[0], c.notes[1:]
+		} else {
+			var err error
+			if msg, err = c.conn.Read(); err != nil {
+				c.t.Fatalf("diagnostics: %v", err)
+			}
+		}
+		if msg.Method != "textDocument/publishDiagnostics" {
+			continue
+		}
+		var p PublishDiagnosticsParams
+		if err := json.Unmarshal(msg.Params, &p); err != nil {
+			c.t.Fatal(err)
+		}
+		return p
+	}
+}
+
+const reqDoc = `# System Requirements
+
+- **SR-001**: WHEN a brief is requested , THE system SHALL collect design docs. (Verification: Test)
+- **SR-002**: When verifying, the system shall report results referenced by SR-001 and N-001. (Verification: Test)
+- **SR-003**: When verifying, shall report.
+`
+
+func setupRepo(t *testing.T) (string, string) {
+	t.Helper()
+	dir := t.TempDir()
+	write := func(rel, content string) {
+		path := filepath.Join(dir, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("tgs/design/10_needs.md", "# Needs\n\n- **N-001**: The operator needs design docs collected into a brief.\n")
+	write("tgs/design/20_requirements.md", reqDoc)
+	write("tgs/design/glossary.yml", "systems:\n  - name: system\nstates:\n  - name: CI mode\n")
+	return dir, pathToURI(filepath.Join(dir, "tgs/design/20_requirements.md"))
+}
+
+func TestServer_DiagnosticsHoverDefinitionCompletionActions(t *testing.T) {
+	root, uri := setupRepo(t)
+	c := startServer(t, root)
+
+	var init InitializeResult
+	c.call("initialize", InitializeParams{RootURI: pathToURI(root)}, &init)
+	if !init.Capabilities.HoverProvider || init.Capabilities.TextDocumentSync != 1 {
+		t.Fatalf("unexpected capabilities: %+v", init.Capabilities)
+	}
+	c.notify("initialized", struct{}{})
+
+	c.notify("textDocument/didOpen", DidOpenTextDocumentParams{TextDocument: TextDocumentItem{URI: uri, LanguageID: "markdown", Version: 1, Text: reqDoc}})
+	diags := c.diagnostics()
+	if len(diags.Diagnostics) != 1 || diags.Diagnostics[0].Range.Start.Line != 4 || !strings.Contains(diags.Diagnostics[0].Message, "missing system name") {
+		t.Fatalf("unexpected diagnostics: %+v", diags)
+	}
+
+	// Fixing the line clears the diagnostic
+	fixed := strings.Replace(reqDoc, "When verifying, shall report.", "When verifying, the system shall report.", 1)
+	c.notify("textDocument/didChange", DidChangeTextDocumentParams{TextDocument: TextDocumentIdentifier{URI: uri}, ContentChanges: []TextDocumentContentChangeEvent{{Text: fixed}}})
+	if d := c.diagnostics(); len(d.Diagnostics) != 0 {
+		t.Fatalf("expected diagnostics cleared, got %+v", d)
+	}
+
+	var hover Hover
+	c.call("textDocument/hover", TextDocumentPositionParams{TextDocument: TextDocumentIdentifier{URI: uri}, Position: Position{Line: 2, Character: 40}}, &hover)
+	for _, want := range []string{"event-driven", "System: system", "Trigger: a brief is requested"} {
+		if !strings.Contains(hover.Contents.Value, want) {
+			t.Fatalf("hover missing %q: %q", want, hover.Contents.Value)
+		}
+	}
+
+	// SR-001 referenced on line 3 resolves to its definition on line 2
+	line3 := strings.Split(fixed, "\n")[3]
+	col := strings.LastIndex(line3, "SR-001") + 2
+	var locs []Location
+	c.call("textDocument/definition", TextDocumentPositionParams{TextDocument: TextDocumentIdentifier{URI: uri}, Position: Position{Line: 3, Character: col}}, &locs)
+	if len(locs) != 1 || locs[0].URI != uri || locs[0].Range.Start.Line != 2 {
+		t.Fatalf("unexpected definition: %+v", locs)
+	}
+
+	// Need IDs resolve too, not only EARS requirements
+	col = strings.Index(line3, "N-001") + 1
+	c.call("textDocument/definition", TextDocumentPositionParams{TextDocument: TextDocumentIdentifier{URI: uri}, Position: Position{Line: 3, Character: col}}, &locs)
+	needs := pathToURI(filepath.Join(root, "tgs/design/10_needs.md"))
+	if len(locs) != 1 || locs[0].URI != needs || locs[0].Range.Start.Line != 2 {
+		t.Fatalf("unexpected need definition: %+v", locs)
+	}
+	c.call("textDocument/hover", TextDocumentPositionParams{TextDocument: TextDocumentIdentifier{URI: uri}, Position: Position{Line: 3, Character: col}}, &hover)
+	if !strings.Contains(hover.Contents.Value, "**N-001** (tgs/design/10_needs.md:3)") {
+		t.Fatalf("unexpected need hover: %q", hover.Contents.Value)
+	}
+
+	var items []CompletionItem
+	c.call("textDocument/completion", TextDocumentPositionParams{TextDocument: TextDocumentIdentifier{URI: uri}, Position: Position{Line: 3, Character: 0}}, &items)
+	labels := map[string]bool{}
+	for _, it := range items {
+		labels[it.Label] = true
+	}
+	for _, want := range []string{"N-001", "SR-001", "SR-003", "system", "CI mode"} {
+		if !labels[want] {
+			t.Fatalf("completion missing %q: %+v", want, items)
+		}
+	}
+
+	var actions []CodeAction
+	c.call("textDocument/codeAction", CodeActionParams{TextDocument: TextDocumentIdentifier{URI: uri}, Range: Range{Start: Position{Line: 0}, End: Position{Line: 10}}}, &actions)
+	if len(actions) != 1 {
+		t.Fatalf("expected 1 formatting action, got %+v", actions)
+	}
+	edit := actions[0].Edit.Changes[uri][0]
+	if edit.NewText != "When a brief is requested, the system shall collect design docs" || edit.Range.Start.Line != 2 {
+		t.Fatalf("unexpected edit: %+v", edit)
+	}
+
+	c.call("shutdown", nil, nil)
+	c.notify("exit", nil)
+	if err := <-c.done; err != nil {
+		t.Fatalf("serve: %v", err)
+	}
+}
+
+func TestServer_IgnoresUnconfiguredDocs(t *testing.T) {
+	root, _ := setupRepo(t)
+	c := startServer(t, root)
+	c.call("initialize", InitializeParams{RootURI: pathToURI(root)}, nil)
+	other := pathToURI(filepath.Join(root, "notes.md"))
+	c.notify("textDocument/didOpen", DidOpenTextDocumentParams{TextDocument: TextDocumentItem{URI: other, Text: "When verifying, shall report.\n"}})
+	if d := c.diagnostics(); d.URI != other || len(d.Diagnostics) != 0 {
+		t.Fatalf("expected no diagnostics for unconfigured doc, got %+v", d)
+	}
+	c.notify("exit", nil)
+	if err := <-c.done; err != ErrExitWithoutShutdown {
+		t.Fatalf("expected ErrExitWithoutShutdown, got %v", err)
+	}
+}
+
+func TestServer_SurvivesMalformedMessage(t *testing.T) {
+	root, _ := setupRepo(t)
+	c := startServer(t, root)
+	if _, err := io.WriteString(c.raw, "Content-Length: 9\r\n\r\n{not json"); err != nil {
+		t.Fatal(err)
+	}
+	msg, err := c.conn.Read()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg.Error == nil || msg.Error.Code != jsonrpc.CodeParseError {
+		t.Fatalf("expected parse error response, got %+v", msg)
+	}
+	// The session keeps serving requests
+	var init InitializeResult
+	c.call("initialize", InitializeParams{RootURI: pathToURI(root)}, &init)
+	if !init.Capabilities.DefinitionProvider {
+		t.Fatalf("unexpected capabilities: %+v", init.Capabilities)
+	}
+	c.call("shutdown", nil, nil)
+	c.notify("exit", nil)
+	if err := <-c.done; err != nil {
+		t.Fatalf("serve: %v", err)
+	}
+}
@@ -0,0 +1,265 @@
+// Package mcp implements a Model Context Protocol client over stdio (a spawned
+// server process) and streamable HTTP, and a stdio server for tools and resources.
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/kelvin/tgsflow/src/core/jsonrpc"
+)
+
+// ClientInfo identifies tgs to MCP servers.
+var ClientInfo = Implementation{Name: "tgs"}
+
+// session carries requests to one server connection.
+type session interface {
+	call(ctx context.Context, method string, params, result any) error
+	notify(ctx context.Context, method string, params any) error
+	close() error
+}
+
+// Client is an initialized connection to an MCP server.
+type Client struct {
+	s session
+	// Server is the server's initialize result (name, capabilities, instructions).
+	Server InitializeResult
+}
+
+// DialStdio spawns argv and speaks newline-delimited JSON-RPC over its
+// stdin/stdout. env entries ("KEY=VALUE") are added to the current environment;
+// the server's stderr is passed through to ours.
+func DialStdio(ctx context.Context, argv, env []string) (*Client, error) {
+	if len(argv) == 0 {
+		return nil, errors.New("mcp: empty server command")
+	}
+	cmd := exec.Command(argv[0], argv[1:]...)
+	cmd.Env = append(os.Environ(), env...)
+	cmd.Stderr = os.Stderr
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("mcp: start %s: %w", argv[0], err)
+	}
+	s := &stdioSession{
+		cmd:     cmd,
+		stdin:   stdin,
+		conn:    jsonrpc.NewLineConn(stdout, stdin),
+		pending: make(map[string]chan jsonrpc.Message),
+		done:    make(chan struct{}),
+	}
+	go s.readLoop()
+	return connect(ctx, s)
+}
+
+func connect(ctx context.Context, s session) (*Client, error) {
+	c := &Client{s: s}
+	err := s.call(ctx, "initialize", InitializeParams{
+		ProtocolVersion: ProtocolVersion,
+		Capabilities:    map[string]any{},
+		ClientInfo:      ClientInfo,
+	}, &c.Server)
+	if err == nil {
+		err = s.notify(ctx, "notifications/initialized", nil)
+	}
+	if err != nil {
+		s.close()
+		return nil, fmt.Errorf("mcp: initialize: %w", err)
+	}
+	return c, nil
+}
+
+// HasCapability reports whether the server advertised name (e.g. "tools", "prompts").
+func (c *Client) HasCapability(name string) bool {
+	_, ok := c.Server.Capabilities[name]
+	return ok
+}
+
+// ListTools returns every tool, following pagination cursors.
+func (c *Client) ListTools(ctx context.Context) ([]Tool, error) {
+	var all []Tool
+	cursor := ""
+	for {
+		var res ListToolsResult
+		if err := c.s.call(ctx, "tools/list", cursorParams(cursor), &res); err != nil {
+			return nil, err
+		}
+		all = append(all, res.Tools...)
+		if cursor = res.NextCursor; cursor == "" {
+			return all, nil
+		}
+	}
+}
+
+// ListPrompts returns every prompt, following pagination cursors.
+func (c *Client) ListPrompts(ctx context.Context) ([]Prompt, error) {
+	var all []Prompt
+	cursor := ""
+	for {
+		var res ListPromptsResult
+		if err := c.s.call(ctx, "prompts/list", cursorParams(cursor), &res); err != nil {
+			return nil, err
+		}
+		all = append(all, res.Prompts...)
+		if cursor = res.NextCursor; cursor == "" {
+			return all, nil
+		}
+	}
+}
+
+// GetPrompt renders a prompt with arguments.
+func (c *Client) GetPrompt(ctx context.Context, name string, args map[string]string) (GetPromptResult, error) {
+	var res GetPromptResult
+	err := c.s.call(ctx, "prompts/get", GetPromptParams{Name: name, Arguments: args}, &res)
+	return res, err
+}
+
+// CallTool invokes a tool; args is a JSON object (nil for none).
+func (c *Client) CallTool(ctx context.Context, name string, args json.RawMessage) (CallToolResult, error) {
+	var res CallToolResult
+	err := c.s.call(ctx, "tools/call", CallToolParams{Name: name, Arguments: args}, &res)
+	return res, err
+}
+
+// CreateMessage asks the server for a completion via sampling/createMessage.
+// Only gateways that front a model implement it; others answer "method not found".
+func (c *Client) CreateMessage(ctx context.Context, p CreateMessageParams) (CreateMessageResult, error) {
+	var res CreateMessageResult
+	err := c.s.call(ctx, "sampling/createMessage", p, &res)
+	return res, err
+}
+
+// Close ends the session and, for stdio servers, the process.
+func (c *Client) Close() error { return c.s.close() }
+
+func cursorParams(cursor string) any {
+	if cursor == "" {
+		return nil
+	}
+	return map[string]string{"cursor": cursor}
+}
+
+// stdioSession multiplexes requests over a child process's stdin/stdout.
+type stdioSession struct {
+	cmd   *exec.Cmd
+	stdin io.WriteCloser
+	conn  *jsonrpc.LineConn
+
+	mu      sync.Mutex
+	nextID  int
+	pending map[string]chan jsonrpc.Message
+	done    chan struct{}
+	err     error // why readLoop stopped; valid once done is closed
+}
+
+func (s *stdioSession) readLoop() {
+	for {
+		msg, err := s.conn.Read()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				err = errors.New("server closed the connection")
+			}
+			s.err = err
+			close(s.done)
+			return
+		}
+		switch {
+		case msg.IsRequest():
+			// Servers may ping us; we offer no other client features.
+			reply := jsonrpc.NewErrorResponse(msg.ID, jsonrpc.CodeMethodNotFound, "method not found: "+msg.Method)
+			if msg.Method == "ping" {
+				reply, _ = jsonrpc.NewResponse(msg.ID, struct{}{})
+			}
+			_ = s.conn.Write(reply)
+		case msg.IsNotification():
+			// Logging and list_changed notifications are not used.
+		default:
+			s.mu.Lock()
+			ch := s.pending[string(msg.ID)]
+			delete(s.pending, string(msg.ID))
+			s.mu.Unlock()
+			if ch != nil {
+				ch <- msg
+			}
+		}
+	}
+}
+
+func (s *stdioSession) call(ctx context.Context, method string, params, result any) error {
+	s.mu.Lock()
+	s.nextID++
+	id := s.nextID
+	ch := make(chan jsonrpc.Message, 1)
+	s.pending[strconv.Itoa(id)] = ch
+	s.mu.Unlock()
+	forget := func() {
+		s.mu.Lock()
+		delete(s.pending, strconv.Itoa(id))
+		s.mu.Unlock()
+	}
+
+	req, err := jsonrpc.NewRequest(id, method, params)
+	if err != nil {
+		forget()
+		return err
+	}
+	if err := s.conn.Write(req); err != nil {
+		forget()
+		return err
+	}
+	select {
+	case <-ctx.Done():
+		forget()
+		return ctx.Err()
+	case <-s.done:
+		return s.err
+	case msg := <-ch:
+		return decodeResult(msg, result)
+	}
+}
+
+func (s *stdioSession) notify(ctx context.Context, method string, params any) error {
+	m, err := jsonrpc.NewNotification(method, params)
+	if err != nil {
+		return err
+	}
+	return s.conn.Write(m)
+}
+
+// close shuts stdin so the server can exit on its own, then kills it if it lingers.
+func (s *stdioSession) close() error {
+	_ = s.stdin.Close()
+	exited := make(chan error, 1)
+	go func() { exited <- s.cmd.Wait() }()
+	select {
+	case <-exited:
+	case <-time.After(2 * time.Second):
+		_ = s.cmd.Process.Kill()
+		<-exited
+	}
+	return nil
+}
+
+func decodeResult(msg jsonrpc.Message, result any) error {
+	if msg.Error != nil {
+		return msg.Error
+	}
+	if result == nil || len(msg.Result) == 0 {
+		return nil
+	}
+	return json.Unmarshal(msg.Result, result)
+}
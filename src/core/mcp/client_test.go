@@ -0,0 +1,114 @@
+package mcp_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kelvin/tgsflow/src/core/jsonrpc"
+	"github.com/kelvin/tgsflow/src/core/mcp"
+	"github.com/kelvin/tgsflow/src/core/mcp/mcptest"
+)
+
+// TestMain doubles as the stdio fake server when re-executed by DialStdio.
+func TestMain(m *testing.M) {
+	if os.Getenv("TGS_MCP_FAKE_SERVER") == "1" {
+		if err := mcptest.Default().ServeConn(jsonrpc.NewLineConn(os.Stdin, os.Stdout)); err != nil {
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+func exercise(t *testing.T, c *mcp.Client) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if c.Server.ServerInfo.Name != "mcptest" || !c.HasCapability("tools") {
+		t.Fatalf("initialize result = %+v", c.Server)
+	}
+	tools, err := c.ListTools(ctx)
+	if err != nil || len(tools) != 1 || tools[0].Name != "lookup" || !strings.Contains(string(tools[0].InputSchema), `"term"`) {
+		t.Fatalf("ListTools = %+v, %v", tools, err)
+	}
+	prompts, err := c.ListPrompts(ctx)
+	if err != nil || len(prompts) != 1 || prompts[0].Name != "summarize" {
+		t.Fatalf("ListPrompts = %+v, %v", prompts, err)
+	}
+	got, err := c.GetPrompt(ctx, "summarize", map[string]string{"path": "README.md"})
+	if err != nil || len(got.Messages) != 1 || got.Messages[0].Content.Text != "summarize path=README.md" {
+		t.Fatalf("GetPrompt = %+v, %v", got, err)
+	}
+	res, err := c.CallTool(ctx, "lookup", json.RawMessage(`{"term":"EARS"}`))
+	if err != nil || res.Text() != `lookup {"term":"EARS"}` {
+		t.Fatalf("CallTool = %+v, %v", res, err)
+	}
+	text, _ := json.Marshal(mcp.Content{Type: "text", Text: "hello"})
+	sample, err := c.CreateMessage(ctx, mcp.CreateMessageParams{Messages: []mcp.SamplingMessage{{Role: "user", Content: text}}, MaxTokens: 10})
+	if err != nil {
+		t.Fatalf("CreateMessage: %v", err)
+	}
+	blocks, err := sample.Blocks()
+	if err != nil || len(blocks) != 1 || blocks[0].Text != "echo: hello" {
+		t.Fatalf("CreateMessage blocks = %+v, %v", blocks, err)
+	}
+	if _, err := c.CallTool(ctx, "", nil); err != nil {
+		t.Fatalf("CallTool without args: %v", err)
+	}
+}
+
+func TestClient_Stdio(t *testing.T) {
+	c, err := mcp.DialStdio(context.Background(), []string{os.Args[0], "-test.run=^$"}, []string{"TGS_MCP_FAKE_SERVER=1"})
+	if err != nil {
+		t.Fatalf("DialStdio: %v", err)
+	}
+	defer c.Close()
+	exercise(t, c)
+}
+
+func TestClient_StreamableHTTP(t *testing.T) {
+	for _, sse := range []bool{false, true} {
+		fake := mcptest.Default()
+		fake.SSE = sse
+		srv := httptest.NewServer(fake)
+		c, err := mcp.DialHTTP(context.Background(), srv.URL, map[string]string{"Authorization": "Bearer t"})
+		if err != nil {
+			t.Fatalf("DialHTTP (sse=%v): %v", sse, err)
+		}
+		exercise(t, c)
+		if err := c.Close(); err != nil {
+			t.Fatalf("Close: %v", err)
+		}
+		srv.Close()
+		if m := fake.Methods(); len(m) < 2 || m[0] != "initialize" || m[1] != "notifications/initialized" {
+			t.Fatalf("methods = %v", m)
+		}
+	}
+}
+
+func TestClient_SamplingUnsupported(t *testing.T) {
+	fake := mcptest.Default()
+	fake.Sample = nil
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+	c, err := mcp.DialHTTP(context.Background(), srv.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = c.CreateMessage(context.Background(), mcp.CreateMessageParams{MaxTokens: 1})
+	if rpcErr, ok := err.(*jsonrpc.Error); !ok || rpcErr.Code != jsonrpc.CodeMethodNotFound {
+		t.Fatalf("expected method-not-found, got %v", err)
+	}
+}
+
+func TestDialStdio_ServerExits(t *testing.T) {
+	if _, err := mcp.DialStdio(context.Background(), []string{"sh", "-c", "exit 0"}, nil); err == nil {
+		t.Fatal("expected initialize to fail when the server exits")
+	}
+}
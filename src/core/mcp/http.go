@@ -0,0 +1,188 @@
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/kelvin/tgsflow/src/core/jsonrpc"
+)
+
+// DialHTTP connects to a streamable HTTP endpoint (a single URL accepting
+// POSTed JSON-RPC messages). headers are sent with every request, e.g. an
+// Authorization bearer token.
+func DialHTTP(ctx context.Context, url string, headers map[string]string) (*Client, error) {
+	return connect(ctx, &httpSession{url: url, headers: headers, client: &http.Client{}})
+}
+
+// httpSession implements the streamable HTTP transport: each message is a
+// POST; responses arrive as JSON or as an SSE stream. Server-initiated GET
+// streams are not opened since tgs uses no server-to-client features.
+type httpSession struct {
+	url     string
+	headers map[string]string
+	client  *http.Client
+
+	mu        sync.Mutex
+	nextID    int
+	sessionID string
+	protocol  string // negotiated version, sent after initialize
+}
+
+func (s *httpSession) post(ctx context.Context, m jsonrpc.Message) (*http.Response, error) {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	s.setHeaders(req)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json, text/event-stream")
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if id := resp.Header.Get("Mcp-Session-Id"); id != "" {
+		s.mu.Lock()
+		s.sessionID = id
+		s.mu.Unlock()
+	}
+	if resp.StatusCode/100 != 2 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		resp.Body.Close()
+		return nil, fmt.Errorf("HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return resp, nil
+}
+
+func (s *httpSession) setHeaders(req *http.Request) {
+	for k, v := range s.headers {
+		req.Header.Set(k, v)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.sessionID != "" {
+		req.Header.Set("Mcp-Session-Id", s.sessionID)
+	}
+	if s.protocol != "" {
+		req.Header.Set("MCP-Protocol-Version", s.protocol)
+	}
+}
+
+func (s *httpSession) call(ctx context.Context, method string, params, result any) error {
+	s.mu.Lock()
+	s.nextID++
+	id := s.nextID
+	s.mu.Unlock()
+	req, err := jsonrpc.NewRequest(id, method, params)
+	if err != nil {
+		return err
+	}
+	resp, err := s.post(ctx, req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var reply jsonrpc.Message
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if mediaType == "text/event-stream" {
+		if reply, err = s.awaitEvent(ctx, resp.Body, string(req.ID)); err != nil {
+			return err
+		}
+	} else if err := json.NewDecoder(resp.Body).Decode(&reply); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	if method == "initialize" && reply.Error == nil {
+		var init InitializeResult
+		if json.Unmarshal(reply.Result, &init) == nil && init.ProtocolVersion != "" {
+			s.mu.Lock()
+			s.protocol = init.ProtocolVersion
+			s.mu.Unlock()
+		}
+	}
+	return decodeResult(reply, result)
+}
+
+// awaitEvent reads SSE events until the response with id arrives. Server
+// requests interleaved on the stream are answered (ping) or refused.
+func (s *httpSession) awaitEvent(ctx context.Context, body io.Reader, id string) (jsonrpc.Message, error) {
+	sc := bufio.NewScanner(body)
+	sc.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	var data []string
+	for sc.Scan() {
+		line := sc.Text()
+		if v, ok := strings.CutPrefix(line, "data:"); ok {
+			data = append(data, strings.TrimPrefix(v, " "))
+			continue
+		}
+		if line != "" || len(data) == 0 {
+			continue
+		}
+		var msg jsonrpc.Message
+		err := json.Unmarshal([]byte(strings.Join(data, "\n")), &msg)
+		data = nil
+		if err != nil {
+			continue
+		}
+		switch {
+		case msg.IsRequest():
+			reply := jsonrpc.NewErrorResponse(msg.ID, jsonrpc.CodeMethodNotFound, "method not found: "+msg.Method)
+			if msg.Method == "ping" {
+				reply, _ = jsonrpc.NewResponse(msg.ID, struct{}{})
+			}
+			if resp, err := s.post(ctx, reply); err == nil {
+				resp.Body.Close()
+			}
+		case !msg.IsNotification() && string(msg.ID) == id:
+			return msg, nil
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return jsonrpc.Message{}, err
+	}
+	return jsonrpc.Message{}, fmt.Errorf("event stream ended without a response to request %s", id)
+}
+
+func (s *httpSession) notify(ctx context.Context, method string, params any) error {
+	m, err := jsonrpc.NewNotification(method, params)
+	if err != nil {
+		return err
+	}
+	resp, err := s.post(ctx, m)
+	if err != nil {
+		return err
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return resp.Body.Close()
+}
+
+// close terminates the server-side session when one was assigned.
+func (s *httpSession) close() error {
+	s.mu.Lock()
+	id := s.sessionID
+	s.mu.Unlock()
+	if id == "" {
+		return nil
+	}
+	req, err := http.NewRequest(http.MethodDelete, s.url, nil)
+	if err != nil {
+		return err
+	}
+	s.setHeaders(req)
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
@@ -0,0 +1,185 @@
+// Package mcptest provides a scriptable fake MCP server for tests, served over
+// a jsonrpc.Conn (stdio) or as a streamable HTTP handler.
+package mcptest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/kelvin/tgsflow/src/core/jsonrpc"
+	"github.com/kelvin/tgsflow/src/core/mcp"
+)
+
+// Server answers initialize, tools/*, prompts/* and, when Sample is set,
+// sampling/createMessage. It records every method it receives.
+type Server struct {
+	Tools   []mcp.Tool
+	Prompts []mcp.Prompt
+	// CallTool handles tools/call; nil echoes the tool name and arguments.
+	CallTool func(name string, args json.RawMessage) mcp.CallToolResult
+	// Sample handles sampling/createMessage; nil leaves it unimplemented.
+	Sample func(mcp.CreateMessageParams) mcp.CreateMessageResult
+	// SSE makes the HTTP handler answer requests as event streams.
+	SSE bool
+
+	mu      sync.Mutex
+	methods []string
+}
+
+// Default returns a server with one tool ("lookup"), one prompt ("summarize")
+// and a sampler that echoes the last user message.
+func Default() *Server {
+	return &Server{
+		Tools: []mcp.Tool{{
+			Name:        "lookup",
+			Description: "Look up a term in the team glossary.",
+			InputSchema: json.RawMessage(`{"type":"object","properties":{"term":{"type":"string"}},"required":["term"]}`),
+		}},
+		Prompts: []mcp.Prompt{{
+			Name:        "summarize",
+			Description: "Summarize a document.",
+			Arguments:   []mcp.PromptArgument{{Name: "path", Required: true}},
+		}},
+		Sample: func(p mcp.CreateMessageParams) mcp.CreateMessageResult {
+			last := ""
+			if n := len(p.Messages); n > 0 {
+				var c mcp.Content
+				_ = json.Unmarshal(p.Messages[n-1].Content, &c)
+				last = c.Text
+			}
+			text, _ := json.Marshal(mcp.Content{Type: "text", Text: "echo: " + last})
+			return mcp.CreateMessageResult{Role: "assistant", Content: text, Model: "fake", StopReason: "endTurn"}
+		},
+	}
+}
+
+// Methods returns the methods received so far, in order.
+func (s *Server) Methods() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.methods...)
+}
+
+// ServeConn answers messages from conn until it is closed.
+func (s *Server) ServeConn(conn jsonrpc.Conn) error {
+	for {
+		msg, err := conn.Read()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+		if reply, ok := s.reply(msg); ok {
+			if err := conn.Write(reply); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// ServeHTTP implements the streamable HTTP transport for POSTed messages.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+	case http.MethodDelete:
+		w.WriteHeader(http.StatusNoContent)
+		return
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var msg jsonrpc.Message
+	if err := json.NewDecoder(r.Body).Decode(&msg); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if msg.Method == "initialize" {
+		w.Header().Set("Mcp-Session-Id", "fake-session")
+	} else if r.Header.Get("Mcp-Session-Id") != "fake-session" {
+		http.Error(w, "missing session", http.StatusBadRequest)
+		return
+	}
+	reply, ok := s.reply(msg)
+	if !ok {
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+	data, _ := json.Marshal(reply)
+	if s.SSE {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprintf(w, "event: message\ndata: {\"jsonrpc\":\"2.0\",\"method\":\"notifications/message\",\"params\":{}}\n\n")
+		fmt.Fprintf(w, "event: message\ndata: %s\n\n", data)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(data)
+}
+
+// reply handles one message; ok is false for notifications.
+func (s *Server) reply(msg jsonrpc.Message) (jsonrpc.Message, bool) {
+	s.mu.Lock()
+	s.methods = append(s.methods, msg.Method)
+	s.mu.Unlock()
+	if !msg.IsRequest() {
+		return jsonrpc.Message{}, false
+	}
+	result, rpcErr := s.handle(msg)
+	if rpcErr != nil {
+		return jsonrpc.NewErrorResponse(msg.ID, rpcErr.Code, rpcErr.Message), true
+	}
+	reply, err := jsonrpc.NewResponse(msg.ID, result)
+	if err != nil {
+		return jsonrpc.NewErrorResponse(msg.ID, jsonrpc.CodeInternalError, err.Error()), true
+	}
+	return reply, true
+}
+
+func (s *Server) handle(msg jsonrpc.Message) (any, *jsonrpc.Error) {
+	switch msg.Method {
+	case "initialize":
+		caps := map[string]json.RawMessage{"tools": json.RawMessage(`{}`), "prompts": json.RawMessage(`{}`)}
+		return mcp.InitializeResult{ProtocolVersion: mcp.ProtocolVersion, Capabilities: caps, ServerInfo: mcp.Implementation{Name: "mcptest"}}, nil
+	case "ping":
+		return struct{}{}, nil
+	case "tools/list":
+		return mcp.ListToolsResult{Tools: s.Tools}, nil
+	case "tools/call":
+		var p mcp.CallToolParams
+		if err := json.Unmarshal(msg.Params, &p); err != nil {
+			return nil, &jsonrpc.Error{Code: jsonrpc.CodeInvalidParams, Message: err.Error()}
+		}
+		if s.CallTool != nil {
+			return s.CallTool(p.Name, p.Arguments), nil
+		}
+		return mcp.CallToolResult{Content: []mcp.Content{{Type: "text", Text: p.Name + " " + string(p.Arguments)}}}, nil
+	case "prompts/list":
+		return mcp.ListPromptsResult{Prompts: s.Prompts}, nil
+	case "prompts/get":
+		var p mcp.GetPromptParams
+		if err := json.Unmarshal(msg.Params, &p); err != nil {
+			return nil, &jsonrpc.Error{Code: jsonrpc.CodeInvalidParams, Message: err.Error()}
+		}
+		var args []string
+		for k, v := range p.Arguments {
+			args = append(args, k+"="+v)
+		}
+		text := strings.TrimSpace(p.Name + " " + strings.Join(args, " "))
+		return mcp.GetPromptResult{Messages: []mcp.PromptMessage{{Role: "user", Content: mcp.Content{Type: "text", Text: text}}}}, nil
+	case "sampling/createMessage":
+		if s.Sample == nil {
+			break
+		}
+		var p mcp.CreateMessageParams
+		if err := json.Unmarshal(msg.Params, &p); err != nil {
+			return nil, &jsonrpc.Error{Code: jsonrpc.CodeInvalidParams, Message: err.Error()}
+		}
+		return s.Sample(p), nil
+	}
+	return nil, &jsonrpc.Error{Code: jsonrpc.CodeMethodNotFound, Message: "method not found: " + msg.Method}
+}
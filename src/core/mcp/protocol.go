@@ -0,0 +1,178 @@
+package mcp
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// The subset of Model Context Protocol types used by the tgs client and server.
+
+// ProtocolVersion is the MCP revision tgs speaks.
+const ProtocolVersion = "2025-06-18"
+
+type Implementation struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+}
+
+type InitializeParams struct {
+	ProtocolVersion string         `json:"protocolVersion"`
+	Capabilities    map[string]any `json:"capabilities"`
+	ClientInfo      Implementation `json:"clientInfo"`
+}
+
+type InitializeResult struct {
+	ProtocolVersion string                     `json:"protocolVersion"`
+	Capabilities    map[string]json.RawMessage `json:"capabilities"`
+	ServerInfo      Implementation             `json:"serverInfo"`
+	Instructions    string                     `json:"instructions,omitempty"`
+}
+
+type Tool struct {
+	Name        string          `json:"name"`
+	Title       string          `json:"title,omitempty"`
+	Description string          `json:"description,omitempty"`
+	InputSchema json.RawMessage `json:"inputSchema"`
+}
+
+type ListToolsResult struct {
+	Tools      []Tool `json:"tools"`
+	NextCursor string `json:"nextCursor,omitempty"`
+}
+
+type CallToolParams struct {
+	Name      string          `json:"name"`
+	Arguments json.RawMessage `json:"arguments,omitempty"`
+}
+
+// Content is a text, image or resource block; tgs only reads text.
+type Content struct {
+	Type string `json:"type"`
+	Text string `json:"text,omitempty"`
+	// Tool-use blocks in sampling results (sampling with tools).
+	ID    string          `json:"id,omitempty"`
+	Name  string          `json:"name,omitempty"`
+	Input json.RawMessage `json:"input,omitempty"`
+	// Tool results in sampling requests.
+	ToolUseID string    `json:"toolUseId,omitempty"`
+	Content   []Content `json:"content,omitempty"`
+}
+
+type CallToolResult struct {
+	Content []Content `json:"content"`
+	IsError bool      `json:"isError,omitempty"`
+}
+
+type PromptArgument struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+}
+
+type Prompt struct {
+	Name        string           `json:"name"`
+	Description string           `json:"description,omitempty"`
+	Arguments   []PromptArgument `json:"arguments,omitempty"`
+}
+
+type ListPromptsResult struct {
+	Prompts    []Prompt `json:"prompts"`
+	NextCursor string   `json:"nextCursor,omitempty"`
+}
+
+type GetPromptParams struct {
+	Name      string            `json:"name"`
+	Arguments map[string]string `json:"arguments,omitempty"`
+}
+
+type PromptMessage struct {
+	Role    string  `json:"role"`
+	Content Content `json:"content"`
+}
+
+type GetPromptResult struct {
+	Description string          `json:"description,omitempty"`
+	Messages    []PromptMessage `json:"messages"`
+}
+
+// SamplingMessage.Content is a single block or, when tools are involved, an array.
+type SamplingMessage struct {
+	Role    string          `json:"role"`
+	Content json.RawMessage `json:"content"`
+}
+
+type CreateMessageParams struct {
+	Messages     []SamplingMessage `json:"messages"`
+	SystemPrompt string            `json:"systemPrompt,omitempty"`
+	MaxTokens    int               `json:"maxTokens"`
+	Tools        []Tool            `json:"tools,omitempty"`
+}
+
+type CreateMessageResult struct {
+	Role       string          `json:"role"`
+	Content    json.RawMessage `json:"content"`
+	Model      string          `json:"model,omitempty"`
+	StopReason string          `json:"stopReason,omitempty"`
+}
+
+// Blocks decodes Content, which may be a single block or an array of blocks.
+func (r CreateMessageResult) Blocks() ([]Content, error) {
+	return decodeBlocks(r.Content)
+}
+
+func decodeBlocks(raw json.RawMessage) ([]Content, error) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+	if raw[0] == '[' {
+		var blocks []Content
+		err := json.Unmarshal(raw, &blocks)
+		return blocks, err
+	}
+	var block Content
+	if err := json.Unmarshal(raw, &block); err != nil {
+		return nil, err
+	}
+	return []Content{block}, nil
+}
+
+// Text joins the text blocks of a tool result.
+func (r CallToolResult) Text() string {
+	var out []byte
+	for _, c := range r.Content {
+		if c.Type == "text" {
+			if len(out) > 0 {
+				out = append(out, '\n')
+			}
+			out = append(out, c.Text...)
+		}
+	}
+	return string(out)
+}
+
+type Resource struct {
+	URI         string `json:"uri"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	MimeType    string `json:"mimeType,omitempty"`
+}
+
+type ListResourcesResult struct {
+	Resources  []Resource `json:"resources"`
+	NextCursor string     `json:"nextCursor,omitempty"`
+}
+
+type ReadResourceParams struct {
+	URI string `json:"uri"`
+}
+
+type ResourceContents struct {
+	URI      string `json:"uri"`
+	MimeType string `json:"mimeType,omitempty"`
+	Text     string `json:"text"`
+}
+
+type ReadResourceResult struct {
+	Contents []ResourceContents `json:"contents"`
+}
@@ -0,0 +1,173 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/kelvin/tgsflow/src/core/jsonrpc"
+)
+
+// supportedVersions are the protocol revisions the server accepts from clients.
+var supportedVersions = map[string]bool{"2024-11-05": true, "2025-03-26": true, ProtocolVersion: true}
+
+// ToolHandler runs a tool call. A returned error is reported to the model as a
+// tool result with isError set, not as a protocol error.
+type ToolHandler func(ctx context.Context, args json.RawMessage) (string, error)
+
+// Server answers MCP requests for registered tools and, when ListResources
+// and ReadResource are set, resources. Requests are handled in order.
+type Server struct {
+	Info         Implementation
+	Instructions string
+	// ListResources and ReadResource back resources/list and resources/read.
+	ListResources func(ctx context.Context) ([]Resource, error)
+	ReadResource  func(ctx context.Context, uri string) (ResourceContents, error)
+	// Logf receives diagnostics about the server itself; defaults to stderr.
+	Logf func(format string, args ...any)
+
+	tools    []Tool
+	handlers map[string]ToolHandler
+}
+
+// NewServer creates a server identified by info.
+func NewServer(info Implementation) *Server {
+	return &Server{
+		Info:     info,
+		handlers: make(map[string]ToolHandler),
+		Logf: func(format string, args ...any) {
+			fmt.Fprintf(os.Stderr, "tgs mcp: "+format+"\n", args...)
+		},
+	}
+}
+
+// AddTool registers a tool; a tool with the same name is replaced.
+func (s *Server) AddTool(t Tool, h ToolHandler) {
+	if _, ok := s.handlers[t.Name]; !ok {
+		s.tools = append(s.tools, t)
+	} else {
+		for i := range s.tools {
+			if s.tools[i].Name == t.Name {
+				s.tools[i] = t
+			}
+		}
+	}
+	s.handlers[t.Name] = h
+}
+
+// Serve processes messages from conn until the client closes the stream.
+func (s *Server) Serve(ctx context.Context, conn jsonrpc.Conn) error {
+	for {
+		msg, err := conn.Read()
+		if err != nil {
+			var rpcErr *jsonrpc.Error
+			if errors.As(err, &rpcErr) {
+				if werr := conn.Write(jsonrpc.NewErrorResponse(json.RawMessage("null"), rpcErr.Code, rpcErr.Message)); werr != nil {
+					return werr
+				}
+				continue
+			}
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+		if !msg.IsRequest() {
+			continue // initialized, cancelled and other notifications need no reply
+		}
+		result, rpcErr := s.handle(ctx, msg)
+		var reply jsonrpc.Message
+		if rpcErr != nil {
+			reply = jsonrpc.NewErrorResponse(msg.ID, rpcErr.Code, rpcErr.Message)
+		} else if reply, err = jsonrpc.NewResponse(msg.ID, result); err != nil {
+			reply = jsonrpc.NewErrorResponse(msg.ID, jsonrpc.CodeInternalError, err.Error())
+		}
+		if err := conn.Write(reply); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *Server) handle(ctx context.Context, msg jsonrpc.Message) (any, *jsonrpc.Error) {
+	switch msg.Method {
+	case "initialize":
+		var p InitializeParams
+		if err := decodeParams(msg.Params, &p); err != nil {
+			return nil, err
+		}
+		version := ProtocolVersion
+		if supportedVersions[p.ProtocolVersion] {
+			version = p.ProtocolVersion
+		}
+		caps := map[string]json.RawMessage{"tools": json.RawMessage(`{}`)}
+		if s.ListResources != nil && s.ReadResource != nil {
+			caps["resources"] = json.RawMessage(`{}`)
+		}
+		return InitializeResult{ProtocolVersion: version, Capabilities: caps, ServerInfo: s.Info, Instructions: s.Instructions}, nil
+	case "ping":
+		return struct{}{}, nil
+	case "tools/list":
+		tools := s.tools
+		if tools == nil {
+			tools = []Tool{}
+		}
+		return ListToolsResult{Tools: tools}, nil
+	case "tools/call":
+		var p CallToolParams
+		if err := decodeParams(msg.Params, &p); err != nil {
+			return nil, err
+		}
+		h, ok := s.handlers[p.Name]
+		if !ok {
+			return nil, &jsonrpc.Error{Code: jsonrpc.CodeInvalidParams, Message: "unknown tool: " + p.Name}
+		}
+		out, err := h(ctx, p.Arguments)
+		if err != nil {
+			s.Logf("%s: %v", p.Name, err)
+			return CallToolResult{IsError: true, Content: []Content{{Type: "text", Text: err.Error()}}}, nil
+		}
+		return CallToolResult{Content: []Content{{Type: "text", Text: out}}}, nil
+	case "resources/list":
+		if s.ListResources == nil {
+			break
+		}
+		list, err := s.ListResources(ctx)
+		if err != nil {
+			return nil, &jsonrpc.Error{Code: jsonrpc.CodeInternalError, Message: err.Error()}
+		}
+		if list == nil {
+			list = []Resource{}
+		}
+		return ListResourcesResult{Resources: list}, nil
+	case "resources/read":
+		if s.ReadResource == nil {
+			break
+		}
+		var p ReadResourceParams
+		if err := decodeParams(msg.Params, &p); err != nil {
+			return nil, err
+		}
+		rc, err := s.ReadResource(ctx, p.URI)
+		if err != nil {
+			return nil, &jsonrpc.Error{Code: CodeResourceNotFound, Message: err.Error()}
+		}
+		return ReadResourceResult{Contents: []ResourceContents{rc}}, nil
+	}
+	return nil, &jsonrpc.Error{Code: jsonrpc.CodeMethodNotFound, Message: "method not found: " + msg.Method}
+}
+
+// CodeResourceNotFound is the MCP error code for unreadable resources.
+const CodeResourceNotFound = -32002
+
+func decodeParams(raw json.RawMessage, v any) *jsonrpc.Error {
+	if len(raw) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		return &jsonrpc.Error{Code: jsonrpc.CodeInvalidParams, Message: err.Error()}
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package report
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+//go:embed assets/*
+var assetFS embed.FS
+
+// Pages are the HTML pages written by Write, in navigation order.
+var Pages = []struct{ File, Title string }{
+	{"index.html", "Overview"},
+	{"requirements.html", "Requirements"},
+	{"trace.html", "Trace"},
+	{"thoughts.html", "Thoughts"},
+	{"lint.html", "EARS lint"},
+	{"vnv.html", "V&V matrix"},
+}
+
+var funcs = template.FuncMap{
+	// reqLink returns the catalogue anchor for a need or requirement ID.
+	"reqLink": func(id string) string { return "requirements.html#" + id },
+	// thoughtLink returns the timeline anchor for a thought name.
+	"thoughtLink": func(name string) string { return "thoughts.html#thought-" + name },
+	"join":        strings.Join,
+	"date": func(t time.Time) string {
+		if t.IsZero() {
+			return "unknown"
+		}
+		return t.Format("2006-01-02")
+	},
+}
+
+type page struct {
+	*Site
+	File  string
+	Title string
+	Pages []struct{ File, Title string }
+	DOT   string
+	Mmd   string
+}
+
+// Write renders the site into outDir: one HTML page per view, the stylesheet,
+// and the trace graph as trace.dot and trace.mmd. Only embedded assets are used
+// so the output works offline.
+func (s *Site) Write(outDir string) error {
+	tmpl, err := template.New("").Funcs(funcs).ParseFS(assetFS, "assets/*.tmpl")
+	if err != nil {
+		return fmt.Errorf("parse report templates: %w", err)
+	}
+	if err := os.MkdirAll(outDir, 0o755); err != nil {
+		return err
+	}
+	var dot, mmd bytes.Buffer
+	if s.Graph != nil {
+		if err := s.Graph.WriteDOT(&dot); err != nil {
+			return err
+		}
+		if err := s.Graph.WriteMermaid(&mmd); err != nil {
+			return err
+		}
+	}
+	files := map[string][]byte{"trace.dot": dot.Bytes(), "trace.mmd": mmd.Bytes()}
+	css, err := assetFS.ReadFile("assets/style.css")
+	if err != nil {
+		return err
+	}
+	files["style.css"] = css
+	for _, p := range Pages {
+		var buf bytes.Buffer
+		data := page{Site: s, File: p.File, Title: p.Title, Pages: Pages, DOT: dot.String(), Mmd: mmd.String()}
+		if err := tmpl.ExecuteTemplate(&buf, strings.TrimSuffix(p.File, ".html")+".tmpl", data); err != nil {
+			return fmt.Errorf("render %s: %w", p.File, err)
+		}
+		files[p.File] = buf.Bytes()
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(outDir, name), data, 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
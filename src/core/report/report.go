@@ -0,0 +1,198 @@
+// Package report collects requirements, traceability, thoughts, lint findings
+// and the V&V matrix into a self-contained static HTML site.
+package report
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kelvin/tgsflow/src/core/config"
+	"github.com/kelvin/tgsflow/src/core/ears"
+	"github.com/kelvin/tgsflow/src/core/reqs"
+	"github.com/kelvin/tgsflow/src/core/trace"
+)
+
+// Site is everything rendered by Write.
+type Site struct {
+	Title        string
+	Generated    time.Time
+	Needs        []Need
+	Requirements []Requirement
+	Thoughts     []Thought
+	Findings     []Finding
+	VnV          []trace.VnVRow
+	Graph        *trace.Graph
+	Orphans      trace.Orphans
+}
+
+// Need is a stakeholder need with the requirements refining it.
+type Need struct {
+	reqs.Item
+	Requirements []string
+}
+
+// Requirement is one catalogue row.
+type Requirement struct {
+	reqs.Item
+	Shape    string // EARS shape, "invalid", or "" when the line is not EARS-shaped
+	Issues   []string
+	Coverage trace.CoverageStatus
+	Tests    []string
+	Thoughts []string
+	Commits  []string
+	VnV      []trace.VnVRow
+}
+
+// Thought is a thought directory on the timeline.
+type Thought struct {
+	Name         string
+	Title        string
+	Path         string
+	Date         time.Time
+	Docs         []string
+	Approvals    []Approval
+	Requirements []string
+}
+
+// Approval is an "Approved-By:" style record in a thought document.
+type Approval struct {
+	Doc  string
+	Line int
+	Text string
+}
+
+// Finding is an EARS lint finding.
+type Finding struct {
+	Path    string
+	Line    int
+	ID      string
+	Message string
+}
+
+var (
+	approvalRe = regexp.MustCompile(`(?i)^\s*[-*]?\s*(?:\*\*)?(?:approved(?:[- ]by)?|approval)(?:\*\*)?\s*:\s*(.+)$`)
+	titleRe    = regexp.MustCompile(`(?m)^#\s+(.+)$`)
+)
+
+// Collect gathers the site data. Missing inputs are returned as errors; the site
+// holds whatever could be read.
+func Collect(repoRoot, title string, cfg config.Config, opts trace.Options) (*Site, []error) {
+	g, errs := trace.Build(repoRoot, opts)
+	site := &Site{Title: title, Generated: time.Now().UTC(), Graph: g, Orphans: g.Orphans()}
+
+	// EARS findings and shapes over the configured documents
+	rules, err := reqs.LoadRules(repoRoot, cfg.Guardrails.EARS)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	list, loadErrs := reqs.Load(repoRoot, reqs.DefaultPaths(cfg), reqs.DefaultLanguage(cfg), rules.Glossary.SystemNames())
+	errs = append(errs, loadErrs...)
+	parsed := make(map[string]reqs.Requirement)
+	for _, r := range list {
+		issues := rules.CheckRequirement(r)
+		for _, msg := range issues {
+			site.Findings = append(site.Findings, Finding{Path: r.Path, Line: r.Line, ID: r.ID, Message: msg})
+		}
+		if r.ID != "" {
+			if _, dup := parsed[r.ID]; !dup {
+				parsed[r.ID] = r
+			}
+		}
+	}
+
+	items, err := trace.Requirements(repoRoot, opts)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	// Build already scanned the tests and reported unparsable files in errs
+	cov := trace.Coverage(items, g.Tests(), nil)
+	covByID := make(map[string]trace.RequirementCoverage)
+	for _, c := range cov.Requirements {
+		covByID[c.ID] = c
+	}
+
+	for _, it := range items {
+		row := Requirement{Item: it, Coverage: covByID[it.ID].Status}
+		if r, ok := parsed[it.ID]; ok {
+			row.Shape = "invalid"
+			if r.Valid() {
+				row.Shape = string(r.Result.Shape)
+			}
+			row.Issues = rules.CheckRequirement(r)
+		} else if res, err := ears.ParseRequirement(it.Text); err == nil {
+			row.Shape = string(res.Shape)
+		}
+		for _, t := range covByID[it.ID].Tests {
+			row.Tests = append(row.Tests, t.Path+":"+t.Name)
+		}
+		id := trace.NodeID(trace.KindRequirement, it.ID)
+		for _, e := range g.In(id, trace.RelReferences) {
+			row.Thoughts = append(row.Thoughts, g.Node(e.From).Label)
+		}
+		for _, e := range g.In(id, trace.RelImplements) {
+			row.Commits = append(row.Commits, g.Node(e.From).Label)
+		}
+		for _, e := range g.Out(id, trace.RelVerifiedBy) {
+			n := g.Node(e.To)
+			row.VnV = append(row.VnV, trace.VnVRow{ReqID: it.ID, Method: n.Attrs["method"], Criteria: n.Attrs["criteria"], Artifact: n.Attrs["artifact"], Path: n.Path, Line: n.Line})
+		}
+		site.Requirements = append(site.Requirements, row)
+	}
+
+	for _, n := range g.NodesOf(trace.KindNeed) {
+		need := Need{Item: reqs.Item{ID: n.Label, Path: n.Path, Line: n.Line, Text: n.Attrs["text"]}}
+		for _, e := range g.Out(n.ID, trace.RelRefines) {
+			need.Requirements = append(need.Requirements, g.Node(e.To).Label)
+		}
+		site.Needs = append(site.Needs, need)
+	}
+	for _, n := range g.NodesOf(trace.KindVnV) {
+		site.VnV = append(site.VnV, trace.VnVRow{ReqID: strings.Fields(n.Label)[0], Method: n.Attrs["method"], Criteria: n.Attrs["criteria"], Artifact: n.Attrs["artifact"], Path: n.Path, Line: n.Line})
+	}
+	for _, n := range g.NodesOf(trace.KindThought) {
+		site.Thoughts = append(site.Thoughts, collectThought(repoRoot, g, n))
+	}
+	sort.SliceStable(site.Thoughts, func(i, j int) bool { return site.Thoughts[i].Date.After(site.Thoughts[j].Date) })
+	return site, errs
+}
+
+func collectThought(repoRoot string, g *trace.Graph, n *trace.Node) Thought {
+	th := Thought{Name: n.Label, Title: n.Label, Path: n.Path}
+	dir := filepath.Join(repoRoot, n.Path)
+	if st, err := os.Stat(dir); err == nil {
+		th.Date = st.ModTime().UTC()
+	}
+	if t, ok := trace.FirstAdded(repoRoot, n.Path); ok {
+		th.Date = t.UTC()
+	}
+	docs, _ := filepath.Glob(filepath.Join(dir, "*.md"))
+	sort.Strings(docs)
+	for _, d := range docs {
+		name := filepath.Base(d)
+		th.Docs = append(th.Docs, name)
+		data, err := os.ReadFile(d)
+		if err != nil {
+			continue
+		}
+		if name == "README.md" {
+			if m := titleRe.FindStringSubmatch(string(data)); m != nil {
+				th.Title = strings.TrimSpace(m[1])
+			}
+		}
+		for i, ln := range strings.Split(string(data), "\n") {
+			if m := approvalRe.FindStringSubmatch(ln); m != nil {
+				th.Approvals = append(th.Approvals, Approval{Doc: name, Line: i + 1, Text: strings.TrimSpace(m[1])})
+			}
+		}
+	}
+	for _, e := range g.Out(n.ID, trace.RelReferences) {
+		if to := g.Node(e.To); to.Kind == trace.KindRequirement {
+			th.Requirements = append(th.Requirements, to.Label)
+		}
+	}
+	return th
+}
@@ -0,0 +1,129 @@
+package report
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kelvin/tgsflow/src/core/config"
+	"github.com/kelvin/tgsflow/src/core/trace"
+)
+
+func writeRepo(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for rel, content := range files {
+		path := filepath.Join(dir, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func sampleSite(t *testing.T) (string, *Site) {
+	t.Helper()
+	root := writeRepo(t, map[string]string{
+		"tgs/design/10_needs.md": "# Needs\n\n- **N-001**: Users need briefs.\n",
+		"tgs/design/20_requirements.md": "# Requirements\n\n" +
+			"- **SR-001**: When a brief is requested, the system shall collect docs. (Traces: N-001) (Verification: Test)\n" +
+			"- **SR-002**: When a run ends, shall log runs.\n",
+		"tgs/design/40_vnv.md": "| Req ID | Method | Acceptance Criteria | Artifact/Test |\n|---|---|---|---|\n" +
+			"| SR-001 | T | Brief lists docs | `src/brief_test.go` |\n",
+		"tgs/thoughts/abc1234-brief/README.md": "# Brief collection\n\nApproved-By: Jane Doe 2026-01-02\n",
+		"tgs/thoughts/abc1234-brief/plan.md":   "Implements SR-001.\n",
+		"src/brief_test.go":                    "package src\n\n// Verifies: SR-001\nfunc TestBrief(t *testing.T) {}\n",
+	})
+	opts := trace.Options{DesignDir: "tgs/design", ThoughtsDir: "tgs/thoughts"}
+	site, errs := Collect(root, "Demo", config.Config{}, opts)
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	return root, site
+}
+
+func TestCollect(t *testing.T) {
+	_, site := sampleSite(t)
+	if len(site.Requirements) != 2 || len(site.Needs) != 1 || len(site.VnV) != 1 {
+		t.Fatalf("unexpected site: %+v", site)
+	}
+	sr1 := site.Requirements[0]
+	if sr1.Shape != "event-driven" || sr1.Coverage != trace.CoverageDeclared || len(sr1.Tests) != 1 || len(sr1.VnV) != 1 {
+		t.Errorf("unexpected SR-001 row: %+v", sr1)
+	}
+	if len(sr1.Thoughts) != 1 || sr1.Thoughts[0] != "abc1234-brief" {
+		t.Errorf("expected SR-001 to link the thought, got %v", sr1.Thoughts)
+	}
+	if len(site.Findings) == 0 || site.Findings[0].ID != "SR-002" {
+		t.Errorf("expected an EARS finding for SR-002, got %+v", site.Findings)
+	}
+	if len(site.Thoughts) != 1 {
+		t.Fatalf("expected one thought, got %+v", site.Thoughts)
+	}
+	th := site.Thoughts[0]
+	if th.Title != "Brief collection" || len(th.Approvals) != 1 || th.Approvals[0].Text != "Jane Doe 2026-01-02" {
+		t.Errorf("unexpected thought: %+v", th)
+	}
+}
+
+func TestCollect_ReportsTestScanErrors(t *testing.T) {
+	root := writeRepo(t, map[string]string{
+		"tgs/design/20_requirements.md": "# Requirements\n\n- **SR-001**: When a brief is requested, the system shall collect docs. (Verification: Test)\n",
+		"src/brief_test.go":             "package src\n\n// Verifies: SR-001\nfunc TestBrief(t *testing.T) {}\n",
+		"src/broken_test.go":            "package src\n\nfunc TestBroken(\n",
+	})
+	site, errs := Collect(root, "Demo", config.Config{}, trace.Options{DesignDir: "tgs/design", ThoughtsDir: "tgs/thoughts"})
+	var scanErrs []string
+	for _, err := range errs {
+		if strings.Contains(err.Error(), "scan tests") {
+			scanErrs = append(scanErrs, err.Error())
+		}
+	}
+	if len(scanErrs) != 1 || !strings.Contains(scanErrs[0], "broken_test.go") {
+		t.Fatalf("expected one scan error for broken_test.go, got %v", errs)
+	}
+	if len(site.Requirements) != 1 || site.Requirements[0].Coverage != trace.CoverageDeclared {
+		t.Fatalf("expected SR-001 still covered by brief_test.go, got %+v", site.Requirements)
+	}
+}
+
+func TestWrite_CrossLinked(t *testing.T) {
+	_, site := sampleSite(t)
+	out := t.TempDir()
+	if err := site.Write(out); err != nil {
+		t.Fatal(err)
+	}
+	read := func(name string) string {
+		b, err := os.ReadFile(filepath.Join(out, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(b)
+	}
+	for _, p := range Pages {
+		html := read(p.File)
+		if !strings.Contains(html, `href="style.css"`) || strings.Contains(html, "http://") || strings.Contains(html, "https://") {
+			t.Errorf("%s must only reference local assets", p.File)
+		}
+	}
+	if s := read("requirements.html"); !strings.Contains(s, `<tr id="SR-001">`) || !strings.Contains(s, `href="thoughts.html#thought-abc1234-brief"`) {
+		t.Errorf("catalogue missing anchors or thought links:\n%s", s)
+	}
+	if s := read("lint.html"); !strings.Contains(s, `href="requirements.html#SR-002"`) {
+		t.Errorf("lint findings not linked to the catalogue:\n%s", s)
+	}
+	if s := read("thoughts.html"); !strings.Contains(s, "Jane Doe 2026-01-02") || !strings.Contains(s, `href="requirements.html#SR-001"`) {
+		t.Errorf("timeline missing approval or requirement link:\n%s", s)
+	}
+	if s := read("vnv.html"); !strings.Contains(s, `id="vnv-SR-001"`) {
+		t.Errorf("V&V matrix missing row anchor:\n%s", s)
+	}
+	if s := read("trace.mmd"); !strings.Contains(s, "SR-001") {
+		t.Errorf("mermaid graph missing requirement:\n%s", s)
+	}
+	read("trace.dot")
+}
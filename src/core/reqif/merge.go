@@ -0,0 +1,88 @@
+package reqif
+
+import (
+	"strings"
+
+	"github.com/kelvin/tgsflow/src/core/reqs"
+)
+
+// MergeReport summarises what Merge changed.
+type MergeReport struct {
+	Updated   []string `json:"updated"`
+	Added     []string `json:"added"`
+	Unchanged []string `json:"unchanged"`
+	// Missing lists IDs in the document that the import did not contain; they are kept.
+	Missing []string `json:"missing"`
+}
+
+// Merge applies imported requirements to a Markdown requirements document.
+// Existing bullets are edited in place (only the changed text or annotation),
+// new IDs are inserted after the last bullet with the same prefix (e.g. "SR-"),
+// and bullets absent from the import are left untouched.
+func Merge(path, doc string, imported []Requirement) (string, MergeReport) {
+	var rep MergeReport
+	lines := strings.Split(doc, "\n")
+	items := reqs.ScanItems(path, doc)
+	byID := make(map[string]reqs.Item, len(items))
+	for _, it := range items {
+		if _, dup := byID[it.ID]; !dup {
+			byID[it.ID] = it
+		}
+	}
+	inImport := make(map[string]bool, len(imported))
+	// insertAfter[i] holds new bullets to emit after line index i (-1 = end of doc)
+	insertAfter := make(map[int][]string)
+	lastByPrefix := make(map[string]int)
+	lastItem := -1
+	for _, it := range items {
+		lastByPrefix[idPrefix(it.ID)] = it.Line - 1
+		lastItem = it.Line - 1
+	}
+	for _, r := range imported {
+		inImport[r.ID] = true
+		it, ok := byID[r.ID]
+		if !ok {
+			at, ok := lastByPrefix[idPrefix(r.ID)]
+			if !ok {
+				at = lastItem
+			}
+			insertAfter[at] = append(insertAfter[at], reqs.FormatItem(r.ID, r.Text, r.Verification, r.Parents))
+			rep.Added = append(rep.Added, r.ID)
+			continue
+		}
+		updated := reqs.RewriteItem(lines[it.Line-1], it, r.Text, r.Verification, r.Parents)
+		if updated == lines[it.Line-1] {
+			rep.Unchanged = append(rep.Unchanged, r.ID)
+			continue
+		}
+		lines[it.Line-1] = updated
+		rep.Updated = append(rep.Updated, r.ID)
+	}
+	for _, it := range items {
+		if !inImport[it.ID] {
+			rep.Missing = append(rep.Missing, it.ID)
+		}
+	}
+	if len(insertAfter) == 0 {
+		return strings.Join(lines, "\n"), rep
+	}
+
+	var out []string
+	if extra, ok := insertAfter[-1]; ok {
+		// No bullets yet: append at the end, before a trailing newline
+		trimmed := strings.TrimRight(strings.Join(lines, "\n"), "\n")
+		return trimmed + "\n\n" + strings.Join(extra, "\n") + "\n", rep
+	}
+	for i, ln := range lines {
+		out = append(out, ln)
+		out = append(out, insertAfter[i]...)
+	}
+	return strings.Join(out, "\n"), rep
+}
+
+func idPrefix(id string) string {
+	if i := strings.LastIndex(id, "-"); i >= 0 {
+		return id[:i+1]
+	}
+	return id
+}
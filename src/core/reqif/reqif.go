@@ -0,0 +1,273 @@
+// Package reqif reads and writes requirements as ReqIF 1.2 XML, the exchange
+// format used by DOORS, Polarion and similar requirement management tools.
+package reqif
+
+import (
+	"encoding/xml"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Namespace is the ReqIF 1.2 XML namespace.
+const Namespace = "http://www.omg.org/spec/ReqIF/20110401/reqif.xsd"
+
+// Attribute long names written on export. Import also accepts common aliases.
+const (
+	AttrID           = "ReqIF.ForeignID"
+	AttrText         = "ReqIF.Text"
+	AttrShape        = "TGS.Shape"
+	AttrVerification = "TGS.Verification"
+	AttrTraces       = "TGS.Traces"
+)
+
+// Requirement is the tool-neutral record exchanged via ReqIF.
+type Requirement struct {
+	ID           string
+	Text         string
+	Shape        string
+	Verification string
+	Parents      []string
+}
+
+type identifiable struct {
+	Identifier string `xml:"IDENTIFIER,attr"`
+	LongName   string `xml:"LONG-NAME,attr,omitempty"`
+	LastChange string `xml:"LAST-CHANGE,attr"`
+}
+
+type document struct {
+	XMLName xml.Name `xml:"REQ-IF"`
+	Xmlns   string   `xml:"xmlns,attr,omitempty"`
+	Header  header   `xml:"THE-HEADER>REQ-IF-HEADER"`
+	Content content  `xml:"CORE-CONTENT>REQ-IF-CONTENT"`
+}
+
+type header struct {
+	Identifier   string `xml:"IDENTIFIER,attr"`
+	CreationTime string `xml:"CREATION-TIME"`
+	ToolID       string `xml:"REQ-IF-TOOL-ID"`
+	Version      string `xml:"REQ-IF-VERSION"`
+	SourceToolID string `xml:"SOURCE-TOOL-ID"`
+	Title        string `xml:"TITLE"`
+}
+
+type content struct {
+	Strings        []datatypeString `xml:"DATATYPES>DATATYPE-DEFINITION-STRING"`
+	XHTMLTypes     []identifiable   `xml:"DATATYPES>DATATYPE-DEFINITION-XHTML"`
+	ObjectTypes    []specObjectType `xml:"SPEC-TYPES>SPEC-OBJECT-TYPE"`
+	SpecTypes      []identifiable   `xml:"SPEC-TYPES>SPECIFICATION-TYPE"`
+	Objects        []specObject     `xml:"SPEC-OBJECTS>SPEC-OBJECT"`
+	Specifications []specification  `xml:"SPECIFICATIONS>SPECIFICATION"`
+}
+
+type datatypeString struct {
+	identifiable
+	MaxLength int `xml:"MAX-LENGTH,attr"`
+}
+
+type specObjectType struct {
+	identifiable
+	StringAttrs []attrDefString `xml:"SPEC-ATTRIBUTES>ATTRIBUTE-DEFINITION-STRING"`
+	XHTMLAttrs  []attrDefXHTML  `xml:"SPEC-ATTRIBUTES>ATTRIBUTE-DEFINITION-XHTML"`
+}
+
+type attrDefString struct {
+	identifiable
+	Type string `xml:"TYPE>DATATYPE-DEFINITION-STRING-REF"`
+}
+
+type attrDefXHTML struct {
+	identifiable
+	Type string `xml:"TYPE>DATATYPE-DEFINITION-XHTML-REF"`
+}
+
+type specObject struct {
+	identifiable
+	Type   string            `xml:"TYPE>SPEC-OBJECT-TYPE-REF"`
+	Values []attrValueString `xml:"VALUES>ATTRIBUTE-VALUE-STRING"`
+	XHTML  []attrValueXHTML  `xml:"VALUES>ATTRIBUTE-VALUE-XHTML"`
+}
+
+type attrValueString struct {
+	Value      string `xml:"THE-VALUE,attr"`
+	Definition string `xml:"DEFINITION>ATTRIBUTE-DEFINITION-STRING-REF"`
+}
+
+type attrValueXHTML struct {
+	Definition string `xml:"DEFINITION>ATTRIBUTE-DEFINITION-XHTML-REF"`
+	Value      struct {
+		Inner string `xml:",innerxml"`
+	} `xml:"THE-VALUE"`
+}
+
+type specification struct {
+	identifiable
+	Type     string          `xml:"TYPE>SPECIFICATION-TYPE-REF"`
+	Children []specHierarchy `xml:"CHILDREN>SPEC-HIERARCHY"`
+}
+
+type specHierarchy struct {
+	identifiable
+	Object   string          `xml:"OBJECT>SPEC-OBJECT-REF"`
+	Children []specHierarchy `xml:"CHILDREN>SPEC-HIERARCHY"`
+}
+
+var nonNCNameRe = regexp.MustCompile(`[^A-Za-z0-9_.-]`)
+
+func xmlID(prefix, id string) string { return prefix + "-" + nonNCNameRe.ReplaceAllString(id, "_") }
+
+// Export renders list as a ReqIF document with one specification named title.
+func Export(title string, list []Requirement, now time.Time) ([]byte, error) {
+	stamp := now.UTC().Format(time.RFC3339)
+	ident := func(id, name string) identifiable {
+		return identifiable{Identifier: id, LongName: name, LastChange: stamp}
+	}
+	attrs := []struct{ id, name string }{
+		{"AD-ID", AttrID}, {"AD-TEXT", AttrText}, {"AD-SHAPE", AttrShape},
+		{"AD-VERIFICATION", AttrVerification}, {"AD-TRACES", AttrTraces},
+	}
+	objType := specObjectType{identifiable: ident("SOT-REQUIREMENT", "Requirement")}
+	for _, a := range attrs {
+		objType.StringAttrs = append(objType.StringAttrs, attrDefString{identifiable: ident(a.id, a.name), Type: "DT-STRING"})
+	}
+	doc := document{
+		Xmlns: Namespace,
+		Header: header{
+			Identifier: xmlID("HDR", title), CreationTime: stamp, ToolID: "tgs", Version: "1.0",
+			SourceToolID: "tgs", Title: title,
+		},
+		Content: content{
+			Strings:     []datatypeString{{identifiable: ident("DT-STRING", "String"), MaxLength: 32000}},
+			ObjectTypes: []specObjectType{objType},
+			SpecTypes:   []identifiable{ident("ST-DOCUMENT", "Requirements Document")},
+		},
+	}
+	spec := specification{identifiable: ident(xmlID("SPEC", title), title), Type: "ST-DOCUMENT"}
+	for _, r := range list {
+		obj := specObject{identifiable: ident(xmlID("SO", r.ID), ""), Type: "SOT-REQUIREMENT"}
+		for _, v := range []struct{ def, value string }{
+			{"AD-ID", r.ID}, {"AD-TEXT", r.Text}, {"AD-SHAPE", r.Shape},
+			{"AD-VERIFICATION", r.Verification}, {"AD-TRACES", strings.Join(r.Parents, ", ")},
+		} {
+			if v.value != "" {
+				obj.Values = append(obj.Values, attrValueString{Value: v.value, Definition: v.def})
+			}
+		}
+		doc.Content.Objects = append(doc.Content.Objects, obj)
+		spec.Children = append(spec.Children, specHierarchy{identifiable: ident(xmlID("SH", r.ID), ""), Object: obj.Identifier})
+	}
+	doc.Content.Specifications = []specification{spec}
+	out, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), append(out, '\n')...), nil
+}
+
+// Import reads the requirements of a ReqIF document in specification order
+// (falling back to SPEC-OBJECT order). Attribute definitions are matched by
+// long name, so files authored in other tools import as long as they carry an
+// ID and a text attribute; XHTML values are reduced to plain text.
+func Import(data []byte) ([]Requirement, error) {
+	var doc document
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parse ReqIF: %w", err)
+	}
+	names := make(map[string]string)
+	for _, t := range doc.Content.ObjectTypes {
+		for _, a := range t.StringAttrs {
+			names[a.Identifier] = a.LongName
+		}
+		for _, a := range t.XHTMLAttrs {
+			names[a.Identifier] = a.LongName
+		}
+	}
+	byRef := make(map[string]Requirement)
+	var order []string
+	for _, o := range doc.Content.Objects {
+		var r Requirement
+		set := func(def, value string) {
+			value = strings.TrimSpace(value)
+			switch field(names[def]) {
+			case "id":
+				r.ID = value
+			case "text":
+				r.Text = value
+			case "shape":
+				r.Shape = value
+			case "verification":
+				r.Verification = value
+			case "traces":
+				for _, p := range strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == ';' || r == ' ' }) {
+					r.Parents = append(r.Parents, p)
+				}
+			}
+		}
+		for _, v := range o.Values {
+			set(v.Definition, v.Value)
+		}
+		for _, v := range o.XHTML {
+			set(v.Definition, xhtmlText(v.Value.Inner))
+		}
+		if r.ID == "" {
+			continue
+		}
+		byRef[o.Identifier] = r
+		order = append(order, o.Identifier)
+	}
+
+	var out []Requirement
+	seen := make(map[string]bool)
+	var walk func([]specHierarchy)
+	walk = func(hs []specHierarchy) {
+		for _, h := range hs {
+			if r, ok := byRef[h.Object]; ok && !seen[h.Object] {
+				seen[h.Object] = true
+				out = append(out, r)
+			}
+			walk(h.Children)
+		}
+	}
+	for _, s := range doc.Content.Specifications {
+		walk(s.Children)
+	}
+	for _, ref := range order {
+		if !seen[ref] {
+			out = append(out, byRef[ref])
+		}
+	}
+	return out, nil
+}
+
+// field maps an attribute long name to the Requirement field it fills.
+func field(longName string) string {
+	n := strings.ToLower(longName)
+	switch {
+	case n == strings.ToLower(AttrID) || n == "id" || n == "identifier" || strings.HasSuffix(n, ".id"):
+		return "id"
+	case n == strings.ToLower(AttrText) || n == "text" || n == "description" || strings.HasSuffix(n, ".text"):
+		return "text"
+	case strings.Contains(n, "shape"):
+		return "shape"
+	case strings.Contains(n, "verification"):
+		return "verification"
+	case strings.Contains(n, "trace") || strings.Contains(n, "parent"):
+		return "traces"
+	}
+	return ""
+}
+
+var (
+	tagRe   = regexp.MustCompile(`<[^>]*>`)
+	spaceRe = regexp.MustCompile(`\s+`)
+)
+
+func xhtmlText(inner string) string {
+	s := tagRe.ReplaceAllString(inner, " ")
+	for _, e := range [][2]string{{"&lt;", "<"}, {"&gt;", ">"}, {"&quot;", `"`}, {"&apos;", "'"}, {"&#39;", "'"}, {"&amp;", "&"}} {
+		s = strings.ReplaceAll(s, e[0], e[1])
+	}
+	return strings.TrimSpace(spaceRe.ReplaceAllString(s, " "))
+}
@@ -0,0 +1,94 @@
+package reqif
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kelvin/tgsflow/src/core/reqs"
+)
+
+const doc = "# System Requirements\n\n" +
+	"## Functional Requirements\n" +
+	"- **SR-001**: When a brief is requested, the system shall collect `tgs/design/` docs. (Traces: N-001) (Verification: Test)\n" +
+	"  * **SR-002**: The system shall log runs & errors.   (Verification: Inspection)\n" +
+	"\n## Interfaces\n" +
+	"- **IF-001**: The system shall expose a `make new-thought` target.\n"
+
+func toReqIF(items []reqs.Item) []Requirement {
+	var out []Requirement
+	for _, it := range items {
+		out = append(out, Requirement{ID: it.ID, Text: it.Text, Verification: it.Verification, Parents: it.Parents})
+	}
+	return out
+}
+
+func TestRoundTrip_PreservesDocument(t *testing.T) {
+	data, err := Export("20_requirements.md", toReqIF(reqs.ScanItems("20_requirements.md", doc)), time.Unix(0, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`xmlns="` + Namespace + `"`, `THE-VALUE="The system shall log runs &amp; errors"`, `<SPEC-OBJECT-REF>SO-IF-001</SPEC-OBJECT-REF>`} {
+		if !strings.Contains(string(data), want) {
+			t.Fatalf("export missing %s:\n%s", want, data)
+		}
+	}
+	imported, err := Import(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	merged, rep := Merge("20_requirements.md", doc, imported)
+	if merged != doc {
+		t.Fatalf("round trip changed the document:\n%s", merged)
+	}
+	if len(rep.Unchanged) != 3 || len(rep.Updated)+len(rep.Added)+len(rep.Missing) != 0 {
+		t.Fatalf("unexpected report: %+v", rep)
+	}
+}
+
+func TestMerge_EditsInPlaceAndInserts(t *testing.T) {
+	imported := toReqIF(reqs.ScanItems("20_requirements.md", doc))
+	imported[0].Parents = []string{"N-001", "N-002"}
+	imported[1].Text = "The system shall log runs and errors"
+	imported[1].Verification = "Test"
+	imported = append(imported[:2], Requirement{ID: "SR-003", Text: "The system shall redact secrets.", Verification: "Analysis"})
+	merged, rep := Merge("20_requirements.md", doc, imported)
+
+	want := "# System Requirements\n\n" +
+		"## Functional Requirements\n" +
+		"- **SR-001**: When a brief is requested, the system shall collect `tgs/design/` docs. (Traces: N-001, N-002) (Verification: Test)\n" +
+		"  * **SR-002**: The system shall log runs and errors.   (Verification: Test)\n" +
+		"- **SR-003**: The system shall redact secrets. (Verification: Analysis)\n" +
+		"\n## Interfaces\n" +
+		"- **IF-001**: The system shall expose a `make new-thought` target.\n"
+	if merged != want {
+		t.Fatalf("unexpected merge:\n%s", merged)
+	}
+	if strings.Join(rep.Updated, ",") != "SR-001,SR-002" || strings.Join(rep.Added, ",") != "SR-003" || strings.Join(rep.Missing, ",") != "IF-001" {
+		t.Fatalf("unexpected report: %+v", rep)
+	}
+}
+
+func TestImport_ForeignXHTML(t *testing.T) {
+	const foreign = `<?xml version="1.0" encoding="UTF-8"?>
+<REQ-IF xmlns="http://www.omg.org/spec/ReqIF/20110401/reqif.xsd" xmlns:xhtml="http://www.w3.org/1999/xhtml">
+  <CORE-CONTENT><REQ-IF-CONTENT>
+    <SPEC-TYPES><SPEC-OBJECT-TYPE IDENTIFIER="t" LAST-CHANGE="x"><SPEC-ATTRIBUTES>
+      <ATTRIBUTE-DEFINITION-STRING IDENTIFIER="a1" LONG-NAME="ID" LAST-CHANGE="x"/>
+      <ATTRIBUTE-DEFINITION-XHTML IDENTIFIER="a2" LONG-NAME="ReqIF.Text" LAST-CHANGE="x"/>
+    </SPEC-ATTRIBUTES></SPEC-OBJECT-TYPE></SPEC-TYPES>
+    <SPEC-OBJECTS><SPEC-OBJECT IDENTIFIER="o1" LAST-CHANGE="x"><VALUES>
+      <ATTRIBUTE-VALUE-STRING THE-VALUE="SR-010"><DEFINITION><ATTRIBUTE-DEFINITION-STRING-REF>a1</ATTRIBUTE-DEFINITION-STRING-REF></DEFINITION></ATTRIBUTE-VALUE-STRING>
+      <ATTRIBUTE-VALUE-XHTML><DEFINITION><ATTRIBUTE-DEFINITION-XHTML-REF>a2</ATTRIBUTE-DEFINITION-XHTML-REF></DEFINITION>
+        <THE-VALUE><xhtml:div>The system shall <xhtml:b>sign</xhtml:b> briefs &amp; logs.</xhtml:div></THE-VALUE></ATTRIBUTE-VALUE-XHTML>
+    </VALUES></SPEC-OBJECT></SPEC-OBJECTS>
+  </REQ-IF-CONTENT></CORE-CONTENT>
+</REQ-IF>`
+	got, err := Import([]byte(foreign))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].ID != "SR-010" || got[0].Text != "The system shall sign briefs & logs." {
+		t.Fatalf("unexpected import: %+v", got)
+	}
+}
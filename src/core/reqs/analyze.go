@@ -0,0 +1,141 @@
+package reqs
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/kelvin/tgsflow/src/core/ears"
+)
+
+// FindingKind classifies an analysis finding.
+type FindingKind string
+
+const (
+	FindingDuplicate FindingKind = "duplicate"
+	FindingConflict  FindingKind = "conflict"
+)
+
+// DuplicateThreshold is the minimum response token similarity (Jaccard) at which two
+// requirements with the same condition are reported as likely duplicates.
+const DuplicateThreshold = 0.7
+
+// ConflictThreshold is the minimum response similarity at which a "shall" and a
+// "shall not" for the same condition are reported as a potential conflict.
+const ConflictThreshold = 0.5
+
+// Finding is a relationship between two requirements that share a condition.
+type Finding struct {
+	Kind       FindingKind `json:"kind"`
+	A          string      `json:"a"`
+	B          string      `json:"b"`
+	Similarity float64     `json:"similarity"`
+	Message    string      `json:"message"`
+}
+
+// ConditionKey groups requirements by system, normalized preconditions (order
+// independent) and normalized trigger, e.g. "alarm|armed mode|motion is detected".
+func ConditionKey(res ears.Result) string {
+	pcs := make([]string, 0, len(res.Preconditions))
+	for _, pc := range res.Preconditions {
+		if k := NormalizePhrase(pc); k != "" {
+			pcs = append(pcs, k)
+		}
+	}
+	sort.Strings(pcs)
+	return systemName(res) + "|" + strings.Join(pcs, " & ") + "|" + NormalizePhrase(res.Trigger)
+}
+
+// Analyze compares valid requirements that share a ConditionKey. Pairs with the
+// same polarity and similar responses are reported as duplicates; a "shall" and
+// a "shall not" with similar responses are reported as conflicts.
+func Analyze(list []Requirement) []Finding {
+	groups := make(map[string][]Requirement)
+	var keys []string
+	for _, r := range list {
+		if !r.Valid() {
+			continue
+		}
+		k := ConditionKey(r.Result)
+		if _, ok := groups[k]; !ok {
+			keys = append(keys, k)
+		}
+		groups[k] = append(groups[k], r)
+	}
+
+	var out []Finding
+	for _, k := range keys {
+		g := groups[k]
+		for i := 0; i < len(g); i++ {
+			for j := i + 1; j < len(g); j++ {
+				a, b := g[i], g[j]
+				sim := Similarity(a.Result.Response, b.Result.Response)
+				switch {
+				case a.Result.Polarity != b.Result.Polarity && sim >= ConflictThreshold:
+					out = append(out, Finding{
+						Kind: FindingConflict, A: a.Ref(), B: b.Ref(), Similarity: sim,
+						Message: fmt.Sprintf("%s and %s state opposing responses for the same condition (%q vs %q)", a.Ref(), b.Ref(), modalResponse(a.Result), modalResponse(b.Result)),
+					})
+				case a.Result.Polarity == b.Result.Polarity && sim >= DuplicateThreshold:
+					out = append(out, Finding{
+						Kind: FindingDuplicate, A: a.Ref(), B: b.Ref(), Similarity: sim,
+						Message: fmt.Sprintf("%s and %s are likely duplicates (response similarity %.2f)", a.Ref(), b.Ref(), sim),
+					})
+				}
+			}
+		}
+	}
+	return out
+}
+
+func modalResponse(res ears.Result) string {
+	if res.Polarity == ears.PolarityNegative {
+		return "shall not " + res.Response
+	}
+	return "shall " + res.Response
+}
+
+// stopwords are ignored when comparing responses.
+var stopwords = map[string]bool{
+	"a": true, "an": true, "the": true, "to": true, "of": true, "and": true, "or": true,
+	"in": true, "on": true, "for": true, "with": true, "by": true, "be": true, "it": true,
+	"its": true, "is": true, "all": true, "any": true, "that": true, "this": true,
+}
+
+// Similarity returns the Jaccard similarity of the content tokens of a and b in [0,1].
+func Similarity(a, b string) float64 {
+	ta, tb := tokenSet(a), tokenSet(b)
+	if len(ta) == 0 && len(tb) == 0 {
+		return 1
+	}
+	inter := 0
+	for t := range ta {
+		if tb[t] {
+			inter++
+		}
+	}
+	union := len(ta) + len(tb) - inter
+	if union == 0 {
+		return 0
+	}
+	return float64(inter) / float64(union)
+}
+
+func tokenSet(s string) map[string]bool {
+	out := make(map[string]bool)
+	words := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !(unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '-')
+	})
+	for _, w := range words {
+		if stopwords[w] {
+			continue
+		}
+		// crude stemming so "logs"/"log" and "requests"/"request" compare equal
+		if len(w) > 3 && strings.HasSuffix(w, "s") && !strings.HasSuffix(w, "ss") {
+			w = strings.TrimSuffix(w, "s")
+		}
+		out[w] = true
+	}
+	return out
+}
@@ -0,0 +1,49 @@
+package reqs
+
+import "testing"
+
+const analyzeDoc = `- **SR-001**: When a request arrives, the gateway shall log the request.
+- **SR-002**: When the request arrives, the gateway shall log all requests.
+- **SR-003**: If the token is expired, then the gateway shall forward the request.
+- **SR-004**: If the token is expired, then the gateway shall not forward the request.
+- **SR-005**: When a request arrives, the gateway shall validate the token.
+- **SR-006**: When a request arrives, the proxy shall log the request.
+`
+
+func TestAnalyze_DuplicatesAndConflicts(t *testing.T) {
+	findings := Analyze(Scan("20_requirements.md", analyzeDoc, true))
+	if len(findings) != 2 {
+		t.Fatalf("expected 2 findings, got %+v", findings)
+	}
+	dup, conflict := findings[0], findings[1]
+	if dup.Kind != FindingDuplicate || dup.A != "SR-001" || dup.B != "SR-002" {
+		t.Fatalf("unexpected duplicate finding: %+v", dup)
+	}
+	if conflict.Kind != FindingConflict || conflict.A != "SR-003" || conflict.B != "SR-004" {
+		t.Fatalf("unexpected conflict finding: %+v", conflict)
+	}
+}
+
+func TestConditionKey_PreconditionOrder(t *testing.T) {
+	a := Scan("x.md", "While door is open and battery is low, the system shall beep.", true)
+	b := Scan("x.md", "While battery is low and the door is open, the system shall beep.", true)
+	if ConditionKey(a[0].Result) != ConditionKey(b[0].Result) {
+		t.Fatalf("expected equal keys, got %q vs %q", ConditionKey(a[0].Result), ConditionKey(b[0].Result))
+	}
+}
+
+func TestSimilarity(t *testing.T) {
+	if s := Similarity("log the request", "log all requests"); s != 1 {
+		t.Fatalf("expected 1, got %v", s)
+	}
+	if s := Similarity("log the request", "validate the token"); s != 0 {
+		t.Fatalf("expected 0, got %v", s)
+	}
+	// Non-ASCII letters are part of the word, not separators
+	if s := Similarity("Größe prüfen", "Grüße prüfen"); s >= 0.5 {
+		t.Fatalf("expected Größe and Grüße to differ, got %v", s)
+	}
+	if s := Similarity("créer une tâche", "créer une tâche"); s != 1 {
+		t.Fatalf("expected 1, got %v", s)
+	}
+}
@@ -0,0 +1,162 @@
+package reqs
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// BaselineDir is the repo-relative directory holding named baselines.
+const BaselineDir = "tgs/baselines"
+
+// Snapshot is a named, hashed record of the ID'd items (requirements, needs,
+// constraints) at a point in time. Hash covers the entries so edits to a stored baseline are detected.
+type Snapshot struct {
+	Name         string          `json:"name"`
+	Created      time.Time       `json:"created"`
+	Source       string          `json:"source,omitempty"` // e.g. the git commit the baseline was taken at
+	Hash         string          `json:"hash"`
+	Requirements []SnapshotEntry `json:"requirements"`
+}
+
+// SnapshotEntry is one requirement in a Snapshot.
+type SnapshotEntry struct {
+	ID           string `json:"id"`
+	Path         string `json:"path"`
+	Line         int    `json:"line"`
+	Text         string `json:"text"`
+	Verification string `json:"verification,omitempty"`
+	Shape        string `json:"shape"` // EARS shape, "invalid", or "" for items that are not EARS candidates
+	System       string `json:"system,omitempty"`
+	Hash         string `json:"hash"` // sha256 of the normalised text
+}
+
+// NewSnapshot records the ID'd items, taking the EARS shape and system from the
+// requirement scanned at the same path and line in list; for duplicate IDs the
+// first occurrence wins.
+func NewSnapshot(name string, items []Item, list []Requirement) Snapshot {
+	s := Snapshot{Name: name, Created: time.Now().UTC().Truncate(time.Second)}
+	parsed := make(map[string]Requirement, len(list))
+	for _, r := range list {
+		parsed[fmt.Sprintf("%s:%d", r.Path, r.Line)] = r
+	}
+	seen := make(map[string]bool)
+	for _, it := range items {
+		if seen[it.ID] {
+			continue
+		}
+		seen[it.ID] = true
+		e := SnapshotEntry{ID: it.ID, Path: it.Path, Line: it.Line, Text: it.Text, Verification: it.Verification, Hash: hashText(normalizeText(it.Text))}
+		if r, ok := parsed[fmt.Sprintf("%s:%d", it.Path, it.Line)]; ok {
+			e.Shape = "invalid"
+			if r.Valid() {
+				e.Shape = string(r.Result.Shape)
+			}
+			e.System = r.Result.System
+		}
+		s.Requirements = append(s.Requirements, e)
+	}
+	s.Hash = s.computeHash()
+	return s
+}
+
+func (s Snapshot) computeHash() string {
+	h := sha256.New()
+	for _, e := range s.Requirements {
+		fmt.Fprintf(h, "%s\x00%s\x00%s\x00%s\x00%s\n", e.ID, e.Hash, e.Shape, e.System, e.Verification)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func normalizeText(s string) string { return strings.Join(strings.Fields(s), " ") }
+
+func hashText(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+// WriteSnapshot stores s as indented JSON at path, creating parent directories.
+func WriteSnapshot(path string, s Snapshot) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// ReadSnapshot loads a baseline and checks its hash.
+func ReadSnapshot(path string) (Snapshot, error) {
+	var s Snapshot
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return s, err
+	}
+	if err := json.Unmarshal(data, &s); err != nil {
+		return s, fmt.Errorf("%s: %w", path, err)
+	}
+	for _, e := range s.Requirements {
+		if e.Hash != hashText(normalizeText(e.Text)) {
+			return s, fmt.Errorf("%s: hash mismatch for %s; the baseline was edited", path, e.ID)
+		}
+	}
+	if s.computeHash() != s.Hash {
+		return s, fmt.Errorf("%s: snapshot hash mismatch; the baseline was edited", path)
+	}
+	return s, nil
+}
+
+// ChangeKind classifies a difference between two snapshots.
+type ChangeKind string
+
+const (
+	ChangeAdded        ChangeKind = "added"
+	ChangeRemoved      ChangeKind = "removed"
+	ChangeReworded     ChangeKind = "reworded"      // text changed, EARS shape unchanged
+	ChangeShapeChanged ChangeKind = "shape-changed" // EARS shape changed (the text changed too)
+)
+
+// Change is a requirement that differs between two snapshots, matched by ID.
+type Change struct {
+	Kind   ChangeKind     `json:"kind"`
+	ID     string         `json:"id"`
+	Before *SnapshotEntry `json:"before,omitempty"`
+	After  *SnapshotEntry `json:"after,omitempty"`
+}
+
+// Diff compares snapshots by requirement ID, ignoring moves and whitespace-only
+// edits. Changes follow b's order, with removals appended in a's order.
+func Diff(a, b Snapshot) []Change {
+	before := make(map[string]*SnapshotEntry, len(a.Requirements))
+	for i := range a.Requirements {
+		before[a.Requirements[i].ID] = &a.Requirements[i]
+	}
+	inB := make(map[string]bool, len(b.Requirements))
+	var out []Change
+	for i := range b.Requirements {
+		after := &b.Requirements[i]
+		inB[after.ID] = true
+		prev, ok := before[after.ID]
+		switch {
+		case !ok:
+			out = append(out, Change{Kind: ChangeAdded, ID: after.ID, After: after})
+		case prev.Shape != after.Shape:
+			out = append(out, Change{Kind: ChangeShapeChanged, ID: after.ID, Before: prev, After: after})
+		case prev.Hash != after.Hash:
+			out = append(out, Change{Kind: ChangeReworded, ID: after.ID, Before: prev, After: after})
+		}
+	}
+	for i := range a.Requirements {
+		if e := &a.Requirements[i]; !inB[e.ID] {
+			out = append(out, Change{Kind: ChangeRemoved, ID: e.ID, Before: e})
+		}
+	}
+	return out
+}
@@ -0,0 +1,82 @@
+package reqs
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const baselineDoc = `- **SR-001**: When a brief is requested, the system shall collect design docs. (Verification: Test)
+- **SR-002**: The system shall log runs.
+- **SR-003**: The system shall redact secrets.
+`
+
+func snapshot(name, doc string) Snapshot {
+	return NewSnapshot(name, ScanItems("20_requirements.md", doc), Scan("20_requirements.md", doc, true))
+}
+
+func TestDiff_ByID(t *testing.T) {
+	a := snapshot("v1", baselineDoc)
+	changed := strings.NewReplacer(
+		"collect design docs", "collect  design   docs", // whitespace only
+		"The system shall log runs.", "While in CI mode, the system shall log runs.",
+		"redact secrets", "redact secrets and tokens",
+	).Replace(baselineDoc)
+	changed = "- **SR-004**: The system shall sign briefs.\n" + strings.Replace(changed, "- **SR-001**", "\n- **SR-001**", 1)
+	changed = strings.Replace(changed, "- **SR-003**", "- **SR-005**", 1)
+	b := snapshot("v2", changed)
+
+	var got []string
+	for _, c := range Diff(a, b) {
+		got = append(got, string(c.Kind)+":"+c.ID)
+	}
+	want := "added:SR-004,shape-changed:SR-002,added:SR-005,removed:SR-003"
+	if strings.Join(got, ",") != want {
+		t.Fatalf("got %v, want %s", got, want)
+	}
+
+	// Rewording without a shape change
+	reworded := snapshot("v3", strings.Replace(baselineDoc, "log runs", "log every run", 1))
+	if ch := Diff(a, reworded); len(ch) != 1 || ch[0].Kind != ChangeReworded || ch[0].Before.Text == ch[0].After.Text {
+		t.Fatalf("unexpected changes: %+v", ch)
+	}
+}
+
+func TestSnapshot_RoundTripAndTamper(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "v1.json")
+	snap := snapshot("v1", baselineDoc)
+	if err := WriteSnapshot(path, snap); err != nil {
+		t.Fatal(err)
+	}
+	got, err := ReadSnapshot(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Hash != snap.Hash || len(got.Requirements) != 3 {
+		t.Fatalf("round trip mismatch: %+v", got)
+	}
+	data, _ := os.ReadFile(path)
+	if err := os.WriteFile(path, []byte(strings.Replace(string(data), "log runs", "log nothing", 1)), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadSnapshot(path); err == nil || !strings.Contains(err.Error(), "SR-002") {
+		t.Fatalf("expected tamper detection, got %v", err)
+	}
+}
+
+func TestSnapshot_KeepsItemsThatAreNotEARS(t *testing.T) {
+	doc := "- **N-001**: Users need briefs.\n- **CON-001**: Go 1.23 only.\n" + baselineDoc
+	snap := NewSnapshot("v1", ScanItems("10_needs.md", doc), Scan("10_needs.md", doc, false))
+	if len(snap.Requirements) != 5 || snap.Requirements[0].ID != "N-001" || snap.Requirements[1].ID != "CON-001" {
+		t.Fatalf("expected needs and constraints in the snapshot, got %+v", snap.Requirements)
+	}
+	if snap.Requirements[0].Shape != "" || snap.Requirements[2].Shape != "event-driven" {
+		t.Errorf("unexpected shapes: %q %q", snap.Requirements[0].Shape, snap.Requirements[2].Shape)
+	}
+	changed := strings.Replace(doc, "Go 1.23 only", "Go 1.24 only", 1)
+	next := NewSnapshot("v2", ScanItems("10_needs.md", changed), Scan("10_needs.md", changed, false))
+	if ch := Diff(snap, next); len(ch) != 1 || ch[0].ID != "CON-001" || ch[0].Kind != ChangeReworded {
+		t.Fatalf("expected CON-001 reworded, got %+v", ch)
+	}
+}
@@ -0,0 +1,188 @@
+package reqs
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/kelvin/tgsflow/src/core/ears"
+)
+
+// StaleTag marks a scenario whose requirement changed or was removed.
+const StaleTag = "@stale"
+
+// Scenario is a Given/When/Then rendering of an event-driven, unwanted or
+// complex requirement: preconditions become Given, the trigger When and the
+// response Then.
+type Scenario struct {
+	ID     string
+	System string
+	Given  []string
+	When   string
+	Then   string
+	// Hash fingerprints the requirement text; it is written as an @ears-<hash>
+	// tag so later changes to the requirement can be detected.
+	Hash string
+}
+
+// ScenarioFor converts r; ok is false for shapes without a trigger, invalid
+// statements and requirements without an ID.
+func ScenarioFor(r Requirement) (Scenario, bool) {
+	if r.ID == "" || !r.Valid() || r.Result.Trigger == "" {
+		return Scenario{}, false
+	}
+	switch r.Result.Shape {
+	case ears.ShapeEvent, ears.ShapeUnwanted, ears.ShapeComplex:
+	default:
+		return Scenario{}, false
+	}
+	modal := "shall"
+	if r.Result.Polarity == ears.PolarityNegative {
+		modal = "shall not"
+	}
+	return Scenario{
+		ID:     r.ID,
+		System: r.Result.System,
+		Given:  append([]string{}, r.Result.Preconditions...),
+		When:   r.Result.Trigger,
+		Then:   fmt.Sprintf("the %s %s %s", r.Result.System, modal, r.Result.Response),
+		Hash:   scenarioHash(r.Text),
+	}, true
+}
+
+func scenarioHash(text string) string {
+	sum := sha256.Sum256([]byte(normalizeText(text)))
+	return hex.EncodeToString(sum[:4])
+}
+
+// Render returns the tagged scenario block, indented for a Feature.
+func (s Scenario) Render() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "  @%s @ears-%s\n", s.ID, s.Hash)
+	fmt.Fprintf(&b, "  Scenario: %s %s\n", s.ID, s.When)
+	for i, g := range s.Given {
+		kw := "Given"
+		if i > 0 {
+			kw = "And"
+		}
+		fmt.Fprintf(&b, "    %s %s\n", kw, g)
+	}
+	fmt.Fprintf(&b, "    When %s\n", s.When)
+	fmt.Fprintf(&b, "    Then %s\n", s.Then)
+	return b.String()
+}
+
+// StaleScenario reports a scenario that no longer matches its requirement.
+type StaleScenario struct {
+	ID      string `json:"id"`
+	Line    int    `json:"line"`
+	Removed bool   `json:"removed"` // the requirement no longer exists
+}
+
+var (
+	featureIDTagRe   = regexp.MustCompile(`^@([A-Za-z][A-Za-z0-9]*-[0-9][A-Za-z0-9.]*)$`)
+	featureHashTagRe = regexp.MustCompile(`^@ears-([0-9a-f]+)$`)
+)
+
+type featureBlock struct {
+	id, hash string
+	line     int // 1-based line of the tag line (or Scenario line)
+	lines    []string
+}
+
+// splitFeature separates a .feature document into its header and scenario
+// blocks; a block starts at the tag line preceding "Scenario:".
+func splitFeature(content string) (header []string, blocks []*featureBlock) {
+	lines := strings.Split(strings.TrimRight(content, "\n"), "\n")
+	var cur *featureBlock
+	for i := 0; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		start := strings.HasPrefix(trimmed, "Scenario") ||
+			(strings.HasPrefix(trimmed, "@") && i+1 < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i+1]), "Scenario"))
+		if start {
+			cur = &featureBlock{line: i + 1}
+			blocks = append(blocks, cur)
+			if strings.HasPrefix(trimmed, "@") {
+				for _, tag := range strings.Fields(trimmed) {
+					if m := featureIDTagRe.FindStringSubmatch(tag); m != nil && cur.id == "" {
+						cur.id = m[1]
+					}
+					if m := featureHashTagRe.FindStringSubmatch(tag); m != nil {
+						cur.hash = m[1]
+					}
+				}
+				cur.lines = append(cur.lines, lines[i], lines[i+1])
+				i++
+				continue
+			}
+		}
+		if cur == nil {
+			header = append(header, lines[i])
+		} else {
+			cur.lines = append(cur.lines, lines[i])
+		}
+	}
+	return header, blocks
+}
+
+// SyncFeature merges scenarios into an existing .feature document (empty for a
+// new file). Scenarios whose requirement is unchanged are kept verbatim so hand
+// edits survive; new requirements are appended. Changed or removed requirements
+// are tagged @stale, or regenerated/dropped when update is set.
+func SyncFeature(existing, system string, scenarios []Scenario, update bool) (string, []StaleScenario) {
+	if strings.TrimSpace(existing) == "" {
+		existing = fmt.Sprintf("Feature: %s\n  Scenarios generated from EARS requirements by `tgs req gherkin`.\n", system)
+	}
+	header, blocks := splitFeature(existing)
+	byID := make(map[string]*featureBlock)
+	for _, b := range blocks {
+		if b.id != "" {
+			byID[b.id] = b
+		}
+	}
+	want := make(map[string]bool, len(scenarios))
+	var stale []StaleScenario
+	var added []string
+	for _, s := range scenarios {
+		want[s.ID] = true
+		b, ok := byID[s.ID]
+		switch {
+		case !ok:
+			added = append(added, s.Render())
+		case b.hash == s.Hash:
+		case update:
+			b.lines = strings.Split(strings.TrimRight(s.Render(), "\n"), "\n")
+			b.lines = append(b.lines, "")
+		default:
+			markStale(b)
+			stale = append(stale, StaleScenario{ID: s.ID, Line: b.line})
+		}
+	}
+	var out []string
+	out = append(out, header...)
+	for _, b := range blocks {
+		if b.id != "" && !want[b.id] {
+			if update {
+				continue
+			}
+			markStale(b)
+			stale = append(stale, StaleScenario{ID: b.id, Line: b.line, Removed: true})
+		}
+		out = append(out, b.lines...)
+	}
+	text := strings.TrimRight(strings.Join(out, "\n"), "\n") + "\n"
+	for _, a := range added {
+		text += "\n" + a
+	}
+	return text, stale
+}
+
+func markStale(b *featureBlock) {
+	tags := b.lines[0]
+	if !strings.HasPrefix(strings.TrimSpace(tags), "@") || strings.Contains(" "+tags+" ", " "+StaleTag+" ") {
+		return
+	}
+	b.lines[0] = tags + " " + StaleTag
+}
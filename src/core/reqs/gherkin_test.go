@@ -0,0 +1,67 @@
+package reqs
+
+import (
+	"strings"
+	"testing"
+)
+
+const gherkinDoc = `- **SR-001**: While armed, when motion is detected, the alarm shall sound the siren.
+- **SR-002**: If the battery is low, then the alarm shall not disarm.
+- **SR-003**: The alarm shall log events.
+`
+
+func scenarios(t *testing.T, doc string) []Scenario {
+	t.Helper()
+	var out []Scenario
+	for _, r := range Scan("20_requirements.md", doc, true) {
+		if s, ok := ScenarioFor(r); ok {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func TestScenarioFor_Mapping(t *testing.T) {
+	got := scenarios(t, gherkinDoc)
+	if len(got) != 2 {
+		t.Fatalf("expected scenarios for the complex and unwanted requirements only, got %+v", got)
+	}
+	want := "  @SR-001 @ears-" + got[0].Hash + "\n" +
+		"  Scenario: SR-001 motion is detected\n" +
+		"    Given armed\n" +
+		"    When motion is detected\n" +
+		"    Then the alarm shall sound the siren\n"
+	if r := got[0].Render(); r != want {
+		t.Fatalf("unexpected scenario:\n%s", r)
+	}
+	if got[1].When != "the battery is low" || got[1].Then != "the alarm shall not disarm" {
+		t.Fatalf("unexpected unwanted scenario: %+v", got[1])
+	}
+}
+
+func TestSyncFeature_StaleAndUpdate(t *testing.T) {
+	first, stale := SyncFeature("", "alarm", scenarios(t, gherkinDoc), false)
+	if len(stale) != 0 || !strings.HasPrefix(first, "Feature: alarm\n") || strings.Count(first, "Scenario:") != 2 {
+		t.Fatalf("unexpected feature:\n%s", first)
+	}
+	// Hand edits to an unchanged scenario survive regeneration
+	edited := strings.Replace(first, "Given armed", "Given the alarm is armed", 1)
+	if again, stale := SyncFeature(edited, "alarm", scenarios(t, gherkinDoc), false); again != edited || len(stale) != 0 {
+		t.Fatalf("expected idempotent sync, got stale=%v:\n%s", stale, again)
+	}
+
+	changed := strings.Replace(gherkinDoc, "sound the siren", "sound the siren within 2 s", 1)
+	changed = strings.Replace(changed, "- **SR-002**", "- **SR-004**", 1)
+	out, stale := SyncFeature(edited, "alarm", scenarios(t, changed), false)
+	if len(stale) != 2 || stale[0].ID != "SR-001" || stale[0].Line != 4 || !stale[1].Removed {
+		t.Fatalf("unexpected stale report: %+v", stale)
+	}
+	if !strings.Contains(out, "@SR-001 @ears-"+scenarios(t, gherkinDoc)[0].Hash+" @stale") || !strings.Contains(out, "@SR-004") {
+		t.Fatalf("expected stale tags and new scenario:\n%s", out)
+	}
+
+	updated, stale := SyncFeature(out, "alarm", scenarios(t, changed), true)
+	if len(stale) != 0 || strings.Contains(updated, "@stale") || strings.Contains(updated, "SR-002") || !strings.Contains(updated, "within 2 s") {
+		t.Fatalf("expected regenerated feature:\n%s", updated)
+	}
+}
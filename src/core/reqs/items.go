@@ -0,0 +1,96 @@
+package reqs
+
+import (
+	"strings"
+)
+
+// Item is any ID'd bullet in a design document (needs, requirements, components),
+// captured whether or not its text is valid EARS.
+type Item struct {
+	ID           string   `json:"id"`
+	Path         string   `json:"path"`
+	Line         int      `json:"line"`
+	Text         string   `json:"text"`
+	Verification string   `json:"verification,omitempty"`
+	Parents      []string `json:"parents,omitempty"`
+	AllocatedTo  []string `json:"allocated_to,omitempty"`
+}
+
+// ScanItems returns the "- **ID**: text" bullets of a Markdown document outside code fences.
+func ScanItems(path, content string) []Item {
+	var out []Item
+	inFence := false
+	for i, ln := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(ln)
+		if strings.HasPrefix(trimmed, "```") {
+			inFence = !inFence
+			continue
+		}
+		if inFence || !(strings.HasPrefix(trimmed, "- ") || strings.HasPrefix(trimmed, "* ")) {
+			continue
+		}
+		id, rest := splitID(strings.TrimSpace(trimmed[2:]))
+		if id == "" {
+			continue
+		}
+		it := Item{ID: id, Path: path, Line: i + 1}
+		it.Parents, rest = splitTraces(rest)
+		it.AllocatedTo, rest = splitAllocation(rest)
+		it.Text, it.Verification = splitVerification(rest)
+		out = append(out, it)
+	}
+	return out
+}
+
+// FormatItem renders a new requirement bullet in the style of the design docs:
+//
+//   - **SR-001**: The system shall log runs. (Traces: N-001) (Verification: Test)
+func FormatItem(id, text, verification string, parents []string) string {
+	line := "- **" + id + "**: " + strings.TrimSuffix(strings.TrimSpace(text), ".") + "."
+	if len(parents) > 0 {
+		line += " (Traces: " + strings.Join(parents, ", ") + ")"
+	}
+	if verification != "" {
+		line += " (Verification: " + verification + ")"
+	}
+	return line
+}
+
+// RewriteItem updates the text, verification method and parents of the bullet
+// line that it was scanned from, touching only the parts that changed so that
+// indentation, emphasis and annotation placement are preserved.
+func RewriteItem(line string, it Item, text, verification string, parents []string) string {
+	if strings.Join(strings.Fields(text), " ") != strings.Join(strings.Fields(it.Text), " ") {
+		idx := strings.Index(line, it.Text)
+		if it.Text == "" || idx < 0 {
+			indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+			return indent + FormatItem(it.ID, text, verification, parents)
+		}
+		line = line[:idx] + strings.TrimSuffix(strings.TrimSpace(text), ".") + line[idx+len(it.Text):]
+	}
+	if strings.Join(parents, ",") != strings.Join(it.Parents, ",") {
+		annotation := ""
+		if len(parents) > 0 {
+			annotation = " (Traces: " + strings.Join(parents, ", ") + ")"
+		}
+		if loc := tracesRe.FindStringIndex(line); loc != nil {
+			line = line[:loc[0]] + annotation + line[loc[1]:]
+		} else if loc := verificationRe.FindStringIndex(line); loc != nil {
+			line = line[:loc[0]] + annotation + line[loc[0]:]
+		} else {
+			line = strings.TrimRight(line, " ") + annotation
+		}
+	}
+	if verification != it.Verification {
+		if m := verificationRe.FindStringSubmatchIndex(line); m != nil {
+			if verification == "" {
+				line = line[:m[0]]
+			} else {
+				line = line[:m[2]] + verification + line[m[3]:]
+			}
+		} else if verification != "" {
+			line = strings.TrimRight(line, " ") + " (Verification: " + verification + ")"
+		}
+	}
+	return line
+}
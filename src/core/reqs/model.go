@@ -0,0 +1,252 @@
+package reqs
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/kelvin/tgsflow/src/core/ears"
+)
+
+// Transition is a behaviour implied by one requirement: in state From, on Event,
+// the system ends up in To. From is empty for events that apply in any state and
+// To equals From when the response does not name a target state.
+type Transition struct {
+	From  string `json:"from"`
+	Event string `json:"event"`
+	To    string `json:"to"`
+	Ref   string `json:"ref"`
+}
+
+// Element is a state or event with the requirements that mention it.
+type Element struct {
+	Name string   `json:"name"`
+	Refs []string `json:"refs"`
+}
+
+// SystemModel is the state/event catalogue for a single system.
+type SystemModel struct {
+	System      string       `json:"system"`
+	States      []Element    `json:"states"`
+	Events      []Element    `json:"events"`
+	Transitions []Transition `json:"transitions"`
+	Findings    []string     `json:"findings"`
+}
+
+// Model aggregates SystemModels across a requirement set, sorted by system name.
+type Model struct {
+	Systems []SystemModel `json:"systems"`
+}
+
+var enterRe = regexp.MustCompile(`(?i)\b(?:enter|enters|transition(?:s)? to|switch(?:es)? to|change(?:s)? to|move(?:s)? to|return(?:s)? to|go(?:es)? to|remain(?:s)? in|stay(?:s)? in)\s+(.+)$`)
+
+// BuildModel collects states (preconditions) and events (triggers) per system and
+// derives transitions from responses such as "shall enter <state>". Findings flag
+// events used by a single requirement and states that no requirement enters.
+func BuildModel(list []Requirement) Model {
+	type acc struct {
+		states      map[string]*Element
+		events      map[string]*Element
+		entered     map[string]bool
+		transitions []Transition
+	}
+	bySystem := make(map[string]*acc)
+	get := func(sys string) *acc {
+		if a, ok := bySystem[sys]; ok {
+			return a
+		}
+		a := &acc{states: map[string]*Element{}, events: map[string]*Element{}, entered: map[string]bool{}}
+		bySystem[sys] = a
+		return a
+	}
+	add := func(m map[string]*Element, name, ref string) string {
+		key := NormalizePhrase(name)
+		if key == "" {
+			return ""
+		}
+		e, ok := m[key]
+		if !ok {
+			e = &Element{Name: key}
+			m[key] = e
+		}
+		e.Refs = append(e.Refs, ref)
+		return key
+	}
+
+	for _, r := range list {
+		if !r.Valid() {
+			continue
+		}
+		res := r.Result
+		sys := systemName(res)
+		a := get(sys)
+		ref := r.Ref()
+		var froms []string
+		for _, pc := range res.Preconditions {
+			if k := add(a.states, pc, ref); k != "" {
+				froms = append(froms, k)
+			}
+		}
+		event := ""
+		if res.Trigger != "" {
+			event = add(a.events, res.Trigger, ref)
+		}
+		target := ""
+		if m := enterRe.FindStringSubmatch(res.Response); m != nil && res.Polarity != ears.PolarityNegative {
+			target = NormalizePhrase(m[1])
+			if target != "" {
+				if _, ok := a.states[target]; !ok {
+					a.states[target] = &Element{Name: target}
+				}
+				a.entered[target] = true
+			}
+		}
+		if event == "" && target == "" {
+			continue
+		}
+		if len(froms) == 0 {
+			froms = []string{""}
+		}
+		for _, from := range froms {
+			to := target
+			if to == "" {
+				to = from
+			}
+			if to == "" {
+				// event without state context or target: nothing to draw
+				continue
+			}
+			a.transitions = append(a.transitions, Transition{From: from, Event: event, To: to, Ref: ref})
+		}
+	}
+
+	var model Model
+	for sys, a := range bySystem {
+		sm := SystemModel{System: sys, States: sortedElements(a.states), Events: sortedElements(a.events), Transitions: a.transitions}
+		for _, ev := range sm.Events {
+			if len(ev.Refs) == 1 {
+				sm.Findings = append(sm.Findings, fmt.Sprintf("event %q is used only once (%s)", ev.Name, ev.Refs[0]))
+			}
+		}
+		for _, st := range sm.States {
+			if !a.entered[st.Name] && !enteredBySimilar(st.Name, a.entered) {
+				sm.Findings = append(sm.Findings, fmt.Sprintf("state %q is never entered by any requirement", st.Name))
+			}
+		}
+		model.Systems = append(model.Systems, sm)
+	}
+	sort.Slice(model.Systems, func(i, j int) bool { return model.Systems[i].System < model.Systems[j].System })
+	return model
+}
+
+// enteredBySimilar treats "armed mode" and "armed" as the same state.
+func enteredBySimilar(state string, entered map[string]bool) bool {
+	for e := range entered {
+		if strings.TrimSuffix(strings.TrimSuffix(e, " mode"), " state") == strings.TrimSuffix(strings.TrimSuffix(state, " mode"), " state") {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedElements(m map[string]*Element) []Element {
+	out := make([]Element, 0, len(m))
+	for _, e := range m {
+		out = append(out, *e)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// systemName normalizes the parsed system; pronoun subjects are grouped under "it".
+func systemName(res ears.Result) string {
+	s := strings.ReplaceAll(res.System, "`", "")
+	s = strings.ToLower(strings.Join(strings.Fields(s), " "))
+	if s == "" {
+		return "it"
+	}
+	return s
+}
+
+// NormalizePhrase lowercases a clause, collapses whitespace and strips leading
+// articles and "in" so that "in the armed mode" and "armed mode" compare equal.
+func NormalizePhrase(s string) string {
+	s = strings.ToLower(strings.Join(strings.Fields(s), " "))
+	s = strings.TrimRight(s, ".;:")
+	for _, p := range []string{"in the ", "in ", "the ", "a ", "an "} {
+		if strings.HasPrefix(s, p) {
+			s = strings.TrimPrefix(s, p)
+			break
+		}
+	}
+	s = strings.TrimPrefix(s, "the ")
+	return strings.TrimSpace(s)
+}
+
+// Mermaid renders the system's behaviour as a Mermaid stateDiagram-v2.
+func (sm SystemModel) Mermaid() string {
+	var b strings.Builder
+	b.WriteString("stateDiagram-v2\n")
+	fmt.Fprintf(&b, "    %%%% %s\n", sm.System)
+	ids := stateIDs(sm.States)
+	for _, st := range sm.States {
+		fmt.Fprintf(&b, "    state \"%s\" as %s\n", escapeLabel(st.Name), ids[st.Name])
+	}
+	for _, t := range sm.Transitions {
+		from := "[*]"
+		if t.From != "" {
+			from = ids[t.From]
+		}
+		fmt.Fprintf(&b, "    %s --> %s%s\n", from, ids[t.To], transitionLabel(t))
+	}
+	return b.String()
+}
+
+// PlantUML renders the system's behaviour as a PlantUML state diagram.
+func (sm SystemModel) PlantUML() string {
+	var b strings.Builder
+	b.WriteString("@startuml\n")
+	fmt.Fprintf(&b, "title %s\n", sm.System)
+	ids := stateIDs(sm.States)
+	for _, st := range sm.States {
+		fmt.Fprintf(&b, "state \"%s\" as %s\n", escapeLabel(st.Name), ids[st.Name])
+	}
+	for _, t := range sm.Transitions {
+		from := "[*]"
+		if t.From != "" {
+			from = ids[t.From]
+		}
+		fmt.Fprintf(&b, "%s --> %s%s\n", from, ids[t.To], transitionLabel(t))
+	}
+	b.WriteString("@enduml\n")
+	return b.String()
+}
+
+func transitionLabel(t Transition) string {
+	parts := []string{}
+	if t.Event != "" {
+		parts = append(parts, escapeLabel(t.Event))
+	}
+	if t.Ref != "" {
+		parts = append(parts, "["+escapeLabel(t.Ref)+"]")
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return " : " + strings.Join(parts, " ")
+}
+
+func stateIDs(states []Element) map[string]string {
+	ids := make(map[string]string, len(states))
+	for i, st := range states {
+		ids[st.Name] = fmt.Sprintf("s%d", i+1)
+	}
+	return ids
+}
+
+func escapeLabel(s string) string {
+	s = strings.ReplaceAll(s, "\"", "'")
+	s = strings.ReplaceAll(s, ":", " ")
+	return strings.ReplaceAll(s, "`", "")
+}
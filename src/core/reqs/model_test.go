@@ -0,0 +1,83 @@
+package reqs
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kelvin/tgsflow/src/core/ears"
+)
+
+const modelDoc = `- **SR-001**: While idle, when the arm button is pressed, the alarm shall enter armed mode.
+- **SR-002**: While in armed mode, when motion is detected, the alarm shall sound the siren.
+- **SR-003**: While in armed mode, when the disarm code is entered, the alarm shall return to idle.
+- **SR-004**: When the arm button is pressed, the alarm shall beep once.
+- **SR-005**: While in maintenance mode, the alarm shall suppress the siren.
+- **SR-006**: The logger shall record events.
+`
+
+func TestBuildModel_CatalogueAndFindings(t *testing.T) {
+	model := BuildModel(Scan("20_requirements.md", modelDoc, true))
+	if len(model.Systems) != 2 {
+		t.Fatalf("expected 2 systems, got %+v", model.Systems)
+	}
+	alarm := model.Systems[0]
+	if alarm.System != "alarm" {
+		t.Fatalf("expected systems sorted with alarm first, got %q", alarm.System)
+	}
+	states := map[string]bool{}
+	for _, st := range alarm.States {
+		states[st.Name] = true
+	}
+	for _, want := range []string{"idle", "armed mode", "maintenance mode"} {
+		if !states[want] {
+			t.Fatalf("missing state %q in %+v", want, alarm.States)
+		}
+	}
+	for _, ev := range alarm.Events {
+		if ev.Name == "arm button is pressed" && len(ev.Refs) != 2 {
+			t.Fatalf("expected arm button event used twice, got %+v", ev)
+		}
+	}
+	joined := strings.Join(alarm.Findings, "\n")
+	if !strings.Contains(joined, `state "maintenance mode" is never entered`) {
+		t.Fatalf("expected maintenance mode to be flagged, got:\n%s", joined)
+	}
+	if strings.Contains(joined, `state "armed mode"`) || strings.Contains(joined, `state "idle"`) {
+		t.Fatalf("entered states must not be flagged, got:\n%s", joined)
+	}
+	if !strings.Contains(joined, `event "motion is detected" is used only once (SR-002)`) {
+		t.Fatalf("expected single-use event finding, got:\n%s", joined)
+	}
+	if strings.Contains(joined, `event "arm button is pressed"`) {
+		t.Fatalf("event used twice must not be flagged, got:\n%s", joined)
+	}
+}
+
+func TestBuildModel_Diagrams(t *testing.T) {
+	model := BuildModel(Scan("20_requirements.md", modelDoc, true))
+	alarm := model.Systems[0]
+	mmd := alarm.Mermaid()
+	if !strings.HasPrefix(mmd, "stateDiagram-v2\n") || !strings.Contains(mmd, `state "armed mode" as`) {
+		t.Fatalf("unexpected mermaid:\n%s", mmd)
+	}
+	if !strings.Contains(mmd, "--> ") || !strings.Contains(mmd, "[SR-001]") {
+		t.Fatalf("expected labelled transitions in mermaid:\n%s", mmd)
+	}
+	puml := alarm.PlantUML()
+	if !strings.HasPrefix(puml, "@startuml\n") || !strings.HasSuffix(puml, "@enduml\n") {
+		t.Fatalf("unexpected plantuml:\n%s", puml)
+	}
+}
+
+func TestSystemName_StripsInnerBackticks(t *testing.T) {
+	for in, want := range map[string]string{
+		"`verify` command":  "verify command",
+		"the `tgs`  CLI":    "the tgs cli",
+		"`Payment Service`": "payment service",
+		"it":                "it",
+	} {
+		if got := systemName(ears.Result{System: in}); got != want {
+			t.Fatalf("systemName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,254 @@
+package reqs
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/kelvin/tgsflow/src/core/config"
+	"github.com/kelvin/tgsflow/src/core/ears"
+)
+
+// Requirement is an EARS-shaped statement captured from a Markdown document.
+type Requirement struct {
+	ID           string   // e.g. SR-001; empty when the line has no bold ID prefix
+	Path         string   // repo-relative path of the source document
+	Line         int      // 1-based line number
+	Raw          string   // candidate text as linted (ID prefix removed)
+	Text         string   // requirement sentence without verification annotation or trailing period
+	Verification string   // e.g. Test, Inspection; empty when not annotated
+	Language     string   // EARS keyword profile used to parse the line; "en" by default
+	Parents      []string // IDs from a "(Traces: N-001, N-002)" annotation
+	AllocatedTo  []string // component IDs from an "(Allocated-To: C-001)" annotation
+	Result       ears.Result
+	Err          error // parse error; nil when the statement is valid EARS
+}
+
+// Valid reports whether the requirement parsed as an allowed EARS form.
+func (r Requirement) Valid() bool { return r.Err == nil }
+
+// Ref returns a stable reference for messages: the ID if present, else path:line.
+func (r Requirement) Ref() string {
+	if r.ID != "" {
+		return r.ID
+	}
+	return fmt.Sprintf("%s:%d", r.Path, r.Line)
+}
+
+var (
+	idPrefixRe     = regexp.MustCompile(`^\*\*([A-Za-z][A-Za-z0-9]*-[0-9][A-Za-z0-9.]*)\*\*`)
+	verificationRe = regexp.MustCompile(`\s*\((?i:verification):\s*([^)]*)\)\s*\.?\s*$`)
+	tracesRe       = regexp.MustCompile(`\s*\((?i:traces|traces to|parent|parents):\s*([^)]*)\)`)
+	allocatedRe    = regexp.MustCompile(`\s*\((?i:allocated-to|allocated to):\s*([^)]*)\)`)
+)
+
+// DefaultPaths returns the documents to scan: the configured EARS paths or the design defaults.
+func DefaultPaths(cfg config.Config) []string {
+	if len(cfg.Guardrails.EARS.Paths) > 0 {
+		return append([]string{}, cfg.Guardrails.EARS.Paths...)
+	}
+	return []string{"tgs/design/10_needs.md", "tgs/design/20_requirements.md"}
+}
+
+// IsRequirementsDoc reports whether rel is a requirements document, where EARS-shaped
+// lines are linted even without 'shall' so that a missing modal is surfaced.
+func IsRequirementsDoc(rel string) bool { return strings.HasSuffix(rel, "20_requirements.md") }
+
+// DefaultLanguage returns the configured EARS keyword profile (guardrails.ears.language).
+func DefaultLanguage(cfg config.Config) string {
+	if l := strings.TrimSpace(cfg.Guardrails.EARS.Language); l != "" {
+		return l
+	}
+	return "en"
+}
+
+// CacheDir returns the repo-relative directory for the parse cache: a "cache"
+// directory under telemetry.log_dir, or .tgs/cache when no log dir is configured.
+func CacheDir(cfg config.Config) string {
+	if d := strings.TrimSpace(cfg.Telemetry.LogDir); d != "" {
+		return filepath.Join(d, "cache")
+	}
+	return filepath.Join(".tgs", "cache")
+}
+
+// Load reads and scans each repo-relative path, parsing on a worker pool. Unreadable
+// files are returned as errors alongside whatever requirements could be collected
+// from the others. lang is the keyword profile for documents without an
+// ears-language marker; names are known system names such as glossary terms.
+func Load(repoRoot string, paths []string, lang string, names []string) ([]Requirement, []error) {
+	var (
+		out    []Requirement
+		errs   []error
+		linter = &ears.Linter{Names: names}
+	)
+	for _, rel := range paths {
+		data, err := os.ReadFile(filepath.Join(repoRoot, rel))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("cannot read %s: %w", rel, err))
+			continue
+		}
+		out = append(out, ScanWith(linter, rel, string(data), IsRequirementsDoc(rel), lang)...)
+	}
+	return out, errs
+}
+
+// Scan extracts EARS candidates from Markdown content, ignoring code fences and
+// bullet lists that continue a preceding "... shall:" statement. When strict is
+// false (needs documents), only lines containing 'shall' are captured.
+func Scan(path, content string, strict bool) []Requirement {
+	return ScanLanguage(path, content, strict, "en")
+}
+
+// ScanLanguage is Scan with a default keyword profile; an `<!-- ears-language: xx -->`
+// marker in the document takes precedence.
+func ScanLanguage(path, content string, strict bool, lang string) []Requirement {
+	return ScanWith(nil, path, content, strict, lang)
+}
+
+// ScanWith is ScanLanguage parsing the captured lines with linter, which may run
+// on a worker pool, consult a content-hash cache and keep the linter's known
+// system names whole. A nil linter parses inline.
+func ScanWith(linter *ears.Linter, path, content string, strict bool, lang string) []Requirement {
+	if l := ears.DetectLanguage(content); l != "" {
+		lang = l
+	}
+	if lang == "" {
+		lang = "en"
+	}
+	profile, _ := ears.LookupLanguage(lang)
+	hasStarter, hasShall := hasStarter, hasShall
+	if profile != nil {
+		hasStarter, hasShall = profile.HasStarter, profile.HasModal
+	}
+	var out []Requirement
+	lines := strings.Split(content, "\n")
+	inFence := false
+	bulletResponseMode := false
+	for i, ln := range lines {
+		trimmed := strings.TrimSpace(ln)
+		if strings.HasPrefix(trimmed, "```") {
+			inFence = !inFence
+			continue
+		}
+		if inFence || trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			if trimmed == "" {
+				bulletResponseMode = false
+			}
+			continue
+		}
+		isBullet := strings.HasPrefix(trimmed, "- ") || strings.HasPrefix(trimmed, "* ") || strings.HasPrefix(trimmed, "1. ")
+		if isBullet && bulletResponseMode {
+			continue
+		}
+		if !isBullet {
+			if !hasStarter(trimmed) {
+				continue
+			}
+			if !strict && !hasShall(trimmed) {
+				continue
+			}
+			out = append(out, newRequirement(path, i+1, "", trimmed, lang))
+			bulletResponseMode = strings.HasSuffix(trimmed, ":") && hasShall(trimmed)
+			continue
+		}
+		id, candidate := splitID(strings.TrimSpace(trimmed[2:]))
+		if !hasStarter(candidate) {
+			continue
+		}
+		if !strict && !hasShall(candidate) {
+			continue
+		}
+		out = append(out, newRequirement(path, i+1, id, candidate, lang))
+	}
+	texts := make([]string, len(out))
+	for i := range out {
+		texts[i] = out[i].Text
+	}
+	for i, p := range linter.ParseAll(lang, texts) {
+		out[i].Result, out[i].Err = p.Result, p.Err
+	}
+	return out
+}
+
+func newRequirement(path string, line int, id, candidate, lang string) Requirement {
+	r := Requirement{ID: id, Path: path, Line: line, Raw: candidate, Language: lang}
+	r.Parents, candidate = splitTraces(candidate)
+	r.AllocatedTo, candidate = splitAllocation(candidate)
+	r.Text, r.Verification = splitVerification(candidate)
+	return r
+}
+
+func hasStarter(s string) bool {
+	upper := strings.ToUpper(s)
+	return strings.HasPrefix(upper, "WHEN ") || strings.HasPrefix(upper, "WHILE ") || strings.HasPrefix(upper, "IF ") || strings.HasPrefix(upper, "THE ")
+}
+
+func hasShall(s string) bool { return strings.Contains(strings.ToLower(s), " shall") }
+
+// splitID strips a leading bold ID marker like **SR-001**: and returns the ID (if it
+// looks like one) and the remaining text.
+func splitID(s string) (string, string) {
+	if !strings.HasPrefix(s, "**") {
+		return "", s
+	}
+	id := ""
+	if m := idPrefixRe.FindStringSubmatch(s); m != nil {
+		id = m[1]
+	}
+	if idx := strings.Index(s[2:], "**:"); idx >= 0 {
+		return id, strings.TrimSpace(s[2+idx+3:])
+	}
+	if end := strings.Index(s[2:], "**"); end >= 0 {
+		rest := strings.TrimPrefix(s[2+end+2:], ":")
+		return id, strings.TrimSpace(rest)
+	}
+	return id, s
+}
+
+// splitTraces removes "(Traces: N-001, N-002)" annotations and returns the parent IDs.
+func splitTraces(s string) ([]string, string) {
+	var parents []string
+	for _, m := range tracesRe.FindAllStringSubmatch(s, -1) {
+		for _, id := range strings.FieldsFunc(m[1], func(r rune) bool { return r == ',' || r == ';' || r == ' ' }) {
+			parents = append(parents, id)
+		}
+	}
+	return parents, tracesRe.ReplaceAllString(s, "")
+}
+
+// splitAllocation removes "(Allocated-To: C-001)" annotations and returns the component IDs.
+func splitAllocation(s string) ([]string, string) {
+	var ids []string
+	for _, m := range allocatedRe.FindAllStringSubmatch(s, -1) {
+		ids = append(ids, strings.FieldsFunc(m[1], func(r rune) bool { return r == ',' || r == ';' || r == ' ' })...)
+	}
+	return ids, allocatedRe.ReplaceAllString(s, "")
+}
+
+// splitVerification removes a trailing "(Verification: X)" annotation and period.
+func splitVerification(s string) (string, string) {
+	method := ""
+	if m := verificationRe.FindStringSubmatchIndex(s); m != nil {
+		method = canonicalVerification(strings.TrimSpace(s[m[2]:m[3]]))
+		s = s[:m[0]]
+	}
+	s = strings.TrimSpace(s)
+	s = strings.TrimSuffix(s, ".")
+	return strings.TrimSpace(s), method
+}
+
+// verificationMethods are the standard V&V methods in their canonical spelling.
+var verificationMethods = []string{"Test", "Analysis", "Inspection", "Demonstration"}
+
+// canonicalVerification spells a standard method the canonical way ("test"
+// becomes "Test"); other methods are returned as written.
+func canonicalVerification(method string) string {
+	for _, m := range verificationMethods {
+		if strings.EqualFold(method, m) {
+			return m
+		}
+	}
+	return method
+}
@@ -0,0 +1,122 @@
+package reqs
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestScan_RequirementsDoc(t *testing.T) {
+	doc := "# System Requirements\n\n" +
+		"- **SR-001**: When a thought is created, the system shall scaffold `README.md`. (Verification: Test)\n" +
+		"- **SR-002**: The system must validate input.\n" +
+		"- Narrative bullet is ignored\n\n" +
+		"```\n- **SR-999**: The system shall be ignored in fences.\n```\n\n" +
+		"The system shall produce reports:\n" +
+		"- summary\n" +
+		"- details\n"
+	list := Scan("tgs/design/20_requirements.md", doc, true)
+	if len(list) != 3 {
+		t.Fatalf("expected 3 candidates, got %d: %+v", len(list), list)
+	}
+	first := list[0]
+	if first.ID != "SR-001" || first.Line != 3 || first.Verification != "Test" {
+		t.Fatalf("unexpected first requirement: %+v", first)
+	}
+	if first.Text != "When a thought is created, the system shall scaffold `README.md`" {
+		t.Fatalf("unexpected text: %q", first.Text)
+	}
+	if !first.Valid() {
+		t.Fatalf("expected SR-001 to be valid: %v", first.Err)
+	}
+	if list[1].ID != "SR-002" || list[1].Valid() {
+		t.Fatalf("expected SR-002 to be captured and invalid: %+v", list[1])
+	}
+	if list[2].Ref() != "tgs/design/20_requirements.md:11" {
+		t.Fatalf("expected path:line ref for unnumbered requirement, got %q", list[2].Ref())
+	}
+}
+
+func TestScan_NeedsDocRequiresShall(t *testing.T) {
+	doc := "- **N-001**: While planning, the Team needs a design doc.\n- **N-002**: While planning, the team shall document needs.\n"
+	list := Scan("tgs/design/10_needs.md", doc, false)
+	if len(list) != 1 || list[0].ID != "N-002" {
+		t.Fatalf("expected only N-002, got %+v", list)
+	}
+}
+
+func TestLoad_ReportsMissingFiles(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "tgs", "design"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "tgs", "design", "20_requirements.md"), []byte("- **SR-001**: The system shall log.\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	list, errs := Load(dir, []string{"tgs/design/10_needs.md", "tgs/design/20_requirements.md"}, "en", nil)
+	if len(errs) != 1 {
+		t.Fatalf("expected 1 error for missing needs doc, got %v", errs)
+	}
+	if len(list) != 1 || list[0].Path != "tgs/design/20_requirements.md" {
+		t.Fatalf("unexpected requirements: %+v", list)
+	}
+}
+
+func TestScanLanguage_MarkerOverridesDefault(t *testing.T) {
+	doc := "<!-- ears-language: de -->\n# Anforderungen\n\n- **SR-001**: Wenn der Knopf gedrückt wird, soll das System den Alarm auslösen. (Verification: Test)\n- Dies ist keine Anforderung.\n"
+	list := ScanLanguage("20_requirements.md", doc, true, "en")
+	if len(list) != 1 {
+		t.Fatalf("expected 1 requirement, got %+v", list)
+	}
+	r := list[0]
+	if !r.Valid() || r.Language != "de" || r.ID != "SR-001" || r.Result.Trigger != "der Knopf gedrückt wird" {
+		t.Fatalf("unexpected requirement: %+v (err=%v)", r, r.Err)
+	}
+}
+
+func TestScanItems_TracesAndVerification(t *testing.T) {
+	doc := "- **N-001**: The team needs traceability.\n- **SR-001**: The system shall link thoughts. (Traces: N-001, N-002) (Verification: Test)\n```\n- **SR-999**: ignored\n```\n- plain bullet\n"
+	items := ScanItems("20_requirements.md", doc)
+	if len(items) != 2 {
+		t.Fatalf("expected 2 items, got %+v", items)
+	}
+	sr := items[1]
+	if sr.ID != "SR-001" || sr.Text != "The system shall link thoughts" || sr.Verification != "Test" {
+		t.Fatalf("unexpected item: %+v", sr)
+	}
+	if len(sr.Parents) != 2 || sr.Parents[0] != "N-001" || sr.Parents[1] != "N-002" {
+		t.Fatalf("unexpected parents: %v", sr.Parents)
+	}
+	reqs := Scan("20_requirements.md", doc, false)
+	if len(reqs) != 1 || !reqs[0].Valid() || len(reqs[0].Parents) != 2 {
+		t.Fatalf("expected traced requirement to stay valid EARS, got %+v", reqs)
+	}
+}
+
+func TestScanItems_CanonicalVerification(t *testing.T) {
+	doc := "- **SR-001**: The system shall log. (verification: test)\n- **SR-002**: The system shall audit. (Verification: INSPECTION)\n- **SR-003**: The system shall sign. (Verification: Review)\n"
+	items := ScanItems("20_requirements.md", doc)
+	var got []string
+	for _, it := range items {
+		got = append(got, it.Verification)
+	}
+	if strings.Join(got, ",") != "Test,Inspection,Review" {
+		t.Fatalf("verification = %v", got)
+	}
+}
+
+func TestScanItems_AllocatedTo(t *testing.T) {
+	doc := "- **SR-001**: The linter shall report findings. (Traces: N-001) (Allocated-To: C-002, C-003) (Verification: Test)\n"
+	items := ScanItems("20_requirements.md", doc)
+	if len(items) != 1 || items[0].Text != "The linter shall report findings" || items[0].Verification != "Test" {
+		t.Fatalf("unexpected items: %+v", items)
+	}
+	if got := items[0].AllocatedTo; len(got) != 2 || got[0] != "C-002" || got[1] != "C-003" {
+		t.Fatalf("unexpected allocation: %v", got)
+	}
+	reqs := Scan("20_requirements.md", doc, true)
+	if len(reqs) != 1 || !reqs[0].Valid() || len(reqs[0].AllocatedTo) != 2 || reqs[0].Result.System != "linter" {
+		t.Fatalf("expected allocated requirement to stay valid EARS, got %+v", reqs)
+	}
+}
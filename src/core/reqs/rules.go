@@ -0,0 +1,54 @@
+package reqs
+
+import (
+	"github.com/kelvin/tgsflow/src/core/config"
+	"github.com/kelvin/tgsflow/src/core/ears"
+)
+
+// Rules bundles the policy and controlled vocabulary applied to each requirement line.
+type Rules struct {
+	Policy   config.EARSConfig
+	Glossary *ears.Glossary
+}
+
+// LoadRules resolves the glossary (configured or auto-detected) for the given policy.
+func LoadRules(repoRoot string, policy config.EARSConfig) (Rules, error) {
+	g, err := ears.FindGlossary(repoRoot, policy.Glossary)
+	if err != nil {
+		return Rules{Policy: policy}, err
+	}
+	return Rules{Policy: policy, Glossary: g}, nil
+}
+
+// Check parses a requirement line written in the lang keyword profile and
+// returns issue messages (empty when valid). Glossary system names are kept
+// whole when splitting the subject.
+func (r Rules) Check(lang, text string) []string {
+	res, err := ears.ParseRequirementWithNames(lang, text, r.Glossary.SystemNames())
+	if err != nil {
+		return []string{err.Error()}
+	}
+	return r.CheckResult(res)
+}
+
+// CheckRequirement applies the rules to an already scanned requirement.
+func (r Rules) CheckRequirement(req Requirement) []string {
+	if req.Err != nil {
+		return []string{req.Err.Error()}
+	}
+	return r.CheckResult(req.Result)
+}
+
+// CheckResult applies the negative-requirement policy, measurability and glossary checks.
+func (r Rules) CheckResult(res ears.Result) []string {
+	var msgs []string
+	if res.Polarity == ears.PolarityNegative && !r.Policy.AllowNegative {
+		msgs = append(msgs, "negative requirement ('shall not') not allowed by guardrails.ears.allow_negative")
+	}
+	if r.Policy.RequireMeasurable {
+		if m := ears.CheckMeasurable(res); m != "" {
+			msgs = append(msgs, m)
+		}
+	}
+	return append(msgs, r.Glossary.Check(res)...)
+}
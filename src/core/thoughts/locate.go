@@ -65,3 +65,23 @@ func LocateActiveDir(repoRoot string) string {
 
 // SpecFileCandidates returns possible spec filenames.
 func SpecFileCandidates() []string { return []string{"10_spec.md", "10_specs.md"} }
+
+// List returns the thought directory names (<hash>-<slug>) under dir, sorted by name.
+// A missing directory yields an empty list.
+func List(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var out []string
+	for _, e := range entries {
+		if e.IsDir() && thoughtDirRe.MatchString(e.Name()) {
+			out = append(out, e.Name())
+		}
+	}
+	sort.Strings(out)
+	return out, nil
+}
@@ -0,0 +1,67 @@
+package toolpack
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/kelvin/tgsflow/src/core/config"
+)
+
+// Redacted replaces secrets in tool output.
+const Redacted = "[REDACTED]"
+
+// minSecretLen keeps short values such as "1" or "dev" from being masked everywhere.
+const minSecretLen = 8
+
+// Redactor masks values of sensitive environment variables and text matching
+// the configured patterns.
+type Redactor struct {
+	patterns []*regexp.Regexp
+	secrets  []string
+}
+
+// NewRedactor compiles ai.toolpack.redaction. Environment variables whose
+// names contain one of redact_env_keys (case-insensitive) have their current
+// values masked.
+func NewRedactor(r config.AIRedaction) (*Redactor, error) {
+	red := &Redactor{}
+	for _, p := range r.RedactPatterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("ai.toolpack.redaction.redact_patterns: %w", err)
+		}
+		red.patterns = append(red.patterns, re)
+	}
+	for _, kv := range os.Environ() {
+		name, value, _ := strings.Cut(kv, "=")
+		if len(value) < minSecretLen {
+			continue
+		}
+		for _, key := range r.RedactEnvKeys {
+			if key != "" && strings.Contains(strings.ToUpper(name), strings.ToUpper(key)) {
+				red.secrets = append(red.secrets, value)
+				break
+			}
+		}
+	}
+	// Longest first so a secret containing another is masked whole.
+	sort.Slice(red.secrets, func(i, j int) bool { return len(red.secrets[i]) > len(red.secrets[j]) })
+	return red, nil
+}
+
+// Apply returns s with secrets masked. A nil Redactor returns s unchanged.
+func (r *Redactor) Apply(s string) string {
+	if r == nil {
+		return s
+	}
+	for _, v := range r.secrets {
+		s = strings.ReplaceAll(s, v, Redacted)
+	}
+	for _, re := range r.patterns {
+		s = re.ReplaceAllString(s, Redacted)
+	}
+	return s
+}
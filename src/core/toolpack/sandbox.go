@@ -0,0 +1,143 @@
+// Package toolpack implements the repository tools TGS offers to models and
+// agents (fetch_repo_text, list_candidates, verify_ears, ...), with file access
+// confined by guardrails.allow_paths/deny_paths and output redacted per
+// ai.toolpack.redaction.
+package toolpack
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kelvin/tgsflow/src/core/config"
+)
+
+// ErrDenied is returned for paths outside the repository, outside
+// guardrails.allow_paths, or under guardrails.deny_paths.
+var ErrDenied = errors.New("path not allowed by guardrails")
+
+// alwaysDenied are never readable regardless of configuration.
+var alwaysDenied = []string{".git"}
+
+// Sandbox resolves repo-relative paths under the guardrails policy.
+type Sandbox struct {
+	root  string // absolute, symlinks resolved
+	allow []string
+	deny  []string
+}
+
+// NewSandbox confines access to repoRoot. An empty allow list permits every
+// path not denied.
+func NewSandbox(repoRoot string, g config.Guardrails) (*Sandbox, error) {
+	abs, err := filepath.Abs(repoRoot)
+	if err != nil {
+		return nil, err
+	}
+	if real, err := filepath.EvalSymlinks(abs); err == nil {
+		abs = real
+	}
+	s := &Sandbox{root: abs}
+	for _, p := range g.AllowPaths {
+		if p = cleanPattern(p); p != "" {
+			s.allow = append(s.allow, p)
+		}
+	}
+	for _, p := range append(append([]string{}, alwaysDenied...), g.DenyPaths...) {
+		if p = cleanPattern(p); p != "" {
+			s.deny = append(s.deny, p)
+		}
+	}
+	return s, nil
+}
+
+func cleanPattern(p string) string {
+	p = strings.Trim(filepath.ToSlash(strings.TrimSpace(p)), "/")
+	if p == "" || p == "." {
+		return ""
+	}
+	return filepath.ToSlash(filepath.Clean(p))
+}
+
+// Root returns the absolute repository root.
+func (s *Sandbox) Root() string { return s.root }
+
+// Rel normalises p (repo-relative, or absolute inside the repository) to a
+// clean slash-separated repo-relative path.
+func (s *Sandbox) Rel(p string) (string, error) {
+	p = strings.TrimSpace(p)
+	if p == "" {
+		return "", errors.New("empty path")
+	}
+	if filepath.IsAbs(p) {
+		rel, err := filepath.Rel(s.root, p)
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", p, ErrDenied)
+		}
+		p = rel
+	}
+	rel := filepath.ToSlash(filepath.Clean(p))
+	if rel == ".." || strings.HasPrefix(rel, "../") {
+		return "", fmt.Errorf("%s: %w", p, ErrDenied)
+	}
+	return rel, nil
+}
+
+// Allowed reports whether the repo-relative path passes allow/deny rules.
+func (s *Sandbox) Allowed(rel string) bool {
+	for _, d := range s.deny {
+		if underPattern(rel, d) {
+			return false
+		}
+	}
+	if len(s.allow) == 0 {
+		return true
+	}
+	for _, a := range s.allow {
+		if underPattern(rel, a) {
+			return true
+		}
+	}
+	return false
+}
+
+func underPattern(rel, pattern string) bool {
+	return rel == pattern || strings.HasPrefix(rel, pattern+"/")
+}
+
+// Resolve checks p against the policy and returns its absolute path. Symlinks
+// are followed and must stay inside the repository and the policy.
+func (s *Sandbox) Resolve(p string) (string, error) {
+	rel, err := s.Rel(p)
+	if err != nil {
+		return "", err
+	}
+	if !s.Allowed(rel) {
+		return "", fmt.Errorf("%s: %w", rel, ErrDenied)
+	}
+	abs := filepath.Join(s.root, filepath.FromSlash(rel))
+	real, err := filepath.EvalSymlinks(abs)
+	if err != nil {
+		return abs, nil // missing files surface when read
+	}
+	realRel, err := filepath.Rel(s.root, real)
+	if err != nil || realRel == ".." || strings.HasPrefix(filepath.ToSlash(realRel), "../") || !s.Allowed(filepath.ToSlash(realRel)) {
+		return "", fmt.Errorf("%s: %w", rel, ErrDenied)
+	}
+	return real, nil
+}
+
+// ReadFile reads p after Resolve; it also returns the repo-relative path.
+func (s *Sandbox) ReadFile(p string) ([]byte, string, error) {
+	abs, err := s.Resolve(p)
+	if err != nil {
+		return nil, "", err
+	}
+	rel, _ := s.Rel(p)
+	data, err := os.ReadFile(abs)
+	if err != nil {
+		return nil, rel, fmt.Errorf("cannot read %s: %w", rel, errors.Unwrap(err))
+	}
+	return data, rel, nil
+}
@@ -0,0 +1,60 @@
+package toolpack
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kelvin/tgsflow/src/core/config"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSandbox_AllowDeny(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "tgs", "design", "10_needs.md"), "# Needs\n")
+	writeFile(t, filepath.Join(root, "tgs", "secrets", "key.md"), "k\n")
+	writeFile(t, filepath.Join(root, "README.md"), "hi\n")
+	writeFile(t, filepath.Join(root, ".git", "config"), "[core]\n")
+	sb, err := NewSandbox(root, config.Guardrails{AllowPaths: []string{"tgs/", "README.md"}, DenyPaths: []string{"tgs/secrets/"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range []string{"tgs/design/10_needs.md", "README.md", "./tgs/design/../design/10_needs.md", filepath.Join(root, "README.md")} {
+		if _, err := sb.Resolve(p); err != nil {
+			t.Errorf("Resolve(%q) = %v, want allowed", p, err)
+		}
+	}
+	for _, p := range []string{"tgs/secrets/key.md", "src/main.go", "../outside.md", "/etc/passwd", ".git/config", "README.md.bak"} {
+		if _, err := sb.Resolve(p); !errors.Is(err, ErrDenied) {
+			t.Errorf("Resolve(%q) = %v, want ErrDenied", p, err)
+		}
+	}
+}
+
+func TestSandbox_SymlinkEscape(t *testing.T) {
+	root, outside := t.TempDir(), t.TempDir()
+	writeFile(t, filepath.Join(outside, "secret.md"), "s\n")
+	writeFile(t, filepath.Join(root, "tgs", "deny", "x.md"), "x\n")
+	if err := os.Symlink(filepath.Join(outside, "secret.md"), filepath.Join(root, "tgs", "escape.md")); err != nil {
+		t.Skipf("symlinks unavailable: %v", err)
+	}
+	if err := os.Symlink(filepath.Join(root, "tgs", "deny", "x.md"), filepath.Join(root, "tgs", "alias.md")); err != nil {
+		t.Fatal(err)
+	}
+	sb, _ := NewSandbox(root, config.Guardrails{DenyPaths: []string{"tgs/deny"}})
+	for _, p := range []string{"tgs/escape.md", "tgs/alias.md"} {
+		if _, _, err := sb.ReadFile(p); !errors.Is(err, ErrDenied) {
+			t.Errorf("ReadFile(%q) = %v, want ErrDenied", p, err)
+		}
+	}
+}
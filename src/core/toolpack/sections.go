@@ -0,0 +1,240 @@
+package toolpack
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kelvin/tgsflow/src/core/config"
+	"github.com/kelvin/tgsflow/src/core/drift"
+	"github.com/kelvin/tgsflow/src/core/thoughts"
+)
+
+// Section is a Markdown heading and the lines up to the next heading. Text
+// before the first heading forms a section with an empty heading.
+type Section struct {
+	Path    string `json:"path"`
+	Anchor  string `json:"anchor,omitempty"`
+	Heading string `json:"heading,omitempty"`
+	Start   int    `json:"start_line"`
+	End     int    `json:"end_line"`
+	Text    string `json:"-"`
+}
+
+// Source returns the pointer used in briefs, e.g. tgs/design/20_requirements.md#interfaces.
+func (s Section) Source() string {
+	if s.Anchor == "" {
+		return s.Path + "#L" + strconv.Itoa(s.Start) + "-L" + strconv.Itoa(s.End)
+	}
+	return s.Path + "#" + s.Anchor
+}
+
+var headingRe = regexp.MustCompile(`^#{1,6}\s+(.+?)\s*#*\s*$`)
+
+// Sections splits Markdown content at headings outside code fences. Anchors
+// follow GitHub's slug rules, with -1, -2 suffixes for repeated headings.
+func Sections(path, content string) []Section {
+	lines := strings.Split(strings.TrimRight(content, "\n"), "\n")
+	var out []Section
+	counts := map[string]int{}
+	cur := Section{Path: path, Start: 1}
+	inFence := false
+	flush := func(end int) {
+		cur.End = end
+		text := strings.Join(lines[cur.Start-1:end], "\n")
+		if strings.TrimSpace(text) != "" {
+			cur.Text = text
+			out = append(out, cur)
+		}
+	}
+	for i, ln := range lines {
+		if strings.HasPrefix(strings.TrimSpace(ln), "```") {
+			inFence = !inFence
+			continue
+		}
+		m := headingRe.FindStringSubmatch(ln)
+		if inFence || m == nil {
+			continue
+		}
+		if i > 0 {
+			flush(i)
+		}
+		anchor := drift.Slug(m[1])
+		if n := counts[anchor]; n > 0 {
+			counts[anchor]++
+			anchor += "-" + strconv.Itoa(n)
+		} else {
+			counts[anchor]++
+		}
+		cur = Section{Path: path, Anchor: anchor, Heading: m[1], Start: i + 1}
+	}
+	flush(len(lines))
+	return out
+}
+
+// DesignDir returns context.pack_dir (default tgs/design) resolved against repoRoot.
+func DesignDir(repoRoot string, cfg config.Config) string {
+	dir := strings.TrimSpace(cfg.Context.PackDir)
+	if dir == "" {
+		dir = filepath.Join("tgs", "design")
+	}
+	dir = filepath.Clean(dir)
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(repoRoot, dir)
+	}
+	return dir
+}
+
+// ThoughtFiles are the per-thought documents considered for context.
+func ThoughtFiles() []string {
+	return append([]string{"README.md", "research.md", "plan.md", "implementation.md"}, thoughts.SpecFileCandidates()...)
+}
+
+// ContextFiles returns the documents a context pack draws from: the design
+// docs and the active thought's files, joined with repoRoot.
+func ContextFiles(repoRoot string, cfg config.Config) []string {
+	files, _ := filepath.Glob(filepath.Join(DesignDir(repoRoot, cfg), "*.md"))
+	active := thoughts.LocateActiveDir(repoRoot)
+	for _, f := range ThoughtFiles() {
+		p := filepath.Join(active, f)
+		if st, err := os.Stat(p); err == nil && !st.IsDir() {
+			files = append(files, p)
+		}
+	}
+	return files
+}
+
+// Candidate is a section scored against a query.
+type Candidate struct {
+	Section
+	Score float64 `json:"score"`
+}
+
+var (
+	wordRe  = regexp.MustCompile(`[\p{L}\p{N}][\p{L}\p{N}_-]*`)
+	idRe    = regexp.MustCompile(`^[a-z]+-[0-9]+$`)
+	ignored = map[string]bool{"the": true, "and": true, "for": true, "with": true, "that": true, "this": true, "shall": true, "from": true, "into": true, "what": true, "how": true}
+)
+
+func queryTerms(query string) []string {
+	var terms []string
+	seen := map[string]bool{}
+	for _, w := range wordRe.FindAllString(strings.ToLower(query), -1) {
+		if (len(w) < 3 && !idRe.MatchString(w)) || ignored[w] || seen[w] {
+			continue
+		}
+		seen[w] = true
+		terms = append(terms, w)
+	}
+	return terms
+}
+
+// Rank scores sections by query-term overlap: heading matches weigh three
+// times body matches, requirement IDs (SR-001) ten times, and repeated body
+// matches saturate. Sections without a match are dropped; limit <= 0 keeps all.
+func Rank(sections []Section, query string, limit int) []Candidate {
+	terms := queryTerms(query)
+	if len(terms) == 0 {
+		return nil
+	}
+	var out []Candidate
+	for _, s := range sections {
+		heading, body := strings.ToLower(s.Heading), strings.ToLower(s.Text)
+		score := 0.0
+		for _, t := range terms {
+			hits := strings.Count(body, t)
+			if hits == 0 {
+				continue
+			}
+			if hits > 5 {
+				hits = 5
+			}
+			w := 1.0
+			if idRe.MatchString(t) {
+				w = 10
+			}
+			score += w * float64(hits)
+			if strings.Contains(heading, t) {
+				score += 3 * w
+			}
+		}
+		if score > 0 {
+			out = append(out, Candidate{Section: s, Score: score / float64(len(terms))})
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Score != out[j].Score {
+			return out[i].Score > out[j].Score
+		}
+		if out[i].Path != out[j].Path {
+			return out[i].Path < out[j].Path
+		}
+		return out[i].Start < out[j].Start
+	})
+	if limit > 0 && len(out) > limit {
+		out = out[:limit]
+	}
+	return out
+}
+
+// EstimateTokens approximates the token count of s (about four bytes per token).
+func EstimateTokens(s string) int { return (len(s) + 3) / 4 }
+
+// Brief is an ordered selection of sections within a token budget.
+type Brief struct {
+	Query    string    `json:"query,omitempty"`
+	Budget   int       `json:"budget_tokens"`
+	Tokens   int       `json:"tokens"`
+	Sections []Section `json:"sections"`
+	Omitted  []string  `json:"omitted,omitempty"` // sources that did not fit
+}
+
+// ProposeBrief keeps sections in the given order while they fit the budget;
+// sections that would overflow it are skipped and listed in Omitted.
+func ProposeBrief(query string, sections []Section, budget int) Brief {
+	b := Brief{Query: query, Budget: budget}
+	for _, s := range sections {
+		n := EstimateTokens(s.Text)
+		if budget > 0 && b.Tokens+n > budget {
+			b.Omitted = append(b.Omitted, s.Source())
+			continue
+		}
+		b.Tokens += n
+		b.Sections = append(b.Sections, s)
+	}
+	return b
+}
+
+// Markdown renders the brief with a source pointer under each section.
+func (b Brief) Markdown() string {
+	var sb strings.Builder
+	sb.WriteString("# Context pack\n")
+	if b.Query != "" {
+		sb.WriteString("\n## Query\n\"" + b.Query + "\"\n")
+	}
+	for _, s := range b.Sections {
+		title := s.Heading
+		if title == "" {
+			title = s.Path
+		}
+		body := s.Text
+		if s.Heading != "" {
+			_, body, _ = strings.Cut(body, "\n")
+		}
+		sb.WriteString("\n## " + title + "\n(Source: " + s.Source() + ")\n")
+		if body = strings.TrimSpace(body); body != "" {
+			sb.WriteString("\n" + body + "\n")
+		}
+	}
+	if len(b.Omitted) > 0 {
+		sb.WriteString("\n## Omitted (over budget)\n")
+		for _, o := range b.Omitted {
+			sb.WriteString("- " + o + "\n")
+		}
+	}
+	sb.WriteString("\n_Token budget: " + strconv.Itoa(b.Budget) + " (used ~" + strconv.Itoa(b.Tokens) + ")_\n")
+	return sb.String()
+}
@@ -0,0 +1,54 @@
+package toolpack
+
+import (
+	"strings"
+	"testing"
+)
+
+const designDoc = "Intro line.\n\n# Context\nPayments team.\n\n## Refunds\nThe refund flow reverses a payment.\n```\n# not a heading\n```\n\n## Refunds\n- **SR-007**: When a refund is requested, the system shall notify the payer.\n"
+
+func TestSections(t *testing.T) {
+	got := Sections("tgs/design/10_needs.md", designDoc)
+	if len(got) != 4 {
+		t.Fatalf("sections = %+v", got)
+	}
+	if got[0].Heading != "" || got[0].Start != 1 || got[0].End != 2 {
+		t.Errorf("preamble = %+v", got[0])
+	}
+	if got[2].Anchor != "refunds" || got[2].Start != 6 || got[2].End != 11 || !strings.Contains(got[2].Text, "# not a heading") {
+		t.Errorf("first refunds = %+v", got[2])
+	}
+	if got[3].Anchor != "refunds-1" || got[3].Source() != "tgs/design/10_needs.md#refunds-1" {
+		t.Errorf("second refunds = %+v", got[3])
+	}
+	if got[0].Source() != "tgs/design/10_needs.md#L1-L2" {
+		t.Errorf("preamble source = %s", got[0].Source())
+	}
+}
+
+func TestRankAndBrief(t *testing.T) {
+	secs := Sections("d.md", designDoc)
+	ranked := Rank(secs, "refund the payer (SR-007)", 0)
+	if len(ranked) != 2 || ranked[0].Anchor != "refunds-1" || ranked[1].Anchor != "refunds" {
+		t.Fatalf("ranked = %+v", ranked)
+	}
+	if Rank(secs, "an", 0) != nil {
+		t.Error("expected no candidates for a query without terms")
+	}
+
+	var order []Section
+	for _, c := range ranked {
+		order = append(order, c.Section)
+	}
+	budget := EstimateTokens(order[0].Text)
+	b := ProposeBrief("refunds", order, budget)
+	if len(b.Sections) != 1 || len(b.Omitted) != 1 || b.Omitted[0] != "d.md#refunds" || b.Tokens > budget {
+		t.Fatalf("brief = %+v", b)
+	}
+	md := b.Markdown()
+	for _, want := range []string{"## Query\n\"refunds\"", "## Refunds\n(Source: d.md#refunds-1)", "SR-007", "## Omitted (over budget)\n- d.md#refunds"} {
+		if !strings.Contains(md, want) {
+			t.Errorf("markdown missing %q:\n%s", want, md)
+		}
+	}
+}
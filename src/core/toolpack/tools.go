@@ -0,0 +1,450 @@
+package toolpack
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kelvin/tgsflow/src/core/config"
+	"github.com/kelvin/tgsflow/src/core/ears"
+	"github.com/kelvin/tgsflow/src/core/reqs"
+	"github.com/kelvin/tgsflow/src/core/thoughts"
+)
+
+// maxFetchLines caps one fetch_repo_text call.
+const maxFetchLines = 400
+
+// Tool is a named operation with a JSON Schema for its arguments. Run returns
+// text for the model; output is already redacted.
+type Tool struct {
+	Name        string
+	Description string
+	Schema      json.RawMessage
+	Run         func(ctx context.Context, args json.RawMessage) (string, error)
+}
+
+// Env carries the repository, configuration and guardrails the tools run under.
+type Env struct {
+	Root     string
+	Config   config.Config
+	Sandbox  *Sandbox
+	Redactor *Redactor
+}
+
+// NewEnv prepares tools for repoRoot under cfg's guardrails and redaction rules.
+func NewEnv(repoRoot string, cfg config.Config) (*Env, error) {
+	sb, err := NewSandbox(repoRoot, cfg.Guardrails)
+	if err != nil {
+		return nil, err
+	}
+	red, err := NewRedactor(cfg.AI.Toolpack.Redaction)
+	if err != nil {
+		return nil, err
+	}
+	return &Env{Root: sb.Root(), Config: cfg, Sandbox: sb, Redactor: red}, nil
+}
+
+// Tools returns the built-in tools. A tool declared in ai.toolpack.tools
+// takes its description from there.
+func (e *Env) Tools() []Tool {
+	tools := []Tool{
+		{
+			Name:        "fetch_repo_text",
+			Description: "Read a slice of a repository text file by path and 1-based line range (at most 400 lines).",
+			Schema:      json.RawMessage(`{"type":"object","properties":{"path":{"type":"string","description":"Repo-relative path"},"start_line":{"type":"integer","minimum":1},"end_line":{"type":"integer","minimum":1}},"required":["path"]}`),
+			Run:         e.fetchRepoText,
+		},
+		{
+			Name:        "list_candidates",
+			Description: "Rank design and active-thought doc sections against a query; returns path, anchor, line range and score.",
+			Schema:      json.RawMessage(`{"type":"object","properties":{"query":{"type":"string"},"limit":{"type":"integer","minimum":1}},"required":["query"]}`),
+			Run:         e.listCandidates,
+		},
+		{
+			Name:        "context_pack",
+			Description: "Build a Markdown brief of the doc sections most relevant to a query, with source pointers, within a token budget.",
+			Schema:      json.RawMessage(`{"type":"object","properties":{"query":{"type":"string"},"budget_tokens":{"type":"integer","minimum":1}},"required":["query"]}`),
+			Run:         e.contextPack,
+		},
+		{
+			Name:        "propose_brief",
+			Description: "Assemble the chosen doc sections, in order, into a Markdown brief with source pointers within a token budget.",
+			Schema:      json.RawMessage(`{"type":"object","properties":{"query":{"type":"string"},"sections":{"type":"array","items":{"type":"object","properties":{"path":{"type":"string"},"anchor":{"type":"string"},"start_line":{"type":"integer","minimum":1},"end_line":{"type":"integer","minimum":1}},"required":["path"]}},"budget_tokens":{"type":"integer","minimum":1}},"required":["sections"]}`),
+			Run:         e.proposeBrief,
+		},
+		{
+			Name:        "verify_ears",
+			Description: "Lint EARS requirements in the configured design docs (or the given paths) and report path:line findings.",
+			Schema:      json.RawMessage(`{"type":"object","properties":{"paths":{"type":"array","items":{"type":"string"}}}}`),
+			Run:         e.verifyEARS,
+		},
+		{
+			Name:        "parse_requirement",
+			Description: "Parse one requirement sentence into its EARS shape, system, preconditions, trigger and response, with policy issues.",
+			Schema:      json.RawMessage(`{"type":"object","properties":{"text":{"type":"string"},"language":{"type":"string","description":"EARS keyword profile, e.g. en, de, fr"}},"required":["text"]}`),
+			Run:         e.parseRequirement,
+		},
+		{
+			Name:        "list_thoughts",
+			Description: "List thought directories (<hash>-<slug>) and mark the active one.",
+			Schema:      json.RawMessage(`{"type":"object","properties":{}}`),
+			Run:         e.listThoughts,
+		},
+		{
+			Name:        "active_thought",
+			Description: "Return the active thought directory and its Markdown files.",
+			Schema:      json.RawMessage(`{"type":"object","properties":{}}`),
+			Run:         e.activeThought,
+		},
+	}
+	declared := map[string]string{}
+	for _, t := range e.Config.AI.Toolpack.Tools {
+		declared[t.Name] = t.Desc
+	}
+	for i := range tools {
+		if d := strings.TrimSpace(declared[tools[i].Name]); d != "" {
+			tools[i].Description = d
+		}
+		run := tools[i].Run
+		tools[i].Run = func(ctx context.Context, args json.RawMessage) (string, error) {
+			out, err := run(ctx, args)
+			return e.Redactor.Apply(out), err
+		}
+	}
+	return tools
+}
+
+// Lookup returns the tool called name.
+func (e *Env) Lookup(name string) (Tool, bool) {
+	for _, t := range e.Tools() {
+		if t.Name == name {
+			return t, true
+		}
+	}
+	return Tool{}, false
+}
+
+func decodeArgs(args json.RawMessage, v any) error {
+	if len(args) == 0 || string(args) == "null" {
+		return nil
+	}
+	if err := json.Unmarshal(args, v); err != nil {
+		return fmt.Errorf("invalid arguments: %w", err)
+	}
+	return nil
+}
+
+func toJSON(v any) (string, error) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	return string(data), err
+}
+
+func (e *Env) fetchRepoText(_ context.Context, args json.RawMessage) (string, error) {
+	var p struct {
+		Path      string `json:"path"`
+		StartLine int    `json:"start_line"`
+		EndLine   int    `json:"end_line"`
+	}
+	if err := decodeArgs(args, &p); err != nil {
+		return "", err
+	}
+	data, rel, err := e.Sandbox.ReadFile(p.Path)
+	if err != nil {
+		return "", err
+	}
+	if strings.IndexByte(string(data), 0) >= 0 {
+		return "", fmt.Errorf("%s is not a text file", rel)
+	}
+	lines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	start, end := p.StartLine, p.EndLine
+	if start < 1 {
+		start = 1
+	}
+	if end < start || end > len(lines) {
+		end = len(lines)
+	}
+	if end-start+1 > maxFetchLines {
+		end = start + maxFetchLines - 1
+	}
+	text := ""
+	if start <= len(lines) {
+		text = strings.Join(lines[start-1:end], "\n")
+	}
+	return toJSON(struct {
+		Path       string `json:"path"`
+		StartLine  int    `json:"start_line"`
+		EndLine    int    `json:"end_line"`
+		TotalLines int    `json:"total_lines"`
+		Text       string `json:"text"`
+	}{rel, start, end, len(lines), text})
+}
+
+// sections splits the context documents that pass the guardrails.
+func (e *Env) sections() []Section {
+	var out []Section
+	for _, f := range ContextFiles(e.Root, e.Config) {
+		data, rel, err := e.Sandbox.ReadFile(f)
+		if err != nil {
+			continue
+		}
+		out = append(out, Sections(rel, string(data))...)
+	}
+	return out
+}
+
+func (e *Env) listCandidates(_ context.Context, args json.RawMessage) (string, error) {
+	var p struct {
+		Query string `json:"query"`
+		Limit int    `json:"limit"`
+	}
+	if err := decodeArgs(args, &p); err != nil {
+		return "", err
+	}
+	if strings.TrimSpace(p.Query) == "" {
+		return "", errors.New("query is required")
+	}
+	if p.Limit <= 0 {
+		p.Limit = 10
+	}
+	cands := Rank(e.sections(), p.Query, p.Limit)
+	if cands == nil {
+		cands = []Candidate{}
+	}
+	return toJSON(cands)
+}
+
+func (e *Env) contextPack(_ context.Context, args json.RawMessage) (string, error) {
+	var p struct {
+		Query  string `json:"query"`
+		Budget int    `json:"budget_tokens"`
+	}
+	if err := decodeArgs(args, &p); err != nil {
+		return "", err
+	}
+	if strings.TrimSpace(p.Query) == "" {
+		return "", errors.New("query is required")
+	}
+	if p.Budget <= 0 {
+		p.Budget = e.Config.AI.Toolpack.Budget("context_pack_tokens", 1200)
+	}
+	var ranked []Section
+	for _, c := range Rank(e.sections(), p.Query, 0) {
+		ranked = append(ranked, c.Section)
+	}
+	return ProposeBrief(p.Query, ranked, p.Budget).Markdown(), nil
+}
+
+// SectionRef names a section by heading anchor or line range; a bare path
+// selects the whole file.
+type SectionRef struct {
+	Path      string `json:"path"`
+	Anchor    string `json:"anchor"`
+	StartLine int    `json:"start_line"`
+	EndLine   int    `json:"end_line"`
+}
+
+func (e *Env) proposeBrief(_ context.Context, args json.RawMessage) (string, error) {
+	var p struct {
+		Query    string       `json:"query"`
+		Sections []SectionRef `json:"sections"`
+		Budget   int          `json:"budget_tokens"`
+	}
+	if err := decodeArgs(args, &p); err != nil {
+		return "", err
+	}
+	if len(p.Sections) == 0 {
+		return "", errors.New("sections is required")
+	}
+	if p.Budget <= 0 {
+		p.Budget = e.Config.AI.Toolpack.Budget("context_pack_tokens", 1200)
+	}
+	var picked []Section
+	var missing []string
+	for _, ref := range p.Sections {
+		s, err := e.resolveSection(ref)
+		if err != nil {
+			missing = append(missing, err.Error())
+			continue
+		}
+		picked = append(picked, s)
+	}
+	if len(picked) == 0 {
+		return "", fmt.Errorf("no section could be resolved: %s", strings.Join(missing, "; "))
+	}
+	b := ProposeBrief(p.Query, picked, p.Budget)
+	for _, m := range missing {
+		b.Omitted = append(b.Omitted, m)
+	}
+	return b.Markdown(), nil
+}
+
+func (e *Env) resolveSection(ref SectionRef) (Section, error) {
+	data, rel, err := e.Sandbox.ReadFile(ref.Path)
+	if err != nil {
+		return Section{}, err
+	}
+	content := string(data)
+	anchor := strings.TrimPrefix(ref.Anchor, "#")
+	switch {
+	case anchor != "":
+		for _, s := range Sections(rel, content) {
+			if s.Anchor == anchor {
+				return s, nil
+			}
+		}
+		return Section{}, fmt.Errorf("%s#%s (no such heading)", rel, anchor)
+	default:
+		lines := strings.Split(strings.TrimRight(content, "\n"), "\n")
+		start, end := ref.StartLine, ref.EndLine
+		if start < 1 {
+			start = 1
+		}
+		if end < start || end > len(lines) {
+			end = len(lines)
+		}
+		if start > len(lines) {
+			return Section{}, fmt.Errorf("%s#L%d (past end of file)", rel, start)
+		}
+		return Section{Path: rel, Start: start, End: end, Text: strings.Join(lines[start-1:end], "\n")}, nil
+	}
+}
+
+func (e *Env) verifyEARS(_ context.Context, args json.RawMessage) (string, error) {
+	var p struct {
+		Paths []string `json:"paths"`
+	}
+	if err := decodeArgs(args, &p); err != nil {
+		return "", err
+	}
+	if len(p.Paths) == 0 {
+		p.Paths = reqs.DefaultPaths(e.Config)
+	}
+	rules, err := reqs.LoadRules(e.Root, e.Config.Guardrails.EARS)
+	if err != nil {
+		return "", fmt.Errorf("glossary: %w", err)
+	}
+	type report struct {
+		Captured int      `json:"captured"`
+		Valid    int      `json:"valid"`
+		Invalid  int      `json:"invalid"`
+		Findings []string `json:"findings"`
+	}
+	rep := report{Findings: []string{}}
+	for _, path := range p.Paths {
+		data, rel, err := e.Sandbox.ReadFile(path)
+		if err != nil {
+			rep.Findings = append(rep.Findings, err.Error())
+			continue
+		}
+		for _, r := range reqs.ScanWith(&ears.Linter{Names: rules.Glossary.SystemNames()}, rel, string(data), reqs.IsRequirementsDoc(rel), reqs.DefaultLanguage(e.Config)) {
+			rep.Captured++
+			msgs := rules.CheckRequirement(r)
+			if len(msgs) == 0 {
+				rep.Valid++
+				continue
+			}
+			rep.Invalid++
+			for _, m := range msgs {
+				rep.Findings = append(rep.Findings, fmt.Sprintf("%s:%d: %s", rel, r.Line, m))
+			}
+		}
+	}
+	return toJSON(rep)
+}
+
+func (e *Env) parseRequirement(_ context.Context, args json.RawMessage) (string, error) {
+	var p struct {
+		Text     string `json:"text"`
+		Language string `json:"language"`
+	}
+	if err := decodeArgs(args, &p); err != nil {
+		return "", err
+	}
+	if strings.TrimSpace(p.Text) == "" {
+		return "", errors.New("text is required")
+	}
+	if p.Language == "" {
+		p.Language = reqs.DefaultLanguage(e.Config)
+	}
+	out := struct {
+		Valid  bool         `json:"valid"`
+		Result *ears.Result `json:"result,omitempty"`
+		Issues []string     `json:"issues,omitempty"`
+	}{}
+	rules, err := reqs.LoadRules(e.Root, e.Config.Guardrails.EARS)
+	if err != nil {
+		return "", fmt.Errorf("glossary: %w", err)
+	}
+	res, err := ears.ParseRequirementWithNames(p.Language, strings.TrimSpace(p.Text), rules.Glossary.SystemNames())
+	if err != nil {
+		out.Issues = []string{err.Error()}
+		return toJSON(out)
+	}
+	out.Result = &res
+	out.Issues = rules.CheckResult(res)
+	out.Valid = len(out.Issues) == 0
+	return toJSON(out)
+}
+
+// ThoughtsDir returns context.thoughts_dir (default tgs/thoughts) resolved against repoRoot.
+func ThoughtsDir(repoRoot string, cfg config.Config) string {
+	dir := strings.TrimSpace(cfg.Context.ThoughtsDir)
+	if dir == "" {
+		dir = filepath.Join("tgs", "thoughts")
+	}
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(repoRoot, dir)
+	}
+	return filepath.Clean(dir)
+}
+
+type thoughtInfo struct {
+	Name   string   `json:"name"`
+	Path   string   `json:"path"`
+	Active bool     `json:"active,omitempty"`
+	Files  []string `json:"files,omitempty"`
+}
+
+func (e *Env) listThoughts(_ context.Context, args json.RawMessage) (string, error) {
+	dir := ThoughtsDir(e.Root, e.Config)
+	names, err := thoughts.List(dir)
+	if err != nil {
+		return "", err
+	}
+	active := filepath.Base(thoughts.LocateActiveDir(e.Root))
+	out := []thoughtInfo{}
+	for _, n := range names {
+		rel, err := e.Sandbox.Rel(filepath.Join(dir, n))
+		if err != nil || !e.Sandbox.Allowed(rel) {
+			continue
+		}
+		out = append(out, thoughtInfo{Name: n, Path: rel, Active: n == active})
+	}
+	return toJSON(out)
+}
+
+func (e *Env) activeThought(_ context.Context, args json.RawMessage) (string, error) {
+	dir := thoughts.LocateActiveDir(e.Root)
+	rel, err := e.Sandbox.Rel(dir)
+	if err != nil {
+		return "", err
+	}
+	if !e.Sandbox.Allowed(rel) {
+		return "", fmt.Errorf("%s: %w", rel, ErrDenied)
+	}
+	info := thoughtInfo{Name: filepath.Base(dir), Path: rel, Active: true}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", err
+	}
+	for _, ent := range entries {
+		if !ent.IsDir() && strings.HasSuffix(ent.Name(), ".md") {
+			info.Files = append(info.Files, ent.Name())
+		}
+	}
+	return toJSON(info)
+}
@@ -0,0 +1,162 @@
+package toolpack
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kelvin/tgsflow/src/core/config"
+)
+
+func sampleEnv(t *testing.T) *Env {
+	t.Helper()
+	t.Setenv("TGS_THOUGHT_DIR", "")
+	t.Setenv("TGS_TEST_API_KEY", "sk-live-0123456789")
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "tgs", "design", "10_needs.md"), "# Needs\n- **N-001**: The payer needs refunds within 5 days.\n")
+	writeFile(t, filepath.Join(root, "tgs", "design", "20_requirements.md"), "# Requirements\n\n## Refunds\n- **SR-001**: When a refund is requested, the system shall notify the payer within 5 seconds.\n- **SR-002**: The system refunds quickly.\n\nToken: sk-live-0123456789\n")
+	writeFile(t, filepath.Join(root, "tgs", "thoughts", "abc1234-refunds", "plan.md"), "# Plan\nShip refunds.\n")
+	writeFile(t, filepath.Join(root, "deploy", "prod.md"), "# Prod\n")
+	cfg := config.Default()
+	env, err := NewEnv(root, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return env
+}
+
+func run(t *testing.T, env *Env, name, args string) (string, error) {
+	t.Helper()
+	tool, ok := env.Lookup(name)
+	if !ok {
+		t.Fatalf("tool %s not registered", name)
+	}
+	return tool.Run(context.Background(), json.RawMessage(args))
+}
+
+func TestTools_FetchRepoText(t *testing.T) {
+	env := sampleEnv(t)
+	out, err := run(t, env, "fetch_repo_text", `{"path":"tgs/design/20_requirements.md","start_line":3,"end_line":4}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got struct {
+		StartLine, EndLine int
+		TotalLines         int    `json:"total_lines"`
+		Text               string `json:"text"`
+	}
+	_ = json.Unmarshal([]byte(out), &got)
+	if got.TotalLines != 7 || !strings.HasPrefix(got.Text, "## Refunds\n- **SR-001**") {
+		t.Fatalf("fetch = %s", out)
+	}
+	out, _ = run(t, env, "fetch_repo_text", `{"path":"tgs/design/20_requirements.md"}`)
+	if strings.Contains(out, "sk-live") || !strings.Contains(out, Redacted) {
+		t.Errorf("secret not redacted: %s", out)
+	}
+	if _, err := run(t, env, "fetch_repo_text", `{"path":"deploy/prod.md"}`); !errors.Is(err, ErrDenied) {
+		t.Errorf("deny_paths not enforced: %v", err)
+	}
+	if _, err := run(t, env, "fetch_repo_text", `{"path":"../../etc/passwd"}`); !errors.Is(err, ErrDenied) {
+		t.Errorf("escape not refused: %v", err)
+	}
+}
+
+func TestTools_CandidatesAndPack(t *testing.T) {
+	env := sampleEnv(t)
+	out, err := run(t, env, "list_candidates", `{"query":"refund payer","limit":2}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cands []Candidate
+	if err := json.Unmarshal([]byte(out), &cands); err != nil || len(cands) != 2 || cands[0].Path != "tgs/design/20_requirements.md" || cands[0].Anchor != "refunds" {
+		t.Fatalf("candidates = %s (%v)", out, err)
+	}
+	brief, err := run(t, env, "context_pack", `{"query":"refunds"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"(Source: tgs/design/20_requirements.md#refunds)", "(Source: tgs/thoughts/abc1234-refunds/plan.md#plan)", "Token budget: 1200"} {
+		if !strings.Contains(brief, want) {
+			t.Errorf("brief missing %q:\n%s", want, brief)
+		}
+	}
+	if strings.Contains(brief, "sk-live") {
+		t.Errorf("brief leaks secret:\n%s", brief)
+	}
+}
+
+func TestTools_ProposeBrief(t *testing.T) {
+	env := sampleEnv(t)
+	brief, err := run(t, env, "propose_brief", `{"query":"refunds","sections":[
+		{"path":"tgs/design/20_requirements.md","anchor":"#refunds"},
+		{"path":"tgs/design/10_needs.md","start_line":2,"end_line":2},
+		{"path":"tgs/design/10_needs.md","anchor":"missing"}]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"(Source: tgs/design/20_requirements.md#refunds)", "(Source: tgs/design/10_needs.md#L2-L2)", "10_needs.md#missing (no such heading)"} {
+		if !strings.Contains(brief, want) {
+			t.Errorf("brief missing %q:\n%s", want, brief)
+		}
+	}
+	if strings.Index(brief, "SR-001") > strings.Index(brief, "N-001") {
+		t.Errorf("sections not kept in the requested order:\n%s", brief)
+	}
+	if _, err := run(t, env, "propose_brief", `{"sections":[{"path":"deploy/prod.md"}]}`); err == nil || !strings.Contains(err.Error(), "no section could be resolved") {
+		t.Errorf("denied-only refs: %v", err)
+	}
+}
+
+func TestTools_EARS(t *testing.T) {
+	env := sampleEnv(t)
+	out, err := run(t, env, "verify_ears", `{}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var rep struct {
+		Captured, Valid, Invalid int
+		Findings                 []string
+	}
+	_ = json.Unmarshal([]byte(out), &rep)
+	if rep.Captured != 2 || rep.Invalid != 1 || len(rep.Findings) != 1 || !strings.HasPrefix(rep.Findings[0], "tgs/design/20_requirements.md:5: ") {
+		t.Fatalf("verify_ears = %s", out)
+	}
+
+	out, err = run(t, env, "parse_requirement", `{"text":"When a refund is requested, the system shall notify the payer within 5 seconds."}`)
+	if err != nil || !strings.Contains(out, `"valid": true`) || !strings.Contains(out, `"shape": "event-driven"`) {
+		t.Fatalf("parse_requirement = %s, %v", out, err)
+	}
+	out, _ = run(t, env, "parse_requirement", `{"text":"The system refunds quickly."}`)
+	if !strings.Contains(out, `"valid": false`) {
+		t.Errorf("expected invalid requirement: %s", out)
+	}
+}
+
+func TestTools_Thoughts(t *testing.T) {
+	env := sampleEnv(t)
+	out, err := run(t, env, "list_thoughts", `{}`)
+	if err != nil || !strings.Contains(out, `"name": "abc1234-refunds"`) || !strings.Contains(out, `"active": true`) {
+		t.Fatalf("list_thoughts = %s, %v", out, err)
+	}
+	out, err = run(t, env, "active_thought", ``)
+	if err != nil || !strings.Contains(out, `"path": "tgs/thoughts/abc1234-refunds"`) || !strings.Contains(out, `"plan.md"`) {
+		t.Fatalf("active_thought = %s, %v", out, err)
+	}
+}
+
+func TestTools_ConfigDescriptions(t *testing.T) {
+	env := sampleEnv(t)
+	env.Config.AI.Toolpack.Tools = []config.AITool{{Name: "verify_ears", Desc: "Team lint"}}
+	tool, _ := env.Lookup("verify_ears")
+	if tool.Description != "Team lint" {
+		t.Fatalf("description = %q", tool.Description)
+	}
+	for _, tool := range env.Tools() {
+		if !json.Valid(tool.Schema) {
+			t.Errorf("%s: invalid schema", tool.Name)
+		}
+	}
+}
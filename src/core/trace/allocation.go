@@ -0,0 +1,149 @@
+package trace
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/kelvin/tgsflow/src/core/reqs"
+)
+
+// Component is an ID'd bullet of the architecture document, e.g.
+//
+//   - **C-001**: EARS Linter (`src/core/ears/`) — parses and lints requirements.
+type Component struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Text string `json:"text"`
+	Path string `json:"path"`
+	Line int    `json:"line"`
+	// Paths are the existing files and directories named in code spans.
+	Paths []string `json:"paths,omitempty"`
+}
+
+var componentNameEnd = regexp.MustCompile(`\s+[(—–-]\s*|:\s|\.\s|\.$|\(`)
+
+// ScanComponents returns the components declared in an architecture document.
+func ScanComponents(repoRoot, path, content string) []Component {
+	var out []Component
+	for _, it := range reqs.ScanItems(path, content) {
+		c := Component{ID: it.ID, Text: it.Text, Path: it.Path, Line: it.Line, Name: it.Text}
+		if loc := componentNameEnd.FindStringIndex(c.Name); loc != nil {
+			c.Name = c.Name[:loc[0]]
+		}
+		c.Name = strings.TrimSpace(strings.Trim(c.Name, "`*"))
+		for _, m := range codeSpanRe.FindAllStringSubmatch(it.Text, -1) {
+			p := filepath.ToSlash(filepath.Clean(strings.TrimPrefix(m[1], "/")))
+			if strings.HasPrefix(p, "..") || strings.Contains(p, "://") {
+				continue
+			}
+			if _, err := os.Stat(filepath.Join(repoRoot, p)); err == nil && !containsString(c.Paths, p) {
+				c.Paths = append(c.Paths, p)
+			}
+		}
+		out = append(out, c)
+	}
+	return out
+}
+
+// AllocationRow is one line of the allocation matrix.
+type AllocationRow struct {
+	Requirement string   `json:"requirement"`
+	Components  []string `json:"components"`
+}
+
+// SystemMismatch is a requirement whose EARS system names something other than
+// the component it is allocated to.
+type SystemMismatch struct {
+	Requirement string   `json:"requirement"`
+	Path        string   `json:"path"`
+	Line        int      `json:"line"`
+	System      string   `json:"system"`
+	Components  []string `json:"components"`
+}
+
+// UnknownAllocation is an Allocated-To target that names no declared component.
+type UnknownAllocation struct {
+	Requirement string `json:"requirement"`
+	Path        string `json:"path"`
+	Line        int    `json:"line"`
+	Component   string `json:"component"`
+}
+
+// AllocationReport is the result of Graph.Allocation.
+type AllocationReport struct {
+	Components  []Component         `json:"components"`
+	Matrix      []AllocationRow     `json:"matrix"`
+	Unallocated []string            `json:"unallocated"`
+	Unused      []string            `json:"components_without_requirements"`
+	Mismatches  []SystemMismatch    `json:"system_mismatches"`
+	Unknown     []UnknownAllocation `json:"unknown_components"`
+}
+
+// Count returns the number of findings.
+func (r AllocationReport) Count() int {
+	return len(r.Unallocated) + len(r.Unused) + len(r.Mismatches) + len(r.Unknown)
+}
+
+// Allocation reports requirements without an allocated component, components
+// without requirements, Allocated-To targets naming no declared component and
+// the allocation matrix. systems maps requirement IDs to the EARS
+// Result.System; a requirement whose system matches none of its components'
+// names is a mismatch. The generic "system" matches any component.
+func (g *Graph) Allocation(systems map[string]string) AllocationReport {
+	rep := AllocationReport{}
+	for _, n := range g.NodesOf(KindComponent) {
+		c := Component{ID: n.Label, Name: n.Attrs["name"], Text: n.Attrs["text"], Path: n.Path, Line: n.Line}
+		if p := n.Attrs["paths"]; p != "" {
+			c.Paths = strings.Split(p, ",")
+		}
+		rep.Components = append(rep.Components, c)
+		if len(g.In(n.ID, RelAllocated)) == 0 {
+			rep.Unused = append(rep.Unused, n.Label)
+		}
+	}
+	for _, n := range g.NodesOf(KindRequirement) {
+		if u := n.Attrs["unknown_components"]; u != "" {
+			for _, c := range strings.Split(u, ",") {
+				rep.Unknown = append(rep.Unknown, UnknownAllocation{Requirement: n.Label, Path: n.Path, Line: n.Line, Component: c})
+			}
+		}
+		edges := g.Out(n.ID, RelAllocated)
+		if len(edges) == 0 {
+			rep.Unallocated = append(rep.Unallocated, n.Label)
+			continue
+		}
+		row := AllocationRow{Requirement: n.Label}
+		consistent := false
+		sys := normalizeName(systems[n.Label])
+		for _, e := range edges {
+			c := g.Node(e.To)
+			row.Components = append(row.Components, c.Label)
+			if sys == "" || sys == "system" || namesMatch(sys, normalizeName(c.Attrs["name"])) {
+				consistent = true
+			}
+		}
+		sort.Strings(row.Components)
+		rep.Matrix = append(rep.Matrix, row)
+		if !consistent {
+			rep.Mismatches = append(rep.Mismatches, SystemMismatch{Requirement: n.Label, Path: n.Path, Line: n.Line, System: systems[n.Label], Components: row.Components})
+		}
+	}
+	return rep
+}
+
+func normalizeName(s string) string {
+	s = strings.ToLower(strings.Join(strings.Fields(s), " "))
+	return strings.TrimPrefix(s, "the ")
+}
+
+// namesMatch reports whether an EARS system name refers to a component name,
+// allowing either to be a shortened form of the other ("linter" vs "EARS linter").
+func namesMatch(system, component string) bool {
+	if system == "" || component == "" {
+		return false
+	}
+	return system == component || strings.Contains(" "+component+" ", " "+system+" ") || strings.Contains(" "+system+" ", " "+component+" ")
+}
@@ -0,0 +1,78 @@
+package trace
+
+import "testing"
+
+func allocationRepo(t *testing.T) string {
+	return writeRepo(t, map[string]string{
+		"tgs/design/20_requirements.md": "# Requirements\n\n" +
+			"- **SR-001**: When a requirement is saved, the linter shall report findings. (Allocated-To: C-001)\n" +
+			"- **SR-002**: The packer shall write briefs. (Allocated-To: C-001)\n" +
+			"- **SR-003**: The system shall log runs.\n" +
+			"- **SR-004**: The system shall cache parses. (Allocated-To: C-404)\n",
+		"tgs/design/30_architecture.md": "# Architecture\n\n## Components\n" +
+			"- **C-001**: EARS Linter (`src/lint/`) — parses requirements.\n" +
+			"- **C-002**: Context Packer: builds briefs from `src/pack/pack.go`.\n",
+		"src/lint/lint.go": "package lint\n",
+		"src/pack/pack.go": "package pack\n",
+	})
+}
+
+func TestScanComponents(t *testing.T) {
+	root := allocationRepo(t)
+	g, _ := Build(root, Options{DesignDir: "tgs/design", ThoughtsDir: "tgs/thoughts"})
+	cs := g.Allocation(nil).Components
+	if len(cs) != 2 {
+		t.Fatalf("expected 2 components, got %+v", cs)
+	}
+	if cs[0].Name != "EARS Linter" || len(cs[0].Paths) != 1 || cs[0].Paths[0] != "src/lint" {
+		t.Errorf("unexpected C-001: %+v", cs[0])
+	}
+	if cs[1].Name != "Context Packer" || len(cs[1].Paths) != 1 || cs[1].Paths[0] != "src/pack/pack.go" {
+		t.Errorf("unexpected C-002: %+v", cs[1])
+	}
+}
+
+func TestAllocation(t *testing.T) {
+	root := allocationRepo(t)
+	g, _ := Build(root, Options{DesignDir: "tgs/design", ThoughtsDir: "tgs/thoughts"})
+	if !g.edges[Edge{"requirement:SR-001", "component:C-001", RelAllocated}] {
+		t.Fatal("missing allocation edge")
+	}
+	if len(g.Dangling) != 1 || g.Dangling[0] != "tgs/design/20_requirements.md:6: SR-004 is allocated to unknown component C-404" {
+		t.Errorf("unexpected dangling: %v", g.Dangling)
+	}
+	rep := g.Allocation(map[string]string{"SR-001": "linter", "SR-002": "packer", "SR-003": "system"})
+	if len(rep.Matrix) != 2 || rep.Matrix[0].Requirement != "SR-001" || rep.Matrix[0].Components[0] != "C-001" {
+		t.Errorf("unexpected matrix: %+v", rep.Matrix)
+	}
+	if len(rep.Unallocated) != 2 || rep.Unallocated[0] != "SR-003" || rep.Unallocated[1] != "SR-004" {
+		t.Errorf("unexpected unallocated: %v", rep.Unallocated)
+	}
+	if len(rep.Unused) != 1 || rep.Unused[0] != "C-002" {
+		t.Errorf("unexpected unused components: %v", rep.Unused)
+	}
+	if len(rep.Mismatches) != 1 || rep.Mismatches[0].Requirement != "SR-002" || rep.Mismatches[0].System != "packer" {
+		t.Errorf("expected SR-002 (packer) to mismatch the EARS Linter, got %+v", rep.Mismatches)
+	}
+	if len(rep.Unknown) != 1 || rep.Unknown[0].Requirement != "SR-004" || rep.Unknown[0].Component != "C-404" || rep.Unknown[0].Line != 6 {
+		t.Errorf("expected SR-004 to report the unknown C-404, got %+v", rep.Unknown)
+	}
+	if rep.Count() != 5 {
+		t.Errorf("expected 5 findings, got %d", rep.Count())
+	}
+}
+
+func TestImpact_ComponentAllocation(t *testing.T) {
+	root := allocationRepo(t)
+	g, _ := Build(root, Options{DesignDir: "tgs/design", ThoughtsDir: "tgs/thoughts"})
+	rep := g.Impact([]string{"src/lint/lint.go", "src/pack/pack.go"})
+	if len(rep.Requirements) != 2 || rep.Requirements[0].ID != "SR-001" {
+		t.Fatalf("unexpected impact: %+v", rep)
+	}
+	if got := rep.Requirements[0].Reasons; len(got) != 1 || got[0] != "src/lint/lint.go belongs to component C-001" {
+		t.Errorf("unexpected reasons: %v", got)
+	}
+	if len(rep.Unmapped) != 1 || rep.Unmapped[0] != "src/pack/pack.go" {
+		t.Errorf("expected pack.go (C-002 has no requirements) to be unmapped, got %v", rep.Unmapped)
+	}
+}
@@ -0,0 +1,101 @@
+package trace
+
+import (
+	"strings"
+
+	"github.com/kelvin/tgsflow/src/core/reqs"
+)
+
+// CoverageStatus summarises how well a requirement is verified by tests.
+type CoverageStatus string
+
+const (
+	CoveragePassing  CoverageStatus = "passing"  // all annotated tests passed
+	CoverageFailing  CoverageStatus = "failing"  // at least one annotated test failed
+	CoverageMissing  CoverageStatus = "missing"  // no test declares it verifies the requirement
+	CoverageDeclared CoverageStatus = "declared" // annotated tests exist but no results were supplied for them
+)
+
+// RequirementCoverage is the test coverage of one requirement.
+type RequirementCoverage struct {
+	ID           string         `json:"id"`
+	Path         string         `json:"path"`
+	Line         int            `json:"line"`
+	Verification string         `json:"verification,omitempty"`
+	Status       CoverageStatus `json:"status"`
+	Tests        []CoveredTest  `json:"tests,omitempty"`
+}
+
+// CoveredTest is an annotated test with its outcome ("" when it did not run).
+type CoveredTest struct {
+	TestRef
+	Status TestStatus `json:"status,omitempty"`
+}
+
+// Enforced reports whether the requirement's verification method is Test, in
+// which case a missing or failing test is a finding.
+func (c RequirementCoverage) Enforced() bool {
+	return strings.EqualFold(c.Verification, "Test")
+}
+
+// CoverageReport is the result of Coverage.
+type CoverageReport struct {
+	Requirements []RequirementCoverage `json:"requirements"`
+	// Unknown lists annotated tests with only the IDs that are not defined.
+	Unknown []TestRef `json:"unknown,omitempty"`
+}
+
+// Coverage matches annotated tests (and optional run results) against items.
+// A nil results map yields "declared" for every annotated requirement.
+func Coverage(items []reqs.Item, tests []TestRef, results TestResults) CoverageReport {
+	byID := make(map[string][]TestRef)
+	known := make(map[string]bool, len(items))
+	for _, it := range items {
+		known[it.ID] = true
+	}
+	var rep CoverageReport
+	for _, t := range tests {
+		var unknown []string
+		for _, id := range t.Verifies {
+			if !known[id] {
+				unknown = append(unknown, id)
+				continue
+			}
+			byID[id] = append(byID[id], t)
+		}
+		if len(unknown) > 0 {
+			u := t
+			u.Verifies = unknown
+			rep.Unknown = append(rep.Unknown, u)
+		}
+	}
+	for _, it := range items {
+		rc := RequirementCoverage{ID: it.ID, Path: it.Path, Line: it.Line, Verification: it.Verification, Status: CoverageMissing}
+		passed, failed, ran := 0, 0, 0
+		for _, t := range byID[it.ID] {
+			ct := CoveredTest{TestRef: t}
+			if s, ok := results.Lookup(t); ok {
+				ct.Status = s
+				ran++
+				switch s {
+				case TestPass:
+					passed++
+				case TestFail:
+					failed++
+				}
+			}
+			rc.Tests = append(rc.Tests, ct)
+		}
+		switch {
+		case len(rc.Tests) == 0:
+		case failed > 0:
+			rc.Status = CoverageFailing
+		case passed > 0 && ran == len(rc.Tests):
+			rc.Status = CoveragePassing
+		default:
+			rc.Status = CoverageDeclared
+		}
+		rep.Requirements = append(rep.Requirements, rc)
+	}
+	return rep
+}
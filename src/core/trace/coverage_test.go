@@ -0,0 +1,103 @@
+package trace
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kelvin/tgsflow/src/core/reqs"
+)
+
+const annotatedTest = `package alarm
+
+import "testing"
+
+// Verifies: SR-001, SR-404
+func TestSiren(t *testing.T) {}
+
+// TestArm covers arming.
+// Verifies: SR-002
+func TestArm(t *testing.T) {}
+
+func TestUnannotated(t *testing.T) {}
+`
+
+const goTestJSON = `{"Action":"run","Package":"example.com/alarm/alarm","Test":"TestSiren"}
+{"Action":"pass","Package":"example.com/alarm/alarm","Test":"TestSiren/loud"}
+{"Action":"pass","Package":"example.com/alarm/alarm","Test":"TestSiren"}
+# example.com/alarm/other [build failed]
+{"Action":"fail","Package":"example.com/alarm/alarm","Test":"TestArm"}
+{"Action":"pass","Package":"example.com/alarm/alarm"}
+`
+
+func TestScanTests_Annotations(t *testing.T) {
+	root := writeRepo(t, map[string]string{
+		"go.mod":                      "module example.com/alarm\n\ngo 1.23\n",
+		"alarm/alarm_test.go":         annotatedTest,
+		"alarm/testdata/skip_test.go": "package broken(",
+	})
+	tests, errs := ScanTests(root)
+	if len(errs) > 0 {
+		t.Fatal(errs)
+	}
+	if len(tests) != 2 {
+		t.Fatalf("expected 2 annotated tests, got %+v", tests)
+	}
+	if got := tests[0]; got.Name != "TestSiren" || got.Package != "example.com/alarm/alarm" || got.Path != "alarm/alarm_test.go" || got.Line != 6 || strings.Join(got.Verifies, ",") != "SR-001,SR-404" {
+		t.Fatalf("unexpected test ref: %+v", got)
+	}
+	if got := tests[1]; got.Name != "TestArm" || strings.Join(got.Verifies, ",") != "SR-002" {
+		t.Fatalf("unexpected test ref: %+v", got)
+	}
+}
+
+func TestScanTests_SkipsUnparsableFiles(t *testing.T) {
+	root := writeRepo(t, map[string]string{
+		"alarm/alarm_test.go":  annotatedTest,
+		"alarm/broken_test.go": "package alarm\n\nfunc TestBroken(t *testing.T) {\n",
+	})
+	tests, errs := ScanTests(root)
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "broken_test.go") {
+		t.Fatalf("expected one error for broken_test.go, got %v", errs)
+	}
+	if len(tests) != 2 {
+		t.Fatalf("expected the parsable file still scanned, got %+v", tests)
+	}
+}
+
+func TestCoverage_Statuses(t *testing.T) {
+	items := []reqs.Item{
+		{ID: "SR-001", Verification: "Test"},
+		{ID: "SR-002", Verification: "Test"},
+		{ID: "SR-003", Verification: "Test"},
+		{ID: "SR-004", Verification: "Inspection"},
+	}
+	tests := []TestRef{
+		{Name: "TestSiren", Package: "example.com/alarm/alarm", Verifies: []string{"SR-001", "SR-404"}},
+		{Name: "TestArm", Package: "example.com/alarm/alarm", Verifies: []string{"SR-002"}},
+	}
+	results, err := ReadTestJSON(strings.NewReader(goTestJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rep := Coverage(items, tests, results)
+	want := []CoverageStatus{CoveragePassing, CoverageFailing, CoverageMissing, CoverageMissing}
+	for i, rc := range rep.Requirements {
+		if rc.Status != want[i] {
+			t.Errorf("%s: got %s, want %s", rc.ID, rc.Status, want[i])
+		}
+	}
+	if !rep.Requirements[2].Enforced() || rep.Requirements[3].Enforced() {
+		t.Fatalf("only Test-verified requirements are enforced")
+	}
+	if !(RequirementCoverage{Verification: "test"}).Enforced() {
+		t.Fatalf("expected the verification method to match case-insensitively")
+	}
+	if len(rep.Unknown) != 1 || strings.Join(rep.Unknown[0].Verifies, ",") != "SR-404" {
+		t.Fatalf("unexpected unknown annotations: %+v", rep.Unknown)
+	}
+
+	// Without results, annotated requirements are only declared
+	if rc := Coverage(items, tests, nil).Requirements[0]; rc.Status != CoverageDeclared {
+		t.Fatalf("expected declared without results, got %s", rc.Status)
+	}
+}
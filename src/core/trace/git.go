@@ -0,0 +1,129 @@
+package trace
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// Commit is a git commit with its trailers (keys lowercased) and changed files.
+type Commit struct {
+	Hash     string
+	Subject  string
+	Trailers map[string][]string
+	Files    []string
+}
+
+// Short returns the abbreviated hash used in node IDs.
+func (c Commit) Short() string {
+	if len(c.Hash) > 7 {
+		return c.Hash[:7]
+	}
+	return c.Hash
+}
+
+// traceTrailers are the trailer keys that link a commit into the graph, e.g.
+//
+//	Thought: 4b5a2a8-tgs-verify-ears-command
+//	Refs: SR-026, SR-009
+var traceTrailers = map[string]bool{"thought": true, "refs": true, "implements": true, "requirement": true}
+
+// readCommits returns commits carrying trace trailers, newest first. A directory
+// that is not a git work tree yields no commits and no error.
+func readCommits(repoRoot string) ([]Commit, error) {
+	if out, err := Git(repoRoot, "rev-parse", "--is-inside-work-tree"); err != nil || strings.TrimSpace(out) != "true" {
+		return nil, nil
+	}
+	out, err := Git(repoRoot, "log", "--format=%H%x1f%s%x1f%(trailers:only,unfold)%x1e")
+	if err != nil {
+		return nil, err
+	}
+	var commits []Commit
+	for _, rec := range strings.Split(out, "\x1e") {
+		parts := strings.SplitN(strings.TrimLeft(rec, "\n"), "\x1f", 3)
+		if len(parts) < 3 {
+			continue
+		}
+		c := Commit{Hash: parts[0], Subject: parts[1], Trailers: ParseTrailers(parts[2])}
+		linked := false
+		for k := range c.Trailers {
+			if traceTrailers[k] {
+				linked = true
+			}
+		}
+		if !linked {
+			continue
+		}
+		files, err := Git(repoRoot, "show", "--name-only", "--format=", c.Hash)
+		if err == nil {
+			for _, f := range strings.Split(files, "\n") {
+				if f = strings.TrimSpace(f); f != "" {
+					c.Files = append(c.Files, f)
+				}
+			}
+		}
+		commits = append(commits, c)
+	}
+	return commits, nil
+}
+
+// ParseTrailers parses "Key: value" lines into a map with lowercased keys.
+func ParseTrailers(block string) map[string][]string {
+	out := make(map[string][]string)
+	for _, ln := range strings.Split(block, "\n") {
+		k, v, ok := strings.Cut(ln, ":")
+		if !ok || strings.ContainsAny(strings.TrimSpace(k), " \t") {
+			continue
+		}
+		k = strings.ToLower(strings.TrimSpace(k))
+		if v = strings.TrimSpace(v); k != "" && v != "" {
+			out[k] = append(out[k], v)
+		}
+	}
+	return out
+}
+
+// Git runs git in repoRoot and returns its stdout; stderr is included in the error.
+func Git(repoRoot string, args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"-C", repoRoot}, args...)...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("git %s: %v: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
+
+// ChangedFiles lists repo-relative paths that differ between base and the
+// working tree, including staged and unstaged changes.
+func ChangedFiles(repoRoot, base string) ([]string, error) {
+	out, err := Git(repoRoot, "diff", "--name-only", base, "--")
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, f := range strings.Split(out, "\n") {
+		if f = strings.TrimSpace(f); f != "" {
+			files = append(files, f)
+		}
+	}
+	return files, nil
+}
+
+// FirstAdded returns the commit time at which rel was first added, if rel is
+// tracked by git.
+func FirstAdded(repoRoot, rel string) (time.Time, bool) {
+	out, err := Git(repoRoot, "log", "--diff-filter=A", "--format=%cI", "--", rel)
+	if err != nil {
+		return time.Time{}, false
+	}
+	lines := strings.Fields(out)
+	if len(lines) == 0 {
+		return time.Time{}, false
+	}
+	t, err := time.Parse(time.RFC3339, lines[len(lines)-1])
+	return t, err == nil
+}
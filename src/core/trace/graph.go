@@ -0,0 +1,429 @@
+// Package trace builds the traceability graph linking stakeholder needs,
+// requirements, V&V rows, thoughts, commits, source files and tests.
+package trace
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/kelvin/tgsflow/src/core/config"
+	"github.com/kelvin/tgsflow/src/core/reqs"
+	"github.com/kelvin/tgsflow/src/core/thoughts"
+)
+
+// Kind classifies graph nodes.
+type Kind string
+
+const (
+	KindNeed        Kind = "need"
+	KindRequirement Kind = "requirement"
+	KindVnV         Kind = "vnv"
+	KindThought     Kind = "thought"
+	KindCommit      Kind = "commit"
+	KindFile        Kind = "file"
+	KindTest        Kind = "test"
+	KindComponent   Kind = "component"
+)
+
+// Edge relations.
+const (
+	RelRefines    = "refines"     // need -> requirement
+	RelVerifiedBy = "verified-by" // requirement -> V&V row
+	RelArtifact   = "artifact"    // V&V row -> file/test
+	RelReferences = "references"  // thought -> need/requirement
+	RelTouches    = "touches"     // thought -> file/test
+	RelPartOf     = "part-of"     // commit -> thought
+	RelImplements = "implements"  // commit -> requirement
+	RelChanges    = "changes"     // commit -> file/test
+	RelVerifies   = "verifies"    // test -> requirement ("// Verifies:" annotation)
+	RelAllocated  = "allocated"   // requirement -> component ("(Allocated-To: C-001)" annotation)
+)
+
+// Node is a traceable artefact. ID is "<kind>:<key>", e.g. "requirement:SR-001".
+type Node struct {
+	ID    string            `json:"id"`
+	Kind  Kind              `json:"kind"`
+	Label string            `json:"label"`
+	Path  string            `json:"path,omitempty"`
+	Line  int               `json:"line,omitempty"`
+	Attrs map[string]string `json:"attrs,omitempty"`
+}
+
+// Edge is a directed, labelled link between two nodes.
+type Edge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	Rel  string `json:"rel"`
+}
+
+// Graph is the traceability graph. Nodes and edges keep insertion order.
+type Graph struct {
+	Nodes []*Node `json:"nodes"`
+	Edges []Edge  `json:"edges"`
+	// Dangling lists references to IDs that are not defined anywhere.
+	Dangling []string `json:"dangling,omitempty"`
+
+	byID  map[string]*Node
+	edges map[Edge]bool
+	tests []TestRef
+}
+
+// Tests returns the annotated tests scanned by Build; files that failed to
+// parse are among Build's errors.
+func (g *Graph) Tests() []TestRef { return g.tests }
+
+// NewGraph returns an empty graph.
+func NewGraph() *Graph {
+	return &Graph{byID: make(map[string]*Node), edges: make(map[Edge]bool)}
+}
+
+// NodeID builds the node ID for kind and key.
+func NodeID(kind Kind, key string) string { return string(kind) + ":" + key }
+
+// AddNode inserts n unless a node with the same ID exists; the stored node is returned.
+func (g *Graph) AddNode(n Node) *Node {
+	if existing, ok := g.byID[n.ID]; ok {
+		return existing
+	}
+	p := &n
+	g.Nodes = append(g.Nodes, p)
+	g.byID[n.ID] = p
+	return p
+}
+
+// Node returns the node with id, or nil.
+func (g *Graph) Node(id string) *Node { return g.byID[id] }
+
+// AddEdge links two existing nodes; duplicates are ignored.
+func (g *Graph) AddEdge(from, to, rel string) {
+	e := Edge{From: from, To: to, Rel: rel}
+	if g.edges[e] || g.byID[from] == nil || g.byID[to] == nil {
+		return
+	}
+	g.edges[e] = true
+	g.Edges = append(g.Edges, e)
+}
+
+// Out returns the edges leaving id, optionally filtered by relation.
+func (g *Graph) Out(id string, rels ...string) []Edge {
+	return g.filter(func(e Edge) bool { return e.From == id }, rels)
+}
+
+// In returns the edges entering id, optionally filtered by relation.
+func (g *Graph) In(id string, rels ...string) []Edge {
+	return g.filter(func(e Edge) bool { return e.To == id }, rels)
+}
+
+func (g *Graph) filter(match func(Edge) bool, rels []string) []Edge {
+	var out []Edge
+	for _, e := range g.Edges {
+		if !match(e) {
+			continue
+		}
+		if len(rels) == 0 || containsString(rels, e.Rel) {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// NodesOf returns the nodes of a kind in insertion order.
+func (g *Graph) NodesOf(kind Kind) []*Node {
+	var out []*Node
+	for _, n := range g.Nodes {
+		if n.Kind == kind {
+			out = append(out, n)
+		}
+	}
+	return out
+}
+
+// Options locates the inputs of the graph.
+type Options struct {
+	DesignDir   string // e.g. tgs/design
+	ThoughtsDir string // e.g. tgs/thoughts
+	Git         bool   // read commit trailers from git history
+}
+
+// OptionsFromConfig derives Options from context.pack_dir and context.thoughts_dir.
+func OptionsFromConfig(cfg config.Config) Options {
+	design := strings.TrimSpace(cfg.Context.PackDir)
+	if design == "" {
+		design = "tgs/design"
+	}
+	th := strings.TrimSpace(cfg.Context.ThoughtsDir)
+	if th == "" {
+		th = "tgs/thoughts"
+	}
+	return Options{DesignDir: filepath.Clean(design), ThoughtsDir: filepath.Clean(th), Git: true}
+}
+
+// Requirements returns the ID'd items of the requirements document.
+func Requirements(repoRoot string, opts Options) ([]reqs.Item, error) {
+	rel := filepath.ToSlash(filepath.Join(opts.DesignDir, "20_requirements.md"))
+	data, err := os.ReadFile(filepath.Join(repoRoot, rel))
+	if err != nil {
+		return nil, fmt.Errorf("cannot read %s: %w", rel, err)
+	}
+	return reqs.ScanItems(rel, string(data)), nil
+}
+
+// Build reads the design documents, thoughts and (optionally) git history under
+// repoRoot. Missing inputs are returned as errors; the graph holds what was found.
+func Build(repoRoot string, opts Options) (*Graph, []error) {
+	g := NewGraph()
+	var errs []error
+	read := func(rel string) (string, bool) {
+		data, err := os.ReadFile(filepath.Join(repoRoot, rel))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("cannot read %s: %w", rel, err))
+			return "", false
+		}
+		return string(data), true
+	}
+
+	needsPath := filepath.ToSlash(filepath.Join(opts.DesignDir, "10_needs.md"))
+	if doc, ok := read(needsPath); ok {
+		for _, it := range reqs.ScanItems(needsPath, doc) {
+			g.AddNode(Node{ID: NodeID(KindNeed, it.ID), Kind: KindNeed, Label: it.ID, Path: it.Path, Line: it.Line, Attrs: map[string]string{"text": it.Text}})
+		}
+	}
+	// The architecture document is optional; components are only known once declared.
+	archPath := filepath.ToSlash(filepath.Join(opts.DesignDir, "30_architecture.md"))
+	if data, err := os.ReadFile(filepath.Join(repoRoot, archPath)); err == nil {
+		for _, c := range ScanComponents(repoRoot, archPath, string(data)) {
+			g.AddNode(Node{ID: NodeID(KindComponent, c.ID), Kind: KindComponent, Label: c.ID, Path: c.Path, Line: c.Line,
+				Attrs: map[string]string{"text": c.Text, "name": c.Name, "paths": strings.Join(c.Paths, ",")}})
+		}
+	}
+	reqPath := filepath.ToSlash(filepath.Join(opts.DesignDir, "20_requirements.md"))
+	if doc, ok := read(reqPath); ok {
+		for _, it := range reqs.ScanItems(reqPath, doc) {
+			attrs := map[string]string{"text": it.Text}
+			if it.Verification != "" {
+				attrs["verification"] = it.Verification
+			}
+			id := NodeID(KindRequirement, it.ID)
+			g.AddNode(Node{ID: id, Kind: KindRequirement, Label: it.ID, Path: it.Path, Line: it.Line, Attrs: attrs})
+			for _, p := range it.Parents {
+				if g.Node(NodeID(KindNeed, p)) == nil {
+					g.Dangling = append(g.Dangling, fmt.Sprintf("%s:%d: %s traces unknown need %s", it.Path, it.Line, it.ID, p))
+					continue
+				}
+				g.AddEdge(NodeID(KindNeed, p), id, RelRefines)
+			}
+			var unknown []string
+			for _, c := range it.AllocatedTo {
+				if g.Node(NodeID(KindComponent, c)) == nil {
+					g.Dangling = append(g.Dangling, fmt.Sprintf("%s:%d: %s is allocated to unknown component %s", it.Path, it.Line, it.ID, c))
+					unknown = append(unknown, c)
+					continue
+				}
+				g.AddEdge(id, NodeID(KindComponent, c), RelAllocated)
+			}
+			if len(unknown) > 0 {
+				attrs["unknown_components"] = strings.Join(unknown, ",")
+			}
+		}
+	}
+	vnvPath := filepath.ToSlash(filepath.Join(opts.DesignDir, "40_vnv.md"))
+	if doc, ok := read(vnvPath); ok {
+		for _, row := range ParseVnV(vnvPath, doc) {
+			reqID := NodeID(KindRequirement, row.ReqID)
+			if g.Node(reqID) == nil {
+				g.Dangling = append(g.Dangling, fmt.Sprintf("%s:%d: V&V row for unknown requirement %s", row.Path, row.Line, row.ReqID))
+				continue
+			}
+			id := NodeID(KindVnV, fmt.Sprintf("%s:%d", row.Path, row.Line))
+			g.AddNode(Node{ID: id, Kind: KindVnV, Label: row.ReqID + " " + row.Method, Path: row.Path, Line: row.Line,
+				Attrs: map[string]string{"method": row.Method, "criteria": row.Criteria, "artifact": row.Artifact}})
+			g.AddEdge(reqID, id, RelVerifiedBy)
+			for _, f := range mentionedFiles(repoRoot, "", row.Artifact) {
+				g.AddEdge(id, addFile(g, f), RelArtifact)
+			}
+		}
+	}
+
+	thoughtsDir := filepath.Join(repoRoot, opts.ThoughtsDir)
+	names, err := thoughts.List(thoughtsDir)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	for _, name := range names {
+		rel := filepath.ToSlash(filepath.Join(opts.ThoughtsDir, name))
+		id := NodeID(KindThought, name)
+		g.AddNode(Node{ID: id, Kind: KindThought, Label: name, Path: rel})
+		files, _ := filepath.Glob(filepath.Join(thoughtsDir, name, "*.md"))
+		sort.Strings(files)
+		for _, f := range files {
+			data, err := os.ReadFile(f)
+			if err != nil {
+				continue
+			}
+			text := string(data)
+			for _, ref := range mentionedIDs(text) {
+				for _, kind := range []Kind{KindRequirement, KindNeed} {
+					if g.Node(NodeID(kind, ref)) != nil {
+						g.AddEdge(id, NodeID(kind, ref), RelReferences)
+					}
+				}
+			}
+			for _, p := range mentionedFiles(repoRoot, rel, text) {
+				if strings.HasPrefix(p, filepath.ToSlash(opts.ThoughtsDir)+"/") {
+					continue
+				}
+				g.AddEdge(id, addFile(g, p), RelTouches)
+			}
+		}
+	}
+
+	tests, testErrs := ScanTests(repoRoot)
+	for _, err := range testErrs {
+		errs = append(errs, fmt.Errorf("scan tests: %w", err))
+	}
+	g.tests = tests
+	for _, t := range tests {
+		for _, ref := range t.Verifies {
+			rid := NodeID(KindRequirement, ref)
+			if g.Node(rid) == nil {
+				g.Dangling = append(g.Dangling, fmt.Sprintf("%s:%d: %s verifies unknown requirement %s", t.Path, t.Line, t.Name, ref))
+				continue
+			}
+			g.AddEdge(addFile(g, t.Path), rid, RelVerifies)
+		}
+	}
+
+	if opts.Git {
+		commits, err := readCommits(repoRoot)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		for _, c := range commits {
+			addCommit(g, c, names)
+		}
+	}
+	return g, errs
+}
+
+func addCommit(g *Graph, c Commit, thoughtNames []string) {
+	id := NodeID(KindCommit, c.Short())
+	g.AddNode(Node{ID: id, Kind: KindCommit, Label: c.Short() + " " + c.Subject, Attrs: map[string]string{"hash": c.Hash}})
+	for _, t := range c.Trailers["thought"] {
+		t = filepath.Base(strings.TrimSpace(t))
+		for _, name := range thoughtNames {
+			if name == t || strings.HasPrefix(name, t+"-") {
+				g.AddEdge(id, NodeID(KindThought, name), RelPartOf)
+			}
+		}
+	}
+	for _, key := range []string{"refs", "implements", "requirement"} {
+		for _, v := range c.Trailers[key] {
+			for _, ref := range splitIDs(v) {
+				rid := NodeID(KindRequirement, ref)
+				if g.Node(rid) == nil {
+					g.Dangling = append(g.Dangling, fmt.Sprintf("commit %s: trailer references unknown requirement %s", c.Short(), ref))
+					continue
+				}
+				g.AddEdge(id, rid, RelImplements)
+			}
+		}
+	}
+	for _, f := range c.Files {
+		g.AddEdge(id, addFile(g, f), RelChanges)
+	}
+}
+
+// addFile adds a file or test node for a repo-relative path and returns its ID.
+func addFile(g *Graph, rel string) string {
+	kind := KindFile
+	if isTestFile(rel) {
+		kind = KindTest
+	}
+	id := NodeID(kind, rel)
+	g.AddNode(Node{ID: id, Kind: kind, Label: rel, Path: rel})
+	return id
+}
+
+func isTestFile(rel string) bool {
+	return strings.HasSuffix(rel, "_test.go") || strings.Contains(rel, "/testdata/")
+}
+
+// FileNodeID returns the node ID a repo-relative path would have in the graph.
+func FileNodeID(rel string) string {
+	rel = filepath.ToSlash(filepath.Clean(rel))
+	if isTestFile(rel) {
+		return NodeID(KindTest, rel)
+	}
+	return NodeID(KindFile, rel)
+}
+
+var (
+	idMentionRe   = regexp.MustCompile(`\b[A-Z][A-Z0-9]*-[0-9]{2,}\b`)
+	codeSpanRe    = regexp.MustCompile("`([^`\\s]+)`")
+	mdLinkRe      = regexp.MustCompile(`\]\(([^)\s#]+)(?:#[^)]*)?\)`)
+	lineSuffixRe  = regexp.MustCompile(`(:[0-9]+(-[0-9]+)?)+$`)
+	trailingPunct = ".,;:"
+)
+
+// mentionedIDs returns the distinct ID-like tokens in text, e.g. SR-001 or N-012.
+func mentionedIDs(text string) []string {
+	seen := map[string]bool{}
+	var out []string
+	for _, m := range idMentionRe.FindAllString(text, -1) {
+		if !seen[m] {
+			seen[m] = true
+			out = append(out, m)
+		}
+	}
+	return out
+}
+
+// mentionedFiles returns repo-relative paths of existing files named in code spans
+// or Markdown links. Links are resolved relative to baseDir (repo-relative).
+func mentionedFiles(repoRoot, baseDir, text string) []string {
+	seen := map[string]bool{}
+	var out []string
+	try := func(p string, relative bool) {
+		p = strings.TrimRight(lineSuffixRe.ReplaceAllString(p, ""), trailingPunct)
+		if p == "" || strings.Contains(p, "://") || strings.ContainsAny(p, "*<>{}$") {
+			return
+		}
+		if relative && baseDir != "" && !strings.HasPrefix(p, "/") {
+			p = filepath.Join(baseDir, p)
+		}
+		p = filepath.ToSlash(filepath.Clean(strings.TrimPrefix(p, "/")))
+		if seen[p] || strings.HasPrefix(p, "..") {
+			return
+		}
+		st, err := os.Stat(filepath.Join(repoRoot, p))
+		if err != nil || st.IsDir() {
+			return
+		}
+		seen[p] = true
+		out = append(out, p)
+	}
+	for _, m := range codeSpanRe.FindAllStringSubmatch(text, -1) {
+		try(m[1], false)
+	}
+	for _, m := range mdLinkRe.FindAllStringSubmatch(text, -1) {
+		try(m[1], true)
+	}
+	return out
+}
+
+func splitIDs(v string) []string {
+	return strings.FieldsFunc(v, func(r rune) bool { return r == ',' || r == ';' || r == ' ' || r == '\t' })
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,110 @@
+package trace
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeRepo(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for rel, content := range files {
+		if err := writeFileAt(dir, rel, content); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func writeFileAt(root, rel, content string) error {
+	path := filepath.Join(root, rel)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(content), 0o644)
+}
+
+func sampleRepo(t *testing.T) string {
+	return writeRepo(t, map[string]string{
+		"tgs/design/10_needs.md": "# Needs\n\n- **N-001**: Users need briefs.\n- **N-002**: Users need audits.\n",
+		"tgs/design/20_requirements.md": "# Requirements\n\n" +
+			"- **SR-001**: When a brief is requested, the system shall collect docs. (Traces: N-001) (Verification: Test)\n" +
+			"- **SR-002**: The system shall log runs. (Traces: N-009)\n",
+		"tgs/design/40_vnv.md": "| Req ID | Method | Acceptance Criteria | Artifact/Test |\n|---|---|---|---|\n" +
+			"| SR-001 | T | Brief lists docs | `src/brief_test.go` |\n| SR-404 | T | Nothing | - |\n",
+		"tgs/thoughts/abc1234-brief/plan.md": "Implements SR-001 in `src/brief.go`.\n",
+		"src/brief.go":                       "package src\n",
+		"src/brief_test.go":                  "package src\n",
+	})
+}
+
+func TestBuild_LinksAndOrphans(t *testing.T) {
+	root := sampleRepo(t)
+	g, errs := Build(root, Options{DesignDir: "tgs/design", ThoughtsDir: "tgs/thoughts"})
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	for _, e := range []Edge{
+		{"need:N-001", "requirement:SR-001", RelRefines},
+		{"requirement:SR-001", "vnv:tgs/design/40_vnv.md:3", RelVerifiedBy},
+		{"vnv:tgs/design/40_vnv.md:3", "test:src/brief_test.go", RelArtifact},
+		{"thought:abc1234-brief", "requirement:SR-001", RelReferences},
+		{"thought:abc1234-brief", "file:src/brief.go", RelTouches},
+	} {
+		if !g.edges[e] {
+			t.Errorf("missing edge %+v", e)
+		}
+	}
+	o := g.Orphans()
+	if strings.Join(o.RequirementsWithoutNeed, ",") != "SR-002" ||
+		strings.Join(o.NeedsWithoutRequirement, ",") != "N-002" ||
+		strings.Join(o.RequirementsWithoutVerification, ",") != "SR-002" {
+		t.Fatalf("unexpected orphans: %+v", o)
+	}
+	if len(o.Dangling) != 2 || !strings.Contains(o.Dangling[0], "N-009") || !strings.Contains(o.Dangling[1], "SR-404") {
+		t.Fatalf("unexpected dangling: %v", o.Dangling)
+	}
+}
+
+func TestAddCommit_Trailers(t *testing.T) {
+	g, _ := Build(sampleRepo(t), Options{DesignDir: "tgs/design", ThoughtsDir: "tgs/thoughts"})
+	c := Commit{
+		Hash:     "0123456789abcdef",
+		Subject:  "Collect docs",
+		Trailers: ParseTrailers("Thought: abc1234\nRefs: SR-001, SR-777\n"),
+		Files:    []string{"src/brief.go"},
+	}
+	addCommit(g, c, []string{"abc1234-brief"})
+	for _, e := range []Edge{
+		{"commit:0123456", "thought:abc1234-brief", RelPartOf},
+		{"commit:0123456", "requirement:SR-001", RelImplements},
+		{"commit:0123456", "file:src/brief.go", RelChanges},
+	} {
+		if !g.edges[e] {
+			t.Errorf("missing edge %+v", e)
+		}
+	}
+	if d := g.Dangling[len(g.Dangling)-1]; !strings.Contains(d, "SR-777") {
+		t.Fatalf("expected dangling SR-777, got %q", d)
+	}
+}
+
+func TestRender_DOTAndMermaid(t *testing.T) {
+	g, _ := Build(sampleRepo(t), Options{DesignDir: "tgs/design", ThoughtsDir: "tgs/thoughts"})
+	var dot, mmd bytes.Buffer
+	if err := g.WriteDOT(&dot); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.WriteMermaid(&mmd); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(dot.String(), `"need:N-001" -> "requirement:SR-001" [label="refines"]`) {
+		t.Fatalf("unexpected DOT:\n%s", dot.String())
+	}
+	if !strings.HasPrefix(mmd.String(), "flowchart LR\n") || !strings.Contains(mmd.String(), "-->|refines| n2_SR_001") {
+		t.Fatalf("unexpected Mermaid:\n%s", mmd.String())
+	}
+}
@@ -0,0 +1,160 @@
+package trace
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// ImpactedRequirement is a requirement reachable from a changed path.
+type ImpactedRequirement struct {
+	ID   string `json:"id"`
+	Path string `json:"path"`
+	Line int    `json:"line"`
+	Text string `json:"text,omitempty"`
+	// Reasons explain each link from a changed path, e.g. "thought 4b5a2a8-x mentions src/a.go".
+	Reasons []string `json:"reasons"`
+	// Thoughts that reference the requirement or carry commits implementing it.
+	Thoughts []string `json:"thoughts,omitempty"`
+	// Tests verifying the requirement, as "path:TestName" or a V&V artifact path.
+	Tests []string `json:"tests,omitempty"`
+}
+
+// ImpactReport is the result of Graph.Impact.
+type ImpactReport struct {
+	Paths        []string              `json:"paths"`
+	Requirements []ImpactedRequirement `json:"requirements"`
+	// Unmapped lists changed paths with no traceability link.
+	Unmapped []string `json:"unmapped,omitempty"`
+}
+
+// Impact walks the graph backwards from changed repo-relative paths to the
+// requirements they may affect: thoughts mentioning a file, commits that
+// changed it, tests annotated with "// Verifies:", V&V artifacts and the
+// architecture components the file belongs to.
+func (g *Graph) Impact(paths []string) ImpactReport {
+	rep := ImpactReport{}
+	reasons := make(map[string][]string)
+	add := func(reqNode, reason string) {
+		if n := g.Node(reqNode); n == nil || n.Kind != KindRequirement {
+			return
+		}
+		if !containsString(reasons[reqNode], reason) {
+			reasons[reqNode] = append(reasons[reqNode], reason)
+		}
+	}
+	for _, p := range paths {
+		p = filepath.ToSlash(filepath.Clean(p))
+		rep.Paths = append(rep.Paths, p)
+		hits := 0
+		count := func(reqNode, reason string) {
+			n := len(reasons[reqNode])
+			add(reqNode, reason)
+			if len(reasons[reqNode]) > n {
+				hits++
+			}
+		}
+		for _, t := range g.NodesOf(KindThought) {
+			if strings.HasPrefix(p, t.Path+"/") {
+				for _, e := range g.Out(t.ID, RelReferences) {
+					count(e.To, fmt.Sprintf("thought %s changed (%s)", t.Label, p))
+				}
+			}
+		}
+		for _, c := range g.NodesOf(KindComponent) {
+			if !componentOwns(c, p) {
+				continue
+			}
+			for _, e := range g.In(c.ID, RelAllocated) {
+				count(e.From, fmt.Sprintf("%s belongs to component %s", p, c.Label))
+			}
+		}
+		id := FileNodeID(p)
+		if g.Node(id) != nil {
+			for _, e := range g.In(id, RelTouches) {
+				for _, r := range g.Out(e.From, RelReferences) {
+					count(r.To, fmt.Sprintf("thought %s mentions %s", g.Node(e.From).Label, p))
+				}
+			}
+			for _, e := range g.In(id, RelChanges) {
+				c := g.Node(e.From)
+				for _, r := range g.Out(c.ID, RelImplements) {
+					count(r.To, fmt.Sprintf("commit %s changed %s", strings.TrimPrefix(c.ID, string(KindCommit)+":"), p))
+				}
+				for _, th := range g.Out(c.ID, RelPartOf) {
+					for _, r := range g.Out(th.To, RelReferences) {
+						count(r.To, fmt.Sprintf("thought %s changed %s", g.Node(th.To).Label, p))
+					}
+				}
+			}
+			for _, e := range g.Out(id, RelVerifies) {
+				count(e.To, fmt.Sprintf("%s verifies it", p))
+			}
+			for _, e := range g.In(id, RelArtifact) {
+				for _, r := range g.In(e.From, RelVerifiedBy) {
+					count(r.From, fmt.Sprintf("%s is a V&V artifact", p))
+				}
+			}
+		}
+		if hits == 0 {
+			rep.Unmapped = append(rep.Unmapped, p)
+		}
+	}
+
+	for _, n := range g.NodesOf(KindRequirement) {
+		rs, ok := reasons[n.ID]
+		if !ok {
+			continue
+		}
+		ir := ImpactedRequirement{ID: n.Label, Path: n.Path, Line: n.Line, Text: n.Attrs["text"], Reasons: rs}
+		for _, e := range g.In(n.ID, RelReferences) {
+			ir.Thoughts = appendUnique(ir.Thoughts, g.Node(e.From).Label)
+		}
+		for _, e := range g.In(n.ID, RelImplements) {
+			for _, th := range g.Out(e.From, RelPartOf) {
+				ir.Thoughts = appendUnique(ir.Thoughts, g.Node(th.To).Label)
+			}
+		}
+		for _, t := range g.tests {
+			if containsString(t.Verifies, n.Label) {
+				ir.Tests = appendUnique(ir.Tests, t.Path+":"+t.Name)
+			}
+		}
+		for _, v := range g.Out(n.ID, RelVerifiedBy) {
+			for _, a := range g.Out(v.To, RelArtifact) {
+				if n := g.Node(a.To); n.Kind == KindTest && !hasPrefix(ir.Tests, n.Path+":") {
+					ir.Tests = appendUnique(ir.Tests, n.Path)
+				}
+			}
+		}
+		rep.Requirements = append(rep.Requirements, ir)
+	}
+	return rep
+}
+
+// componentOwns reports whether p is one of the component's paths or lies under one.
+func componentOwns(c *Node, p string) bool {
+	for _, cp := range strings.Split(c.Attrs["paths"], ",") {
+		cp = strings.TrimSuffix(cp, "/")
+		if cp != "" && cp != "." && (p == cp || strings.HasPrefix(p, cp+"/")) {
+			return true
+		}
+	}
+	return false
+}
+
+func appendUnique(list []string, s string) []string {
+	if containsString(list, s) {
+		return list
+	}
+	return append(list, s)
+}
+
+func hasPrefix(list []string, prefix string) bool {
+	for _, v := range list {
+		if strings.HasPrefix(v, prefix) {
+			return true
+		}
+	}
+	return false
+}
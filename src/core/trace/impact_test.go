@@ -0,0 +1,38 @@
+package trace
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestImpact_FromChangedPaths(t *testing.T) {
+	root := sampleRepo(t)
+	if err := writeFileAt(root, "src/siren_test.go", "package src\n\nimport \"testing\"\n\n// Verifies: SR-002\nfunc TestLog(t *testing.T) {}\n"); err != nil {
+		t.Fatal(err)
+	}
+	g, errs := Build(root, Options{DesignDir: "tgs/design", ThoughtsDir: "tgs/thoughts"})
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+
+	rep := g.Impact([]string{"src/brief.go", "./src/siren_test.go", "docs/unrelated.md"})
+	if len(rep.Requirements) != 2 {
+		t.Fatalf("expected SR-001 and SR-002, got %+v", rep.Requirements)
+	}
+	sr1, sr2 := rep.Requirements[0], rep.Requirements[1]
+	if sr1.ID != "SR-001" || !strings.Contains(sr1.Reasons[0], "abc1234-brief mentions src/brief.go") ||
+		strings.Join(sr1.Thoughts, ",") != "abc1234-brief" || strings.Join(sr1.Tests, ",") != "src/brief_test.go" {
+		t.Fatalf("unexpected SR-001 impact: %+v", sr1)
+	}
+	if sr2.ID != "SR-002" || strings.Join(sr2.Tests, ",") != "src/siren_test.go:TestLog" {
+		t.Fatalf("unexpected SR-002 impact: %+v", sr2)
+	}
+	if strings.Join(rep.Unmapped, ",") != "docs/unrelated.md" {
+		t.Fatalf("unexpected unmapped: %v", rep.Unmapped)
+	}
+
+	// Editing a thought document affects the requirements it references
+	if rep := g.Impact([]string{"tgs/thoughts/abc1234-brief/plan.md"}); len(rep.Requirements) != 1 || rep.Requirements[0].ID != "SR-001" {
+		t.Fatalf("unexpected thought impact: %+v", rep)
+	}
+}
@@ -0,0 +1,91 @@
+package trace
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// Orphans lists gaps in the traceability chain.
+type Orphans struct {
+	RequirementsWithoutNeed         []string `json:"requirements_without_need"`
+	NeedsWithoutRequirement         []string `json:"needs_without_requirement"`
+	RequirementsWithoutVerification []string `json:"requirements_without_verification"`
+	Dangling                        []string `json:"dangling"`
+}
+
+// Count returns the total number of findings.
+func (o Orphans) Count() int {
+	return len(o.RequirementsWithoutNeed) + len(o.NeedsWithoutRequirement) +
+		len(o.RequirementsWithoutVerification) + len(o.Dangling)
+}
+
+// Orphans reports requirements not refining any need, needs without requirements,
+// requirements without a V&V row and references to undefined IDs.
+func (g *Graph) Orphans() Orphans {
+	o := Orphans{Dangling: append([]string{}, g.Dangling...)}
+	for _, n := range g.NodesOf(KindRequirement) {
+		if len(g.In(n.ID, RelRefines)) == 0 {
+			o.RequirementsWithoutNeed = append(o.RequirementsWithoutNeed, n.Label)
+		}
+		if len(g.Out(n.ID, RelVerifiedBy)) == 0 {
+			o.RequirementsWithoutVerification = append(o.RequirementsWithoutVerification, n.Label)
+		}
+	}
+	for _, n := range g.NodesOf(KindNeed) {
+		if len(g.Out(n.ID, RelRefines)) == 0 {
+			o.NeedsWithoutRequirement = append(o.NeedsWithoutRequirement, n.Label)
+		}
+	}
+	return o
+}
+
+var dotShapes = map[Kind]string{
+	KindNeed:        "hexagon",
+	KindRequirement: "box",
+	KindVnV:         "note",
+	KindThought:     "folder",
+	KindCommit:      "circle",
+	KindFile:        "component",
+	KindTest:        "component",
+	KindComponent:   "box3d",
+}
+
+// WriteDOT renders g in Graphviz DOT format.
+func (g *Graph) WriteDOT(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("digraph trace {\n  rankdir=LR;\n  node [fontname=\"Helvetica\"];\n")
+	for _, n := range g.Nodes {
+		fmt.Fprintf(&b, "  %q [label=%q, shape=%s];\n", n.ID, n.Label, dotShapes[n.Kind])
+	}
+	for _, e := range g.Edges {
+		fmt.Fprintf(&b, "  %q -> %q [label=%q];\n", e.From, e.To, e.Rel)
+	}
+	b.WriteString("}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+var mermaidIDRe = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+// WriteMermaid renders g as a Mermaid flowchart.
+func (g *Graph) WriteMermaid(w io.Writer) error {
+	ids := make(map[string]string, len(g.Nodes))
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+	for i, n := range g.Nodes {
+		id := fmt.Sprintf("n%d_%s", i, mermaidIDRe.ReplaceAllString(n.Label, "_"))
+		if len(id) > 40 {
+			id = id[:40]
+		}
+		ids[n.ID] = id
+		label := strings.ReplaceAll(n.Label, `"`, "#quot;")
+		fmt.Fprintf(&b, "  %s[\"%s: %s\"]\n", id, n.Kind, label)
+	}
+	for _, e := range g.Edges {
+		fmt.Fprintf(&b, "  %s -->|%s| %s\n", ids[e.From], e.Rel, ids[e.To])
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,138 @@
+package trace
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/kelvin/tgsflow/src/core/ears"
+	"github.com/kelvin/tgsflow/src/core/reqs"
+)
+
+var nonIdentRe = regexp.MustCompile(`[^A-Za-z0-9]+`)
+
+// TestFuncName returns the Go test name for a requirement ID, e.g. TestSR001.
+func TestFuncName(id string) string { return "Test" + nonIdentRe.ReplaceAllString(id, "") }
+
+// TestFileName returns the skeleton file name for a requirement ID, e.g. sr_001_test.go.
+func TestFileName(id string) string {
+	return strings.Trim(nonIdentRe.ReplaceAllString(strings.ToLower(id), "_"), "_") + "_test.go"
+}
+
+// PackageName returns the package clause of the non-test Go files in dir,
+// falling back to the directory name.
+func PackageName(dir string) string {
+	files, _ := filepath.Glob(filepath.Join(dir, "*.go"))
+	fset := token.NewFileSet()
+	for _, f := range files {
+		if strings.HasSuffix(f, "_test.go") {
+			continue
+		}
+		if file, err := parser.ParseFile(fset, f, nil, parser.PackageClauseOnly); err == nil {
+			return file.Name.Name
+		}
+	}
+	name := nonIdentRe.ReplaceAllString(filepath.Base(filepath.Clean(dir)), "")
+	if name == "" || name[0] >= '0' && name[0] <= '9' {
+		return "main"
+	}
+	return strings.ToLower(name)
+}
+
+// GoTestSkeleton renders a table-driven test for r with a Verifies annotation
+// and its EARS clauses as comments. Cases are skipped until implemented.
+func GoTestSkeleton(pkg string, r reqs.Requirement) ([]byte, error) {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "package %s\n\nimport \"testing\"\n\n", pkg)
+	fmt.Fprintf(&b, "// Verifies: %s\n//\n// %s\n", r.ID, r.Text)
+	res := r.Result
+	if r.Valid() {
+		fmt.Fprintf(&b, "//\n//\tShape:  %s\n//\tSystem: %s\n", res.Shape, res.System)
+		for _, p := range res.Preconditions {
+			fmt.Fprintf(&b, "//\tWhile:  %s\n", p)
+		}
+		if res.Trigger != "" {
+			kw := "When:  "
+			if res.Shape == ears.ShapeUnwanted {
+				kw = "If:    "
+			}
+			fmt.Fprintf(&b, "//\t%s %s\n", kw, res.Trigger)
+		}
+		modal := "shall"
+		if res.Polarity == ears.PolarityNegative {
+			modal = "shall not"
+		}
+		fmt.Fprintf(&b, "//\tThen:   %s %s\n", modal, res.Response)
+	}
+	fmt.Fprintf(&b, "func %s(t *testing.T) {\n", TestFuncName(r.ID))
+	b.WriteString("\ttests := []struct {\n\t\tname string\n\t}{\n")
+	for _, name := range skeletonCases(r) {
+		fmt.Fprintf(&b, "\t\t{name: %s},\n", strconv.Quote(name))
+	}
+	b.WriteString("\t}\n\tfor _, tt := range tests {\n\t\tt.Run(tt.name, func(t *testing.T) {\n")
+	fmt.Fprintf(&b, "\t\t\tt.Skip(%s)\n", strconv.Quote("TODO: verify "+r.ID))
+	b.WriteString("\t\t})\n\t}\n}\n")
+	return format.Source(b.Bytes())
+}
+
+// skeletonCases names one case for the stated behaviour and, for conditional
+// requirements, one for the condition not holding.
+func skeletonCases(r reqs.Requirement) []string {
+	res := r.Result
+	if !r.Valid() {
+		return []string{"nominal"}
+	}
+	cond := res.Trigger
+	if cond == "" && len(res.Preconditions) > 0 {
+		cond = strings.Join(res.Preconditions, " and ")
+	}
+	if cond == "" {
+		return []string{res.Response}
+	}
+	return []string{cond, "not " + cond}
+}
+
+// MissingTestRequirements returns the requirements marked "(Verification: Test)"
+// that no annotated test in repoRoot verifies yet.
+func MissingTestRequirements(list []reqs.Requirement, tests []TestRef) []reqs.Requirement {
+	covered := make(map[string]bool)
+	for _, t := range tests {
+		for _, id := range t.Verifies {
+			covered[id] = true
+		}
+	}
+	var out []reqs.Requirement
+	seen := make(map[string]bool)
+	for _, r := range list {
+		if r.ID == "" || !strings.EqualFold(r.Verification, "Test") || covered[r.ID] || seen[r.ID] {
+			continue
+		}
+		seen[r.ID] = true
+		out = append(out, r)
+	}
+	return out
+}
+
+// WriteSkeleton writes the skeleton for r into dir unless the file exists; it
+// returns the path and whether a file was written.
+func WriteSkeleton(dir, pkg string, r reqs.Requirement) (string, bool, error) {
+	path := filepath.Join(dir, TestFileName(r.ID))
+	if _, err := os.Stat(path); err == nil {
+		return path, false, nil
+	}
+	src, err := GoTestSkeleton(pkg, r)
+	if err != nil {
+		return path, false, err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return path, false, err
+	}
+	return path, true, os.WriteFile(path, src, 0o644)
+}
@@ -0,0 +1,48 @@
+package trace
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/kelvin/tgsflow/src/core/reqs"
+)
+
+func TestGoTestSkeleton_AnnotatedAndCovered(t *testing.T) {
+	list := reqs.Scan("20_requirements.md",
+		"- **SR-001**: While armed, when motion is detected, the alarm shall sound the siren. (Verification: Test)\n"+
+			"- **SR-002**: The alarm shall log events. (Verification: Inspection)\n", true)
+	missing := MissingTestRequirements(list, nil)
+	if len(missing) != 1 || missing[0].ID != "SR-001" {
+		t.Fatalf("expected only SR-001 to need a test, got %+v", missing)
+	}
+	src, err := GoTestSkeleton("alarm", missing[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"package alarm", "// Verifies: SR-001", "//\tWhile:  armed", "//\tWhen:   motion is detected", "func TestSR001(t *testing.T)", `{name: "not motion is detected"}`} {
+		if !strings.Contains(string(src), want) {
+			t.Fatalf("skeleton missing %q:\n%s", want, src)
+		}
+	}
+
+	root := writeRepo(t, map[string]string{"alarm/alarm.go": "package alarm\n"})
+	path, ok, err := WriteSkeleton(root+"/alarm", PackageName(root+"/alarm"), missing[0])
+	if err != nil || !ok || !strings.HasSuffix(path, "sr_001_test.go") {
+		t.Fatalf("WriteSkeleton = %s, %v, %v", path, ok, err)
+	}
+	// The written skeleton counts as coverage, so nothing is left to scaffold
+	tests, errs := ScanTests(root)
+	if len(errs) > 0 {
+		t.Fatal(errs)
+	}
+	if left := MissingTestRequirements(list, tests); len(left) != 0 {
+		t.Fatalf("expected SR-001 covered, got %+v", left)
+	}
+	if _, ok, _ := WriteSkeleton(root+"/alarm", "alarm", missing[0]); ok {
+		t.Fatal("existing skeleton must not be overwritten")
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Fatal(err)
+	}
+}
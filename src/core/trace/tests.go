@@ -0,0 +1,161 @@
+package trace
+
+import (
+	"bufio"
+	"encoding/json"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// TestRef is a Go test function that declares the requirements it verifies with
+// a doc comment line such as:
+//
+//	// Verifies: SR-026, NFR-003
+//	func TestVerify_EARS_DesignDocs_Valid(t *testing.T) { ... }
+type TestRef struct {
+	Name     string   `json:"name"`
+	Package  string   `json:"package"` // import path when go.mod is found, else the repo-relative dir
+	Path     string   `json:"path"`
+	Line     int      `json:"line"`
+	Verifies []string `json:"verifies"`
+}
+
+var verifiesRe = regexp.MustCompile(`^\s*Verifies:\s*(.+)$`)
+
+// ScanTests parses every _test.go file under repoRoot and returns the test
+// functions carrying a Verifies annotation. Hidden, vendor and testdata
+// directories are skipped. A file that does not parse is skipped and reported
+// in errs; the other files are still scanned.
+func ScanTests(repoRoot string) ([]TestRef, []error) {
+	module := modulePath(repoRoot)
+	var (
+		out  []TestRef
+		errs []error
+	)
+	fset := token.NewFileSet()
+	err := filepath.WalkDir(repoRoot, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			name := d.Name()
+			if path != repoRoot && (strings.HasPrefix(name, ".") || name == "vendor" || name == "testdata" || name == "node_modules") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(path, "_test.go") {
+			return nil
+		}
+		file, err := parser.ParseFile(fset, path, nil, parser.ParseComments|parser.SkipObjectResolution)
+		if err != nil {
+			errs = append(errs, err)
+			return nil
+		}
+		rel, _ := filepath.Rel(repoRoot, path)
+		rel = filepath.ToSlash(rel)
+		pkg := filepath.ToSlash(filepath.Dir(rel))
+		if module != "" {
+			pkg = strings.TrimSuffix(module+"/"+pkg, "/.")
+		}
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Recv != nil || fn.Doc == nil || !isTestFunc(fn.Name.Name) {
+				continue
+			}
+			var ids []string
+			for _, c := range fn.Doc.List {
+				if m := verifiesRe.FindStringSubmatch(strings.TrimPrefix(c.Text, "//")); m != nil {
+					ids = append(ids, splitIDs(m[1])...)
+				}
+			}
+			if len(ids) == 0 {
+				continue
+			}
+			out = append(out, TestRef{Name: fn.Name.Name, Package: pkg, Path: rel, Line: fset.Position(fn.Pos()).Line, Verifies: ids})
+		}
+		return nil
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+	return out, errs
+}
+
+func isTestFunc(name string) bool {
+	for _, prefix := range []string{"Test", "Benchmark", "Example", "Fuzz"} {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// modulePath returns the module path declared in repoRoot/go.mod, or "".
+func modulePath(repoRoot string) string {
+	data, err := os.ReadFile(filepath.Join(repoRoot, "go.mod"))
+	if err != nil {
+		return ""
+	}
+	for _, ln := range strings.Split(string(data), "\n") {
+		if f := strings.Fields(ln); len(f) >= 2 && f[0] == "module" {
+			return strings.Trim(f[1], `"`)
+		}
+	}
+	return ""
+}
+
+// TestStatus is the outcome of a test run.
+type TestStatus string
+
+const (
+	TestPass TestStatus = "pass"
+	TestFail TestStatus = "fail"
+	TestSkip TestStatus = "skip"
+)
+
+// TestResults maps "package.TestName" to the outcome reported by `go test -json`.
+type TestResults map[string]TestStatus
+
+// Lookup returns the outcome of t, if it ran.
+func (r TestResults) Lookup(t TestRef) (TestStatus, bool) {
+	s, ok := r[t.Package+"."+t.Name]
+	return s, ok
+}
+
+// ReadTestJSON ingests `go test -json` output. Only top-level test events are
+// recorded; subtest failures already fail their parent.
+func ReadTestJSON(r io.Reader) (TestResults, error) {
+	out := make(TestResults)
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if !strings.HasPrefix(line, "{") {
+			continue // interleaved build output
+		}
+		var ev struct {
+			Action  string
+			Package string
+			Test    string
+		}
+		if err := json.Unmarshal([]byte(line), &ev); err != nil {
+			return out, err
+		}
+		if ev.Test == "" || strings.Contains(ev.Test, "/") {
+			continue
+		}
+		switch TestStatus(ev.Action) {
+		case TestPass, TestFail, TestSkip:
+			out[ev.Package+"."+ev.Test] = TestStatus(ev.Action)
+		}
+	}
+	return out, sc.Err()
+}
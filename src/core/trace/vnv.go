@@ -0,0 +1,50 @@
+package trace
+
+import (
+	"strings"
+)
+
+// VnVRow is one row of the V&V matrix in 40_vnv.md.
+type VnVRow struct {
+	ReqID    string `json:"req_id"`
+	Method   string `json:"method"`
+	Criteria string `json:"criteria"`
+	Artifact string `json:"artifact"`
+	Path     string `json:"path"`
+	Line     int    `json:"line"`
+}
+
+// ParseVnV reads Markdown table rows whose first cell is a requirement ID, e.g.
+//
+//	| SR-001 | I/D | Acceptance criteria | Artifact/Test |
+//
+// Header and separator rows are skipped.
+func ParseVnV(path, doc string) []VnVRow {
+	var out []VnVRow
+	for i, ln := range strings.Split(doc, "\n") {
+		trimmed := strings.TrimSpace(ln)
+		if !strings.HasPrefix(trimmed, "|") {
+			continue
+		}
+		cells := strings.Split(strings.Trim(trimmed, "|"), "|")
+		for j := range cells {
+			cells[j] = strings.TrimSpace(cells[j])
+		}
+		id := strings.Trim(cells[0], "*`")
+		if !idMentionRe.MatchString(id) || idMentionRe.FindString(id) != id {
+			continue
+		}
+		row := VnVRow{ReqID: id, Path: path, Line: i + 1}
+		if len(cells) > 1 {
+			row.Method = cells[1]
+		}
+		if len(cells) > 2 {
+			row.Criteria = cells[2]
+		}
+		if len(cells) > 3 {
+			row.Artifact = strings.Join(cells[3:], " | ")
+		}
+		out = append(out, row)
+	}
+	return out
+}